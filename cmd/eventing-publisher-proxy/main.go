@@ -1,12 +1,16 @@
 package main //nolint:cyclop // it is only starting required instances.
 
 import (
+	"fmt"
 	log "log"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander/fanout"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander/memory"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander/nats"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander/reloadable"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
@@ -18,11 +22,17 @@ import (
 const (
 	backendEventMesh = "beb"
 	backendNATS      = "nats"
+	backendMemory    = "memory"
+	backendFanout    = "fanout"
 )
 
 type Config struct {
-	// Backend used for Eventing. It could be "nats" or "beb".
-	Backend string `envconfig:"BACKEND" required:"true"`
+	// Backend used for Eventing. It could be "nats", "beb", "memory" or "fanout".
+	// It is required unless BackendFile is set.
+	Backend string `envconfig:"BACKEND"`
+
+	// BackendFile selects the backend instead of Backend and switches it at runtime once the file changes.
+	BackendFile string `envconfig:"BACKEND_FILE"`
 
 	// AppLogFormat defines the log format.
 	AppLogFormat string `default:"json" envconfig:"APP_LOG_FORMAT"`
@@ -62,12 +72,11 @@ func main() {
 
 	// Instantiate configured commander.
 	var c commander.Commander
-	switch cfg.Backend {
-	case backendEventMesh:
-		c = eventmesh.NewCommander(opts, metricsCollector, logger)
-	case backendNATS:
-		c = nats.NewCommander(opts, metricsCollector, logger)
-	default:
+	if cfg.BackendFile != "" {
+		c = reloadable.NewCommander(opts, metricsCollector, logger, func(backend string) (commander.Builder, error) {
+			return newCommander(backend, opts, metricsCollector, logger)
+		})
+	} else if c, err = newCommander(cfg.Backend, opts, metricsCollector, logger); err != nil {
 		setupLogger.Fatalf("Invalid publisher backend: %v", cfg.Backend)
 	}
 
@@ -83,7 +92,11 @@ func main() {
 		setupLogger.Infow("Failed to start metrics server", "error", err)
 	}
 
-	setupLogger.Infof("Starting publisher to: %v", cfg.Backend)
+	if cfg.BackendFile != "" {
+		setupLogger.Infof("Starting publisher to the backend of: %v", cfg.BackendFile)
+	} else {
+		setupLogger.Infof("Starting publisher to: %v", cfg.Backend)
+	}
 
 	// Start the commander.
 	if err := c.Start(); err != nil {
@@ -92,3 +105,21 @@ func main() {
 
 	setupLogger.Info("Shutdown the Event Publisher")
 }
+
+// newCommander returns the commander of the given backend.
+func newCommander(backend string, opts *options.Options, metricsCollector *metrics.Collector,
+	logger *emlogger.Logger,
+) (commander.Builder, error) {
+	switch backend {
+	case backendEventMesh:
+		return eventmesh.NewCommander(opts, metricsCollector, logger), nil
+	case backendNATS:
+		return nats.NewCommander(opts, metricsCollector, logger), nil
+	case backendMemory:
+		return memory.NewCommander(opts, metricsCollector, logger), nil
+	case backendFanout:
+		return fanout.NewCommander(opts, metricsCollector, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown backend: %s", backend)
+	}
+}
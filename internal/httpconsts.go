@@ -2,6 +2,8 @@ package internal
 
 const (
 	HeaderContentType                     = "Content-Type"
+	HeaderRetryAfter                      = "Retry-After"
+	HeaderWarning                         = "Warning"
 	ContentTypeApplicationJSON            = "application/json"
 	ContentTypeApplicationCloudEventsJSON = "application/cloudevents+json"
 
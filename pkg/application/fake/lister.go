@@ -14,7 +14,7 @@ import (
 func NewApplicationListerOrDie(ctx context.Context, app *kymaappconnv1alpha1.Application) *application.Lister {
 	scheme := setupSchemeOrDie()
 	dynamicClient := kdynamicfake.NewSimpleDynamicClient(scheme, app)
-	return application.NewLister(ctx, dynamicClient)
+	return application.NewListerOrDie(ctx, dynamicClient)
 }
 
 func setupSchemeOrDie() *runtime.Scheme {
@@ -24,15 +24,29 @@ type Lister struct {
 	lister cache.GenericLister
 }
 
-func NewLister(ctx context.Context, client dynamic.Interface) *Lister {
+// NewLister returns a Lister of the applications and returns an error if its cache does not sync.
+func NewLister(ctx context.Context, client dynamic.Interface) (*Lister, error) {
+	factory, lister := newFactory(client)
+	if err := informers.WaitForCacheSync(ctx, factory); err != nil {
+		return nil, err
+	}
+	return &Lister{lister: lister}, nil
+}
+
+// NewListerOrDie returns a Lister of the applications. If its cache does not sync everything stops.
+func NewListerOrDie(ctx context.Context, client dynamic.Interface) *Lister {
+	factory, lister := newFactory(client)
+	logger, _ := emlogger.New("json", "error")
+	informers.WaitForCacheSyncOrDie(ctx, factory, logger)
+	return &Lister{lister: lister}
+}
+
+func newFactory(client dynamic.Interface) (dynamicinformer.DynamicSharedInformerFactory, cache.GenericLister) {
 	const defaultResync = 10 * time.Second
 	gvr := GroupVersionResource()
 	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, defaultResync, kcorev1.NamespaceAll, nil)
 	factory.ForResource(gvr)
-	lister := factory.ForResource(gvr).Lister()
-	logger, _ := emlogger.New("json", "error")
-	informers.WaitForCacheSyncOrDie(ctx, factory, logger)
-	return &Lister{lister: lister}
+	return factory, factory.ForResource(gvr).Lister()
 }
 
 func (l Lister) Get(name string) (*kymaappconnv1alpha1.Application, error) {
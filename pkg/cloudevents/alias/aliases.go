@@ -0,0 +1,113 @@
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrAliasWithoutType     = errors.New("alias has no type")
+	ErrAliasWithoutNewType  = errors.New("alias has no new type")
+	ErrAliasOfItself        = errors.New("alias maps the type to itself")
+	ErrDuplicateAlias       = errors.New("type has multiple aliases")
+	ErrChainedAlias         = errors.New("new type is deprecated itself")
+	ErrAliasWithoutDeadline = errors.New("alias has no end of the deprecation window")
+)
+
+const (
+	// warningFormat is the format of the Warning header once the deprecation window ended.
+	warningFormat = `299 - "event type %s is deprecated, publish %s instead"`
+	// warningDualPublishFormat is the format of the Warning header during the deprecation window.
+	warningDualPublishFormat = `299 - "event type %s is deprecated, publish %s instead, it is published under both ` +
+		`names until %s"`
+)
+
+// Aliases map deprecated event types to their new types.
+type Aliases struct {
+	Aliases []Alias `json:"aliases"`
+}
+
+// Alias maps a deprecated event type to its new type. The events are published under both types until the end of
+// the deprecation window, afterwards under the new type only.
+type Alias struct {
+	// Type is the deprecated event type as published by the clients.
+	Type string `json:"type"`
+	// NewType is the event type which replaces the deprecated one.
+	NewType string `json:"newType"`
+	// DeprecatedUntil is the end of the deprecation window.
+	DeprecatedUntil time.Time `json:"deprecatedUntil"`
+}
+
+// LoadAliases reads the aliases from the given YAML or JSON file and validates them.
+func LoadAliases(file string) (*Aliases, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event type aliases: %w", err)
+	}
+	aliases := &Aliases{}
+	if err := yaml.UnmarshalStrict(content, aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse event type aliases: %w", err)
+	}
+	if err := aliases.Validate(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// Validate returns an error if an alias is incomplete, if a type has multiple aliases, or if a new type is deprecated
+// itself.
+func (a *Aliases) Validate() error {
+	types := make(map[string]struct{}, len(a.Aliases))
+	for i, alias := range a.Aliases {
+		switch {
+		case alias.Type == "":
+			return fmt.Errorf("alias %d: %w", i, ErrAliasWithoutType)
+		case alias.NewType == "":
+			return fmt.Errorf("alias %s: %w", alias.Type, ErrAliasWithoutNewType)
+		case alias.Type == alias.NewType:
+			return fmt.Errorf("alias %s: %w", alias.Type, ErrAliasOfItself)
+		case alias.DeprecatedUntil.IsZero():
+			return fmt.Errorf("alias %s: %w", alias.Type, ErrAliasWithoutDeadline)
+		}
+		if _, ok := types[alias.Type]; ok {
+			return fmt.Errorf("alias %s: %w", alias.Type, ErrDuplicateAlias)
+		}
+		types[alias.Type] = struct{}{}
+	}
+	for _, alias := range a.Aliases {
+		if _, ok := types[alias.NewType]; ok {
+			return fmt.Errorf("alias %s: %w: %s", alias.Type, ErrChainedAlias, alias.NewType)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the alias of the given event type, if it is deprecated. It can be called on nil Aliases.
+func (a *Aliases) Lookup(eventType string) (Alias, bool) {
+	if a == nil {
+		return Alias{}, false
+	}
+	for _, alias := range a.Aliases {
+		if alias.Type == eventType {
+			return alias, true
+		}
+	}
+	return Alias{}, false
+}
+
+// DualPublish returns true if the events are published under both types at the given time.
+func (a Alias) DualPublish(now time.Time) bool {
+	return now.Before(a.DeprecatedUntil)
+}
+
+// Warning returns the value of the Warning header the clients are answered with at the given time.
+func (a Alias) Warning(now time.Time) string {
+	if a.DualPublish(now) {
+		return fmt.Sprintf(warningDualPublishFormat, a.Type, a.NewType, a.DeprecatedUntil.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf(warningFormat, a.Type, a.NewType)
+}
@@ -0,0 +1,91 @@
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases_Validate(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		givenAliases []Alias
+		wantError    error
+	}{
+		{
+			name:         "should fail without new type",
+			givenAliases: []Alias{{Type: "order.created.v1", DeprecatedUntil: deadline}},
+			wantError:    ErrAliasWithoutNewType,
+		},
+		{
+			name:         "should fail without end of the deprecation window",
+			givenAliases: []Alias{{Type: "order.created.v1", NewType: "order.placed.v1"}},
+			wantError:    ErrAliasWithoutDeadline,
+		},
+		{
+			name: "should fail with multiple aliases of a type",
+			givenAliases: []Alias{
+				{Type: "order.created.v1", NewType: "order.placed.v1", DeprecatedUntil: deadline},
+				{Type: "order.created.v1", NewType: "order.received.v1", DeprecatedUntil: deadline},
+			},
+			wantError: ErrDuplicateAlias,
+		},
+		{
+			name: "should fail with chained aliases",
+			givenAliases: []Alias{
+				{Type: "order.created.v1", NewType: "order.placed.v1", DeprecatedUntil: deadline},
+				{Type: "order.placed.v1", NewType: "order.received.v1", DeprecatedUntil: deadline},
+			},
+			wantError: ErrChainedAlias,
+		},
+		{
+			name:         "should accept valid aliases",
+			givenAliases: []Alias{{Type: "order.created.v1", NewType: "order.placed.v1", DeprecatedUntil: deadline}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			aliases := &Aliases{Aliases: tc.givenAliases}
+			require.ErrorIs(t, aliases.Validate(), tc.wantError)
+		})
+	}
+}
+
+func TestAliases_Lookup(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "aliases.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`aliases:
+- type: order.created.v1
+  newType: order.placed.v1
+  deprecatedUntil: "2030-01-01T00:00:00Z"
+`), 0o600))
+	aliases, err := LoadAliases(file)
+	require.NoError(t, err)
+
+	// when
+	alias, ok := aliases.Lookup("order.created.v1")
+
+	// then
+	require.True(t, ok)
+	assert.Equal(t, "order.placed.v1", alias.NewType)
+	assert.True(t, alias.DualPublish(time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, alias.DualPublish(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, ok = aliases.Lookup("order.placed.v1")
+	assert.False(t, ok)
+	_, ok = (*Aliases)(nil).Lookup("order.created.v1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,55 @@
+package alias
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const registryName = "alias-registry"
+
+// Registry holds the aliases of the deprecated event types and reloads them once their file changes.
+type Registry struct {
+	aliases atomic.Pointer[Aliases]
+	logger  *logger.Logger
+}
+
+// NewRegistry returns a new Registry with the given aliases.
+func NewRegistry(aliases *Aliases, logger *logger.Logger) *Registry {
+	r := &Registry{logger: logger}
+	r.aliases.Store(aliases)
+	return r
+}
+
+// Lookup returns the alias of the given event type, if it is deprecated. It can be called on a nil Registry.
+func (r *Registry) Lookup(eventType string) (Alias, bool) {
+	if r == nil {
+		return Alias{}, false
+	}
+	return r.aliases.Load().Lookup(eventType)
+}
+
+// Watch reloads the aliases from the given file once it changes, until the given context is done.
+// The current aliases are kept if the changed file is invalid.
+func (r *Registry) Watch(ctx context.Context, file string, interval time.Duration) {
+	filewatch.Watch(ctx, []string{file}, interval, func() {
+		aliases, err := LoadAliases(file)
+		if err != nil {
+			r.namedLogger().Errorw("Failed to reload event type aliases, keeping the current aliases", "error", err)
+			return
+		}
+		r.aliases.Store(aliases)
+		r.namedLogger().Infow("Reloaded event type aliases", "aliases", len(aliases.Aliases))
+	}, func(err error) {
+		r.namedLogger().Warnw("Failed to read event type aliases", "error", err)
+	})
+}
+
+func (r *Registry) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(registryName)
+}
@@ -0,0 +1,21 @@
+package builder
+
+import (
+	"context"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+)
+
+type receivedEventKey struct{}
+
+// WithReceivedEvent returns a copy of the given context which carries the event as it was received, before it was
+// built. It allows senders to build the event again for backends with other specifications.
+func WithReceivedEvent(ctx context.Context, event *ceevent.Event) context.Context {
+	return context.WithValue(ctx, receivedEventKey{}, event)
+}
+
+// ReceivedEvent returns the event as it was received, before it was built, if the given context carries it.
+func ReceivedEvent(ctx context.Context) (*ceevent.Event, bool) {
+	event, ok := ctx.Value(receivedEventKey{}).(*ceevent.Event)
+	return event, ok && event != nil
+}
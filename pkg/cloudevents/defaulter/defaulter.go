@@ -0,0 +1,141 @@
+package defaulter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+)
+
+const (
+	// the names of the defaulters of a chain.
+	NameID              = "id"
+	NameTime            = "time"
+	NameDataContentType = "datacontenttype"
+	NameReceivedTime    = "receivedtime"
+
+	// the supported versions of the generated UUIDs.
+	UUIDv4 = "v4"
+	UUIDv7 = "v7"
+
+	// ReceivedTimeExtensionName is the name of the extension which is stamped with the receive time of the events.
+	ReceivedTimeExtensionName = "receivedtime"
+)
+
+var (
+	ErrUnknownDefaulter     = errors.New("unknown defaulter")
+	ErrDuplicateDefaulter   = errors.New("defaulter is configured multiple times")
+	ErrInvalidUUIDVersion   = errors.New("invalid UUID version")
+	ErrEmptyDataContentType = errors.New("default datacontenttype is empty")
+)
+
+type receivedTimeKey struct{}
+
+// WithReceivedTime returns a copy of the given context which carries the receive time of the event.
+func WithReceivedTime(ctx context.Context, receivedTime time.Time) context.Context {
+	return context.WithValue(ctx, receivedTimeKey{}, receivedTime)
+}
+
+// ReceivedTime returns the receive time of the event carried by the given context, or the current time if there is
+// none.
+func ReceivedTime(ctx context.Context) time.Time {
+	if receivedTime, ok := ctx.Value(receivedTimeKey{}).(time.Time); ok {
+		return receivedTime
+	}
+	return time.Now()
+}
+
+// New returns the chain of the defaulters with the given names in order, or nil if there are none. The id defaulter
+// generates UUIDs of the given version, the datacontenttype defaulter sets the given content type.
+func New(names []string, uuidVersion, dataContentType string) (ceclient.EventDefaulter, error) {
+	defaulters := make([]ceclient.EventDefaulter, 0, len(names))
+	configured := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := configured[name]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateDefaulter, name)
+		}
+		configured[name] = struct{}{}
+		switch name {
+		case NameID:
+			defaulter, err := ID(uuidVersion)
+			if err != nil {
+				return nil, err
+			}
+			defaulters = append(defaulters, defaulter)
+		case NameTime:
+			defaulters = append(defaulters, Time)
+		case NameDataContentType:
+			if dataContentType == "" {
+				return nil, ErrEmptyDataContentType
+			}
+			defaulters = append(defaulters, DataContentType(dataContentType))
+		case NameReceivedTime:
+			defaulters = append(defaulters, ReceivedTimeExtension)
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownDefaulter, name)
+		}
+	}
+	if len(defaulters) == 0 {
+		return nil, nil
+	}
+	return Chain(defaulters...), nil
+}
+
+// Chain returns a defaulter which applies the given defaulters in order.
+func Chain(defaulters ...ceclient.EventDefaulter) ceclient.EventDefaulter {
+	return func(ctx context.Context, event ceevent.Event) ceevent.Event {
+		for _, defaulter := range defaulters {
+			event = defaulter(ctx, event)
+		}
+		return event
+	}
+}
+
+// ID returns a defaulter which sets a generated UUID of the given version as ID of the events without an ID.
+func ID(uuidVersion string) (ceclient.EventDefaulter, error) {
+	var generate func() uuid.UUID
+	switch uuidVersion {
+	case UUIDv4:
+		generate = uuid.New
+	case UUIDv7:
+		generate = func() uuid.UUID { return uuid.Must(uuid.NewV7()) }
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUUIDVersion, uuidVersion)
+	}
+	return func(_ context.Context, event ceevent.Event) ceevent.Event {
+		if event.ID() == "" {
+			event.SetID(generate().String())
+		}
+		return event
+	}, nil
+}
+
+// Time sets the receive time as time of the events without a time.
+func Time(ctx context.Context, event ceevent.Event) ceevent.Event {
+	if event.Time().IsZero() {
+		event.SetTime(ReceivedTime(ctx))
+	}
+	return event
+}
+
+// DataContentType returns a defaulter which sets the given content type as datacontenttype of the events with data
+// but without a datacontenttype.
+func DataContentType(contentType string) ceclient.EventDefaulter {
+	return func(_ context.Context, event ceevent.Event) ceevent.Event {
+		if event.DataContentType() == "" && len(event.Data()) > 0 {
+			event.SetDataContentType(contentType)
+		}
+		return event
+	}
+}
+
+// ReceivedTimeExtension stamps the receive time on the events in the receivedtime extension. A receivedtime set by
+// the client is overwritten, so the consumers can rely on it.
+func ReceivedTimeExtension(ctx context.Context, event ceevent.Event) ceevent.Event {
+	event.SetExtension(ReceivedTimeExtensionName, ReceivedTime(ctx))
+	return event
+}
@@ -0,0 +1,140 @@
+package defaulter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                 string
+		givenNames           []string
+		givenUUIDVersion     string
+		givenDataContentType string
+		wantError            error
+		wantNil              bool
+	}{
+		{
+			name:    "should return no defaulter without names",
+			wantNil: true,
+		},
+		{
+			name:             "should return the chain of the defaulters",
+			givenNames:       []string{NameID, NameTime, NameDataContentType, NameReceivedTime},
+			givenUUIDVersion: UUIDv7, givenDataContentType: "application/json",
+		},
+		{
+			name:       "should fail with unknown defaulter",
+			givenNames: []string{"specversion"},
+			wantError:  ErrUnknownDefaulter,
+		},
+		{
+			name:       "should fail with duplicate defaulter",
+			givenNames: []string{NameTime, NameTime},
+			wantError:  ErrDuplicateDefaulter,
+		},
+		{
+			name:             "should fail with invalid UUID version",
+			givenNames:       []string{NameID},
+			givenUUIDVersion: "v1",
+			wantError:        ErrInvalidUUIDVersion,
+		},
+		{
+			name:       "should fail with empty datacontenttype",
+			givenNames: []string{NameDataContentType},
+			wantError:  ErrEmptyDataContentType,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			defaulter, err := New(tc.givenNames, tc.givenUUIDVersion, tc.givenDataContentType)
+
+			// then
+			require.ErrorIs(t, err, tc.wantError)
+			assert.Equal(t, tc.wantNil || tc.wantError != nil, defaulter == nil)
+		})
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	t.Parallel()
+
+	// given
+	receivedTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := WithReceivedTime(context.Background(), receivedTime)
+	defaulter, err := New([]string{NameID, NameTime, NameDataContentType, NameReceivedTime}, UUIDv7,
+		"application/json")
+	require.NoError(t, err)
+
+	t.Run("should set the missing attributes", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		event := ceevent.New()
+		event.SetType("order.created.v1")
+		event.SetSource("shop")
+		require.NoError(t, event.SetData("", []byte(`{"foo":"bar"}`)))
+
+		// when
+		event = defaulter(ctx, event)
+
+		// then
+		require.NoError(t, event.Validate())
+		id, err := uuid.Parse(event.ID())
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(7), id.Version())
+		assert.Equal(t, receivedTime, event.Time())
+		assert.Equal(t, "application/json", event.DataContentType())
+		assert.Equal(t, cetypes.Timestamp{Time: receivedTime}, event.Extensions()[ReceivedTimeExtensionName])
+	})
+
+	t.Run("should keep the attributes set by the client", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		eventTime := receivedTime.Add(-time.Minute)
+		event := ceevent.New()
+		event.SetID("id")
+		event.SetTime(eventTime)
+		event.SetExtension(ReceivedTimeExtensionName, "spoofed")
+
+		// when
+		event = defaulter(ctx, event)
+
+		// then
+		assert.Equal(t, "id", event.ID())
+		assert.Equal(t, eventTime, event.Time())
+		assert.Empty(t, event.DataContentType())
+		assert.Equal(t, cetypes.Timestamp{Time: receivedTime}, event.Extensions()[ReceivedTimeExtensionName])
+	})
+}
+
+func TestID(t *testing.T) {
+	t.Parallel()
+
+	// given
+	defaulter, err := ID(UUIDv4)
+	require.NoError(t, err)
+
+	// when
+	event := defaulter(context.Background(), ceevent.New())
+
+	// then
+	id, err := uuid.Parse(event.ID())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), id.Version())
+}
@@ -0,0 +1,143 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal/sanitize"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	kymaappconnv1alpha1 "github.com/kyma-project/kyma/components/central-application-gateway/pkg/apis/applicationconnector/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	enricherName = "enricher"
+
+	// the results of the enriched extensions recorded by the metrics.
+	resultSet         = "set"
+	resultOverwritten = "overwritten"
+	resultKept        = "kept"
+	resultMissing     = "missing"
+)
+
+// Enricher sets extensions on the built events with the rules and reloads them once their file changes.
+type Enricher struct {
+	rules             atomic.Pointer[Rules]
+	applicationLister *application.Lister
+	collector         metrics.PublishingMetricsCollector
+	logger            *logger.Logger
+}
+
+// NewEnricher returns a new Enricher with the given rules. The labels and annotations are read from the application
+// of the events with the given lister, if it is enabled.
+func NewEnricher(rules *Rules, applicationLister *application.Lister, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Enricher {
+	e := &Enricher{applicationLister: applicationLister, collector: collector, logger: logger}
+	e.rules.Store(rules)
+	return e
+}
+
+// Enrich sets the extensions of all rules matching the given built event. The given source is the source the event
+// was received with, which identifies its application. Extensions which are already present on the event, e.g. set
+// by the client or an earlier rule, are kept unless the rule overwrites them. Extensions whose header, label or
+// annotation is missing are not set. It can be called on a nil Enricher, which does not change the events.
+func (e *Enricher) Enrich(event *ceevent.Event, source string, header http.Header) {
+	if e == nil {
+		return
+	}
+	var app *kymaappconnv1alpha1.Application
+	appResolved := false
+	for _, rule := range e.rules.Load().Rules {
+		if !rule.matches(event.Type(), source) {
+			continue
+		}
+		for _, extension := range rule.Extensions {
+			if (extension.Label != "" || extension.Annotation != "") && !appResolved {
+				app, appResolved = e.application(source), true
+			}
+			e.collector.RecordEnrichedExtension(rule.Name, extension.Name, e.set(event, rule.Name, extension, header, app))
+		}
+	}
+}
+
+// set sets the given extension on the given event and returns the result.
+func (e *Enricher) set(event *ceevent.Event, rule string, extension Extension, header http.Header,
+	app *kymaappconnv1alpha1.Application,
+) string {
+	value, ok := extensionValue(extension, header, app)
+	if !ok {
+		return resultMissing
+	}
+	result := resultSet
+	if _, ok := event.Extensions()[extension.Name]; ok {
+		if !extension.Overwrite {
+			e.namedLogger().Debugw("Keeping extension present on the event", "rule", rule, "extension", extension.Name,
+				"type", sanitize.LogValue(event.Type()))
+			return resultKept
+		}
+		result = resultOverwritten
+	}
+	event.SetExtension(extension.Name, value)
+	return result
+}
+
+// Watch reloads the rules from the given file once it changes, until the given context is done.
+// The current rules are kept if the changed file is invalid.
+func (e *Enricher) Watch(ctx context.Context, file string, interval time.Duration) {
+	filewatch.Watch(ctx, []string{file}, interval, func() {
+		rules, err := LoadRules(file)
+		if err != nil {
+			e.namedLogger().Errorw("Failed to reload enrichment rules, keeping the current rules", "error", err)
+			return
+		}
+		e.rules.Store(rules)
+		e.namedLogger().Infow("Reloaded enrichment rules", "rules", len(rules.Rules))
+	}, func(err error) {
+		e.namedLogger().Warnw("Failed to read enrichment rules", "error", err)
+	})
+}
+
+// application returns the application of the given source, or nil if the application lister is disabled or the
+// application does not exist.
+func (e *Enricher) application(source string) *kymaappconnv1alpha1.Application {
+	if e.applicationLister == nil {
+		return nil
+	}
+	app, err := e.applicationLister.Get(source)
+	if err != nil {
+		return nil
+	}
+	return app
+}
+
+// extensionValue returns the value of the given extension, and false if its header, label or annotation is missing.
+func extensionValue(extension Extension, header http.Header, app *kymaappconnv1alpha1.Application) (string, bool) {
+	switch {
+	case extension.Value != "":
+		return extension.Value, true
+	case extension.Header != "":
+		values := header.Values(extension.Header)
+		return strings.Join(values, ","), len(values) > 0
+	case app == nil:
+		return "", false
+	case extension.Label != "":
+		value, ok := app.Labels[extension.Label]
+		return value, ok
+	default:
+		value, ok := app.Annotations[extension.Annotation]
+		return value, ok
+	}
+}
+
+func (e *Enricher) namedLogger() *zap.SugaredLogger {
+	return e.logger.WithContext().Named(enricherName)
+}
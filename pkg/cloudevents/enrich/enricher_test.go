@@ -0,0 +1,179 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application/applicationtest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application/fake"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const testRules = `
+rules:
+- name: cluster
+  extensions:
+  - name: cluster
+    value: c1
+  - name: region
+    value: eu10
+- name: shop
+  source: ^shop$
+  type: \.order\.
+  extensions:
+  - name: tenant
+    header: X-Tenant
+    overwrite: true
+  - name: team
+    label: team
+  - name: owner
+    annotation: example.com/owner
+`
+
+func TestEnricher_Enrich(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenType      string
+		givenSource    string
+		givenExtension map[string]string
+		givenHeader    http.Header
+		wantExtensions map[string]any
+	}{
+		{
+			name:        "should set the static extensions only",
+			givenType:   "prefix.crm.customer.created.v1",
+			givenSource: "crm",
+			givenHeader: http.Header{"X-Tenant": []string{"t1"}},
+			wantExtensions: map[string]any{
+				"cluster": "c1",
+				"region":  "eu10",
+			},
+		},
+		{
+			name:        "should set the extensions of all matching rules",
+			givenType:   "prefix.shop.order.created.v1",
+			givenSource: "shop",
+			givenHeader: http.Header{"X-Tenant": []string{"t1"}},
+			wantExtensions: map[string]any{
+				"cluster": "c1",
+				"region":  "eu10",
+				"tenant":  "t1",
+				"team":    "checkout",
+				"owner":   "jane",
+			},
+		},
+		{
+			name:           "should keep the extensions of the client unless overwritten",
+			givenType:      "prefix.shop.order.created.v1",
+			givenSource:    "shop",
+			givenExtension: map[string]string{"region": "us10", "tenant": "t2"},
+			givenHeader:    http.Header{"X-Tenant": []string{"t1"}},
+			wantExtensions: map[string]any{
+				"cluster": "c1",
+				"region":  "us10",
+				"tenant":  "t1",
+				"team":    "checkout",
+				"owner":   "jane",
+			},
+		},
+		{
+			name:           "should not set extensions whose header is missing",
+			givenType:      "prefix.shop.order.created.v1",
+			givenSource:    "shop",
+			givenExtension: map[string]string{"tenant": "t2"},
+			wantExtensions: map[string]any{
+				"cluster": "c1",
+				"region":  "eu10",
+				"tenant":  "t2",
+				"team":    "checkout",
+				"owner":   "jane",
+			},
+		},
+	}
+
+	log, err := logger.New("json", "info")
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testRules), 0o600))
+	rules, err := LoadRules(file)
+	require.NoError(t, err)
+	app := applicationtest.NewApplication("shop", map[string]string{"team": "checkout"})
+	app.Annotations = map[string]string{"example.com/owner": "jane"}
+	applicationLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			enricher := NewEnricher(rules, applicationLister, metrics.NewCollector(latency.NewBucketsProvider()), log)
+			event := ceevent.New()
+			event.SetID("id")
+			event.SetType(tc.givenType)
+			event.SetSource(tc.givenSource)
+			for name, value := range tc.givenExtension {
+				event.SetExtension(name, value)
+			}
+
+			// when
+			enricher.Enrich(&event, tc.givenSource, tc.givenHeader)
+
+			// then
+			assert.Equal(t, tc.wantExtensions, event.Extensions())
+		})
+	}
+}
+
+func TestEnricher_Enrich_Metrics(t *testing.T) {
+	t.Parallel()
+
+	// given
+	log, err := logger.New("json", "info")
+	require.NoError(t, err)
+	rules := &Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{
+		{Name: "region", Value: "eu10"},
+		{Name: "cluster", Value: "c1"},
+		{Name: "tenant", Header: "X-Tenant", Overwrite: true},
+		{Name: "team", Label: "team"},
+	}}}}
+	require.NoError(t, rules.Validate())
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	enricher := NewEnricher(rules, nil, collector, log)
+	event := ceevent.New()
+	event.SetExtension("region", "us10")
+	event.SetExtension("tenant", "t2")
+
+	// when
+	enricher.Enrich(&event, "shop", http.Header{"X-Tenant": []string{"t1"}})
+
+	// then
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+# HELP eventing_epp_enriched_extensions_total The total number of extensions enriched by rule, extension and result
+# TYPE eventing_epp_enriched_extensions_total counter
+eventing_epp_enriched_extensions_total{extension="cluster",result="set",rule="rule"} 1
+eventing_epp_enriched_extensions_total{extension="region",result="kept",rule="rule"} 1
+eventing_epp_enriched_extensions_total{extension="team",result="missing",rule="rule"} 1
+eventing_epp_enriched_extensions_total{extension="tenant",result="overwritten",rule="rule"} 1
+`, metrics.EnrichedExtensionsKey)
+}
+
+func TestEnricher_Enrich_Nil(t *testing.T) {
+	t.Parallel()
+
+	event := ceevent.New()
+	(*Enricher)(nil).Enrich(&event, "", nil)
+	assert.Empty(t, event.Extensions())
+}
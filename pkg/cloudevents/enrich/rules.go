@@ -0,0 +1,160 @@
+package enrich
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrRuleWithoutName         = errors.New("rule has no name")
+	ErrDuplicateRuleName       = errors.New("rule name is not unique")
+	ErrRuleWithoutExtensions   = errors.New("rule sets no extensions")
+	ErrInvalidRulePattern      = errors.New("invalid pattern")
+	ErrInvalidExtensionName    = errors.New("extension name must consist of lower case letters and digits")
+	ErrReservedExtensionName   = errors.New("extension name is reserved")
+	ErrDuplicateExtension      = errors.New("extension is set multiple times")
+	ErrAmbiguousExtensionValue = errors.New("extension must have exactly one of value, header, label or annotation")
+)
+
+// reservedNames are the context attributes and the extensions set by the proxy itself.
+var reservedNames = map[string]struct{}{
+	"id": {}, "source": {}, "specversion": {}, "type": {}, "datacontenttype": {}, "dataschema": {}, "subject": {},
+	"time": {}, "data": {}, "data_base64": {}, "originaltype": {}, "eventtypeversion": {}, "receivedtime": {},
+}
+
+// extensionNamePattern is the format of the CloudEvents extension names.
+var extensionNamePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Rules set extensions on the events after they are built.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule sets extensions on the events matching all of its conditions.
+type Rule struct {
+	// Name identifies the rule in the logs and metrics.
+	Name string `json:"name"`
+	// Type is a regular expression the type of the built event must match.
+	Type string `json:"type,omitempty"`
+	// Source is a regular expression the source the event was received with must match.
+	Source string `json:"source,omitempty"`
+	// Extensions are set on the matching events.
+	Extensions []Extension `json:"extensions"`
+
+	typePattern   *regexp.Regexp
+	sourcePattern *regexp.Regexp
+}
+
+// Extension sets an extension to a static value, a request header, or a label or annotation of the application of
+// the event. Extensions which are already present on the event are kept unless Overwrite is true.
+type Extension struct {
+	// Name is the name of the extension.
+	Name string `json:"name"`
+	// Value is the static value of the extension.
+	Value string `json:"value,omitempty"`
+	// Header is the name of the request header whose value is set.
+	Header string `json:"header,omitempty"`
+	// Label is the name of the application label whose value is set.
+	Label string `json:"label,omitempty"`
+	// Annotation is the name of the application annotation whose value is set.
+	Annotation string `json:"annotation,omitempty"`
+	// Overwrite replaces the value of the extension if it is already present on the event.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+// LoadRules reads the rules from the given YAML or JSON file and validates them.
+func LoadRules(file string) (*Rules, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrichment rules: %w", err)
+	}
+	rules := &Rules{}
+	if err := yaml.UnmarshalStrict(content, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate returns an error if a rule has no unique name, an invalid pattern, or an invalid extension.
+// It compiles the patterns of the rules.
+func (r *Rules) Validate() error {
+	names := make(map[string]struct{}, len(r.Rules))
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d: %w", i, ErrRuleWithoutName)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("rule %s: %w", rule.Name, ErrDuplicateRuleName)
+		}
+		names[rule.Name] = struct{}{}
+		if len(rule.Extensions) == 0 {
+			return fmt.Errorf("rule %s: %w", rule.Name, ErrRuleWithoutExtensions)
+		}
+		if err := rule.validateExtensions(); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		var err error
+		if rule.typePattern, err = compile(rule.Type); err != nil {
+			return fmt.Errorf("rule %s: %w: %w", rule.Name, ErrInvalidRulePattern, err)
+		}
+		if rule.sourcePattern, err = compile(rule.Source); err != nil {
+			return fmt.Errorf("rule %s: %w: %w", rule.Name, ErrInvalidRulePattern, err)
+		}
+	}
+	return nil
+}
+
+// validateExtensions returns an error if an extension has an invalid or reserved name, is set multiple times, or
+// has not exactly one value.
+func (r *Rule) validateExtensions() error {
+	extensions := make(map[string]struct{}, len(r.Extensions))
+	for _, extension := range r.Extensions {
+		if !extensionNamePattern.MatchString(extension.Name) {
+			return fmt.Errorf("%w: %q", ErrInvalidExtensionName, extension.Name)
+		}
+		if _, ok := reservedNames[extension.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrReservedExtensionName, extension.Name)
+		}
+		if _, ok := extensions[extension.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateExtension, extension.Name)
+		}
+		extensions[extension.Name] = struct{}{}
+		values := 0
+		for _, value := range []string{extension.Value, extension.Header, extension.Label, extension.Annotation} {
+			if value != "" {
+				values++
+			}
+		}
+		if values != 1 {
+			return fmt.Errorf("%w: %s", ErrAmbiguousExtensionValue, extension.Name)
+		}
+	}
+	return nil
+}
+
+// matches returns true if the given type and source match all conditions of the rule.
+func (r *Rule) matches(eventType, source string) bool {
+	if r.typePattern != nil && !r.typePattern.MatchString(eventType) {
+		return false
+	}
+	if r.sourcePattern != nil && !r.sourcePattern.MatchString(source) {
+		return false
+	}
+	return true
+}
+
+// compile returns the compiled pattern, or nil if it is empty.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil //nolint:nilnil // an empty pattern matches everything.
+	}
+	return regexp.Compile(pattern)
+}
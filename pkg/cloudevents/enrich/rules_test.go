@@ -0,0 +1,90 @@
+package enrich
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		givenRules Rules
+		wantError  error
+	}{
+		{
+			name:       "should fail without name",
+			givenRules: Rules{Rules: []Rule{{Extensions: []Extension{{Name: "region", Value: "eu"}}}}},
+			wantError:  ErrRuleWithoutName,
+		},
+		{
+			name: "should fail with duplicate names",
+			givenRules: Rules{Rules: []Rule{
+				{Name: "rule", Extensions: []Extension{{Name: "region", Value: "eu"}}},
+				{Name: "rule", Extensions: []Extension{{Name: "cluster", Value: "c1"}}},
+			}},
+			wantError: ErrDuplicateRuleName,
+		},
+		{
+			name:       "should fail without extensions",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Type: "order"}}},
+			wantError:  ErrRuleWithoutExtensions,
+		},
+		{
+			name:       "should fail with invalid extension name",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{{Name: "Region", Value: "eu"}}}}},
+			wantError:  ErrInvalidExtensionName,
+		},
+		{
+			name:       "should fail with reserved extension name",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{{Name: "source", Value: "eu"}}}}},
+			wantError:  ErrReservedExtensionName,
+		},
+		{
+			name: "should fail with duplicate extension",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{
+				{Name: "region", Value: "eu"},
+				{Name: "region", Header: "X-Region"},
+			}}}},
+			wantError: ErrDuplicateExtension,
+		},
+		{
+			name:       "should fail without value",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{{Name: "region"}}}}},
+			wantError:  ErrAmbiguousExtensionValue,
+		},
+		{
+			name: "should fail with multiple values",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Extensions: []Extension{
+				{Name: "region", Value: "eu", Label: "region"},
+			}}}},
+			wantError: ErrAmbiguousExtensionValue,
+		},
+		{
+			name: "should fail with invalid pattern",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Source: "(",
+				Extensions: []Extension{{Name: "region", Value: "eu"}}}}},
+			wantError: ErrInvalidRulePattern,
+		},
+		{
+			name: "should accept valid rules",
+			givenRules: Rules{Rules: []Rule{{Name: "rule", Type: `^prefix\.shop\.`, Extensions: []Extension{
+				{Name: "region", Value: "eu"},
+				{Name: "tenant", Header: "X-Tenant", Overwrite: true},
+				{Name: "team", Label: "team"},
+				{Name: "owner", Annotation: "example.com/owner"},
+			}}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, tc.givenRules.Validate(), tc.wantError)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package eventtype
+
+import (
+	"strings"
+)
+
+// Matches returns true if every event type or NATS subject matching the given subject also matches the given
+// pattern. Both are split into dot-separated segments and may contain the NATS wildcards `*`, which matches a single
+// segment, and `>`, which matches one or more trailing segments.
+func Matches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return i < len(subjectTokens)
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		switch {
+		case subjectTokens[i] == ">":
+			return false
+		case token == "*", token == subjectTokens[i]:
+			continue
+		default:
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
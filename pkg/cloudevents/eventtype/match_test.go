@@ -0,0 +1,33 @@
+package eventtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		subject string
+		want    bool
+	}{
+		{pattern: "kyma.>", subject: "kyma.>", want: true},
+		{pattern: "kyma.>", subject: "kyma.prefix.>", want: true},
+		{pattern: ">", subject: "kyma.>", want: true},
+		{pattern: "kyma.*.>", subject: "kyma.prefix.>", want: true},
+		{pattern: "kyma.prefix.>", subject: "kyma.>", want: false},
+		{pattern: "kyma.*", subject: "kyma.>", want: false},
+		{pattern: "kyma.>", subject: "kyma", want: false},
+		{pattern: "sap.>", subject: "kyma.>", want: false},
+		{pattern: "kyma.order", subject: "kyma.order", want: true},
+		{pattern: "sap.kyma.custom.*.order.created.v1", subject: "sap.kyma.custom.app.order.created.v1", want: true},
+		{pattern: "sap.kyma.custom.*.order.created.v1", subject: "sap.kyma.custom.app.order.created.v2", want: false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, Matches(tc.pattern, tc.subject), "pattern %s subject %s", tc.pattern, tc.subject)
+	}
+}
@@ -0,0 +1,90 @@
+package rewrite
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal/sanitize"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const builderName = "rewrite-builder"
+
+// compile time check.
+var _ builder.CloudEventBuilder = &Builder{}
+
+// Builder rewrites the type and source of the events with the rules before they are built by the wrapped
+// CloudEventBuilder. The original type of the events is kept in the originaltype extension.
+type Builder struct {
+	builder   builder.CloudEventBuilder
+	rules     *atomic.Pointer[Rules]
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+}
+
+// NewBuilder returns a new Builder which rewrites the events with the given rules before they are built by the given
+// CloudEventBuilder.
+func NewBuilder(builder builder.CloudEventBuilder, rules *Rules, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Builder {
+	b := &Builder{builder: builder, rules: &atomic.Pointer[Rules]{}, collector: collector, logger: logger}
+	b.rules.Store(rules)
+	return b
+}
+
+// Wrap returns a new Builder which rewrites the events with the rules of this Builder, including their reloads, before
+// they are built by the given CloudEventBuilder. It does not record the rule hits, so events which are built for
+// multiple backends are counted once by this Builder.
+func (b *Builder) Wrap(builder builder.CloudEventBuilder) *Builder {
+	return &Builder{builder: builder, rules: b.rules, collector: nil, logger: b.logger}
+}
+
+// Build implements the CloudEventBuilder interface.
+func (b *Builder) Build(event ceevent.Event) (*ceevent.Event, error) {
+	originalType := event.Type()
+	eventType, source, rule := b.rules.Load().Rewrite(originalType, event.Source())
+	if rule != "" {
+		if b.collector != nil {
+			b.collector.RecordRewriteRuleHit(rule)
+		}
+		b.namedLogger().Debugw("Rewriting event", "rule", rule,
+			"type", sanitize.LogValue(originalType), "newType", sanitize.LogValue(eventType),
+			"source", sanitize.LogValue(event.Source()), "newSource", sanitize.LogValue(source))
+		event = event.Clone()
+		event.SetType(eventType)
+		event.SetSource(source)
+	}
+	built, err := b.builder.Build(event)
+	if err != nil {
+		return nil, err
+	}
+	built.SetExtension(builder.OriginalTypeHeaderName, originalType)
+	return built, nil
+}
+
+// Watch reloads the rules from the given file once it changes, until the given context is done.
+// The current rules are kept if the changed file is invalid.
+func (b *Builder) Watch(ctx context.Context, file string, interval time.Duration) {
+	filewatch.Watch(ctx, []string{file}, interval, func() {
+		rules, err := LoadRules(file)
+		if err != nil {
+			b.namedLogger().Errorw("Failed to reload rewrite rules, keeping the current rules", "error", err)
+			return
+		}
+		b.rules.Store(rules)
+		b.namedLogger().Infow("Reloaded rewrite rules", "rules", len(rules.Rules))
+	}, func(err error) {
+		b.namedLogger().Warnw("Failed to read rewrite rules", "error", err)
+	})
+}
+
+func (b *Builder) namedLogger() *zap.SugaredLogger {
+	return b.logger.WithContext().Named(builderName)
+}
@@ -0,0 +1,96 @@
+package rewrite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/backend/cleaner"
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	// given
+	log, err := logger.New("json", "info")
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`rules:
+- name: sales-order
+  types:
+    Sales_Order-Created: order.created.v1
+`), 0o600))
+	rules, err := LoadRules(file)
+	require.NoError(t, err)
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	genericBuilder := builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(log), nil, log)
+	rewriteBuilder := NewBuilder(genericBuilder, rules, collector, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rewriteBuilder.Watch(ctx, file, 10*time.Millisecond)
+
+	// when
+	event := newEvent(t, "Sales_Order-Created")
+	built, err := rewriteBuilder.Build(event)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "prefix.source.order.created.v1", built.Type())
+	assert.Equal(t, "Sales_Order-Created", built.Extensions()[builder.OriginalTypeHeaderName])
+	assert.Equal(t, "Sales_Order-Created", event.Type(), "the given event must not be changed")
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+# HELP eventing_epp_rewrite_rule_hits_total The total number of events rewritten by a rewrite rule
+# TYPE eventing_epp_rewrite_rule_hits_total counter
+eventing_epp_rewrite_rule_hits_total{rule="sales-order"} 1
+`, metrics.RewriteRuleHitsKey)
+
+	// when the event is built by a wrapped builder
+	wrapped := rewriteBuilder.Wrap(builder.NewGenericBuilder("other", cleaner.NewJetStreamCleaner(log), nil, log))
+	built, err = wrapped.Build(event)
+
+	// then the rule hit is not recorded again
+	require.NoError(t, err)
+	assert.Equal(t, "other.source.order.created.v1", built.Type())
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+# HELP eventing_epp_rewrite_rule_hits_total The total number of events rewritten by a rewrite rule
+# TYPE eventing_epp_rewrite_rule_hits_total counter
+eventing_epp_rewrite_rule_hits_total{rule="sales-order"} 1
+`, metrics.RewriteRuleHitsKey)
+
+	// when the rules are changed
+	require.NoError(t, os.WriteFile(file, []byte(`rules:
+- name: sales-order
+  types:
+    Sales_Order-Created: order.created.v2
+`), 0o600))
+
+	// then
+	require.Eventually(t, func() bool {
+		built, err := rewriteBuilder.Build(newEvent(t, "Sales_Order-Created"))
+		return err == nil && built.Type() == "prefix.source.order.created.v2"
+	}, time.Second, 10*time.Millisecond)
+	built, err = wrapped.Build(newEvent(t, "Sales_Order-Created"))
+	require.NoError(t, err)
+	assert.Equal(t, "other.source.order.created.v2", built.Type(), "the wrapped builder must share the reloads")
+}
+
+func newEvent(t *testing.T, eventType string) ceevent.Event {
+	t.Helper()
+	event := ceevent.New()
+	event.SetID("id")
+	event.SetSource("source")
+	event.SetType(eventType)
+	require.NoError(t, event.SetData(ceevent.ApplicationJSON, map[string]string{"key": "value"}))
+	return event
+}
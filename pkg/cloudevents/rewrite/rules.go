@@ -0,0 +1,159 @@
+package rewrite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrRuleWithoutName    = errors.New("rule has no name")
+	ErrDuplicateRuleName  = errors.New("rule name is not unique")
+	ErrRuleWithoutRewrite = errors.New("rule rewrites neither the type nor the source")
+	ErrAmbiguousRewrite   = errors.New("rule has both a replacement and a mapping table")
+	ErrInvalidRulePattern = errors.New("invalid pattern")
+	ErrEmptyMappingTarget = errors.New("mapping table has an empty target")
+	ErrEmptyMappingKey    = errors.New("mapping table has an empty key")
+)
+
+// Rules rewrite the types and sources of the events before they are built.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule rewrites the type and source of the events matching all of its conditions.
+type Rule struct {
+	// Name identifies the rule in the logs and metrics.
+	Name string `json:"name"`
+	// Type is a regular expression the event type must match.
+	Type string `json:"type,omitempty"`
+	// Source is a regular expression the event source must match.
+	Source string `json:"source,omitempty"`
+	// NewType replaces the matches of Type in the event type, it can refer to their submatches like `${1}`.
+	// It replaces the whole event type if Type is empty.
+	NewType string `json:"newType,omitempty"`
+	// NewSource replaces the matches of Source in the event source, it can refer to their submatches like `${1}`.
+	// It replaces the whole event source if Source is empty.
+	NewSource string `json:"newSource,omitempty"`
+	// Types maps exact event types to new ones, the rule only matches the event types it contains.
+	Types map[string]string `json:"types,omitempty"`
+	// Sources maps exact event sources to new ones, the rule only matches the event sources it contains.
+	Sources map[string]string `json:"sources,omitempty"`
+
+	typePattern   *regexp.Regexp
+	sourcePattern *regexp.Regexp
+}
+
+// LoadRules reads the rules from the given YAML or JSON file and validates them.
+func LoadRules(file string) (*Rules, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rewrite rules: %w", err)
+	}
+	rules := &Rules{}
+	if err := yaml.UnmarshalStrict(content, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rewrite rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate returns an error if a rule has no unique name, an invalid pattern, or does not rewrite anything.
+// It compiles the patterns of the rules.
+func (r *Rules) Validate() error {
+	names := make(map[string]struct{}, len(r.Rules))
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d: %w", i, ErrRuleWithoutName)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("rule %s: %w", rule.Name, ErrDuplicateRuleName)
+		}
+		names[rule.Name] = struct{}{}
+		if rule.NewType == "" && rule.NewSource == "" && len(rule.Types) == 0 && len(rule.Sources) == 0 {
+			return fmt.Errorf("rule %s: %w", rule.Name, ErrRuleWithoutRewrite)
+		}
+		if (rule.NewType != "" && len(rule.Types) > 0) || (rule.NewSource != "" && len(rule.Sources) > 0) {
+			return fmt.Errorf("rule %s: %w", rule.Name, ErrAmbiguousRewrite)
+		}
+		for _, mapping := range []map[string]string{rule.Types, rule.Sources} {
+			for from, to := range mapping {
+				if from == "" {
+					return fmt.Errorf("rule %s: %w", rule.Name, ErrEmptyMappingKey)
+				}
+				if to == "" {
+					return fmt.Errorf("rule %s: %w: %s", rule.Name, ErrEmptyMappingTarget, from)
+				}
+			}
+		}
+		var err error
+		if rule.typePattern, err = compile(rule.Type); err != nil {
+			return fmt.Errorf("rule %s: %w: %w", rule.Name, ErrInvalidRulePattern, err)
+		}
+		if rule.sourcePattern, err = compile(rule.Source); err != nil {
+			return fmt.Errorf("rule %s: %w: %w", rule.Name, ErrInvalidRulePattern, err)
+		}
+	}
+	return nil
+}
+
+// Rewrite returns the rewritten type and source of the first rule which matches the given type and source, and the
+// name of that rule. The type and source are returned unchanged with an empty name if no rule matches.
+// It can be called on nil Rules.
+func (r *Rules) Rewrite(eventType, source string) (string, string, string) {
+	if r == nil {
+		return eventType, source, ""
+	}
+	for _, rule := range r.Rules {
+		if rule.matches(eventType, source) {
+			return rewrite(rule.typePattern, rule.NewType, rule.Types, eventType),
+				rewrite(rule.sourcePattern, rule.NewSource, rule.Sources, source), rule.Name
+		}
+	}
+	return eventType, source, ""
+}
+
+// matches returns true if the given type and source match all conditions of the rule.
+func (r *Rule) matches(eventType, source string) bool {
+	if r.typePattern != nil && !r.typePattern.MatchString(eventType) {
+		return false
+	}
+	if r.sourcePattern != nil && !r.sourcePattern.MatchString(source) {
+		return false
+	}
+	if _, ok := r.Types[eventType]; len(r.Types) > 0 && !ok {
+		return false
+	}
+	if _, ok := r.Sources[source]; len(r.Sources) > 0 && !ok {
+		return false
+	}
+	return true
+}
+
+// rewrite returns the given value rewritten by the mapping table or the replacement, or unchanged if there are none.
+func rewrite(pattern *regexp.Regexp, replacement string, mapping map[string]string, value string) string {
+	switch {
+	case len(mapping) > 0:
+		return mapping[value]
+	case replacement == "":
+		return value
+	case pattern == nil:
+		return replacement
+	default:
+		return pattern.ReplaceAllString(value, replacement)
+	}
+}
+
+// compile returns the compiled pattern, or nil if it is empty.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil //nolint:nilnil // an empty pattern matches everything.
+	}
+	return regexp.Compile(pattern)
+}
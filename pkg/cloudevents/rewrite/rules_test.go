@@ -0,0 +1,145 @@
+package rewrite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenRule Rule
+		wantError error
+	}{
+		{
+			name:      "should fail without name",
+			givenRule: Rule{NewType: "order.created.v1"},
+			wantError: ErrRuleWithoutName,
+		},
+		{
+			name:      "should fail without rewrite",
+			givenRule: Rule{Name: "rule", Type: "^order$"},
+			wantError: ErrRuleWithoutRewrite,
+		},
+		{
+			name:      "should fail with replacement and mapping table",
+			givenRule: Rule{Name: "rule", NewType: "order.created.v1", Types: map[string]string{"a": "b"}},
+			wantError: ErrAmbiguousRewrite,
+		},
+		{
+			name:      "should fail with empty mapping target",
+			givenRule: Rule{Name: "rule", Sources: map[string]string{"erp": ""}},
+			wantError: ErrEmptyMappingTarget,
+		},
+		{
+			name:      "should fail with invalid pattern",
+			givenRule: Rule{Name: "rule", Type: "(", NewType: "order"},
+			wantError: ErrInvalidRulePattern,
+		},
+		{
+			name:      "should accept valid rule",
+			givenRule: Rule{Name: "rule", Type: "^Sales_(.*)$", NewType: "sales.${1}"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := &Rules{Rules: []Rule{tc.givenRule}}
+			require.ErrorIs(t, rules.Validate(), tc.wantError)
+		})
+	}
+
+	t.Run("should fail with duplicate names", func(t *testing.T) {
+		t.Parallel()
+
+		rules := &Rules{Rules: []Rule{{Name: "rule", NewType: "a"}, {Name: "rule", NewType: "b"}}}
+		require.ErrorIs(t, rules.Validate(), ErrDuplicateRuleName)
+	})
+}
+
+func TestRules_Rewrite(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`rules:
+- name: sales-order
+  type: "^Sales_Order-(\\w+)$"
+  newType: "sales.order.${1}.v1"
+  source: "^erp-(\\w+)$"
+  newSource: "erp"
+- name: legacy-types
+  types:
+    order_created: order.created.v1
+- name: all-crm
+  source: "^crm$"
+  newSource: "commerce"
+`), 0o600))
+	rules, err := LoadRules(file)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		givenType   string
+		givenSource string
+		wantType    string
+		wantSource  string
+		wantRule    string
+	}{
+		{
+			name:        "should rewrite type and source by patterns",
+			givenType:   "Sales_Order-Created",
+			givenSource: "erp-eu10",
+			wantType:    "sales.order.Created.v1",
+			wantSource:  "erp",
+			wantRule:    "sales-order",
+		},
+		{
+			name:        "should apply the first rule matching all conditions",
+			givenType:   "Sales_Order-Created",
+			givenSource: "crm",
+			wantType:    "Sales_Order-Created",
+			wantSource:  "commerce",
+			wantRule:    "all-crm",
+		},
+		{
+			name:        "should rewrite type by mapping table",
+			givenType:   "order_created",
+			givenSource: "erp-eu10",
+			wantType:    "order.created.v1",
+			wantSource:  "erp-eu10",
+			wantRule:    "legacy-types",
+		},
+		{
+			name:        "should not rewrite without matching rule",
+			givenType:   "order.created.v1",
+			givenSource: "shop",
+			wantType:    "order.created.v1",
+			wantSource:  "shop",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			eventType, source, rule := rules.Rewrite(tc.givenType, tc.givenSource)
+
+			// then
+			assert.Equal(t, tc.wantType, eventType)
+			assert.Equal(t, tc.wantSource, source)
+			assert.Equal(t, tc.wantRule, rule)
+		})
+	}
+}
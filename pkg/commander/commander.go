@@ -1,5 +1,11 @@
 package commander
 
+import (
+	"context"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+)
+
 // Commander defines the interface of different implementations.
 type Commander interface {
 	// Init allows main() to pass flag values to the commander instance.
@@ -11,3 +17,31 @@ type Commander interface {
 	// Stop stops the commander instance.
 	Stop() error
 }
+
+// Builder is a Commander which builds its Handler without serving it, so the backend can be switched at runtime.
+type Builder interface {
+	Commander
+
+	// Build returns the Handler of the initialized commander instance and a function which releases its resources.
+	// The resources are bound to the given context as well. It returns an error instead of exiting if the backend
+	// cannot be built, so a failed switch at runtime keeps the active backend, while Start fails on it.
+	Build(ctx context.Context) (*handler.Handler, func(), error)
+}
+
+// Closer releases resources in the reverse order they were acquired, like deferred function calls.
+type Closer struct {
+	funcs []func()
+}
+
+// Defer adds a function which releases a resource.
+func (c *Closer) Defer(f func()) {
+	c.funcs = append(c.funcs, f)
+}
+
+// Close calls the added functions in reverse order.
+func (c *Closer) Close() {
+	for i := len(c.funcs) - 1; i >= 0; i-- {
+		c.funcs[i]()
+	}
+	c.funcs = nil
+}
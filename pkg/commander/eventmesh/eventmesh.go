@@ -2,12 +2,15 @@ package eventmesh
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	eppeventmesh "github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/informers"
@@ -16,7 +19,12 @@ import (
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/oauth"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/circuitbreaker"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/outbox"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/retry"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/routing"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
 	"go.uber.org/zap"
@@ -35,11 +43,17 @@ const (
 	commanderName = backend + "-commander"
 )
 
+// compile time check.
+var _ commander.Builder = &Commander{}
+
 // Commander implements the Commander interface.
 type Commander struct {
 	cancel           context.CancelFunc
 	envCfg           *env.EventMeshConfig
+	rules            *eppeventmesh.Rules
+	targets          *eppeventmesh.Targets
 	logger           *logger.Logger
+	pipeline         *commander.Pipeline
 	metricsCollector *metrics.Collector
 	opts             *options.Options
 }
@@ -59,6 +73,40 @@ func (c *Commander) Init() error {
 	if err := envconfig.Process("", c.envCfg); err != nil {
 		return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
 	}
+	if err := c.envCfg.Validate(); err != nil {
+		return xerrors.Errorf("invalid OAuth configuration for %s : %v", commanderName, err)
+	}
+	if c.envCfg.EventMeshRulesFile != "" {
+		rules, err := eppeventmesh.LoadRules(c.envCfg.EventMeshRulesFile)
+		if err != nil {
+			return xerrors.Errorf("invalid EventMesh rules for %s : %v", commanderName, err)
+		}
+		c.rules = rules
+	}
+	if c.envCfg.EventMeshTargetsFile != "" {
+		targets, err := eppeventmesh.LoadTargets(c.envCfg.EventMeshTargetsFile)
+		if err != nil {
+			return xerrors.Errorf("invalid EventMesh targets for %s : %v", commanderName, err)
+		}
+		for _, t := range targets.Targets {
+			if err := t.Config(c.envCfg).Validate(); err != nil {
+				return xerrors.Errorf("invalid OAuth configuration of target %s for %s : %v", t.Name, commanderName,
+					err)
+			}
+		}
+		c.targets = targets
+	}
+	pipeline, err := commander.LoadPipeline(commanderName, commander.PipelineConfig{
+		Rewrite:    c.envCfg.RewriteConfig,
+		Alias:      c.envCfg.AliasConfig,
+		Policy:     c.envCfg.PolicyConfig,
+		Enrichment: c.envCfg.EnrichmentConfig,
+		Defaulter:  c.envCfg.DefaulterConfig,
+	}, c.metricsCollector, c.logger)
+	if err != nil {
+		return err
+	}
+	c.pipeline = pipeline
 	return nil
 }
 
@@ -66,28 +114,106 @@ func (c *Commander) Init() error {
 func (c *Commander) Start() error {
 	c.namedLogger().Infow("Starting Event Publisher", "configuration", c.envCfg.String(), "startup arguments", c.opts)
 
-	// configure message receiver
-	messageReceiver := receiver.NewHTTPMessageReceiver(c.envCfg.Port)
-
 	// assure uniqueness
 	var ctx context.Context
 	ctx, c.cancel = context.WithCancel(signals.NewContext())
 
+	h, release, err := c.Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// start handler which blocks until it receives a shutdown signal
+	if err := h.Start(ctx); err != nil {
+		return xerrors.Errorf("failed to start handler for %s : %v", commanderName, err)
+	}
+	c.namedLogger().Info("Event Publisher was shut down")
+	return nil
+}
+
+// Build implements the Builder interface and builds the handler of the publisher.
+func (c *Commander) Build(ctx context.Context) (*handler.Handler, func(), error) {
+	closer := &commander.Closer{}
+	h, err := c.build(ctx, closer)
+	if err != nil {
+		closer.Close()
+		return nil, nil, err
+	}
+	return h, closer.Close, nil
+}
+
+// build builds the handler of the publisher and adds the release of its resources to the given closer.
+func (c *Commander) build(ctx context.Context, closer *commander.Closer) (*handler.Handler, error) {
+	// configure message receiver
+	messageReceiver := receiver.NewHTTPMessageReceiver(c.envCfg.Port)
+
 	// configure auth client
-	client := oauth.NewClient(ctx, c.envCfg)
-	defer client.CloseIdleConnections()
+	client, err := oauth.NewClient(ctx, c.envCfg, c.metricsCollector, c.logger)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to configure OAuth client for %s : %v", commanderName, err)
+	}
+	closer.Defer(client.CloseIdleConnections)
 
 	// configure message sender
-	messageSender := eventmesh.NewSender(c.envCfg.EventMeshPublishURL, client, c.logger)
+	var messageSender sender.GenericSender = c.newTargetSender(c.envCfg.EventMeshPublishURL, client, "")
+	var healthChecker health.Checker = health.NewChecker()
+	if checker, ok := messageSender.(health.Checker); ok {
+		healthChecker = checker
+	}
+	if c.envCfg.CircuitBreakerEnabled {
+		c.namedLogger().Info("Circuit breaker is enabled!")
+	}
+	if c.targets != nil {
+		targets := []routing.Target{{Name: eppeventmesh.DefaultTarget, Sender: messageSender}}
+		for _, t := range c.targets.Targets {
+			targetClient, err := oauth.NewClient(ctx, t.Config(c.envCfg), c.metricsCollector, c.logger)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to configure OAuth client of target %s for %s : %v", t.Name,
+					commanderName, err)
+			}
+			closer.Defer(targetClient.CloseIdleConnections)
+			targets = append(targets, routing.Target{
+				Name:   t.Name,
+				Sender: c.newTargetSender(t.PublishURL, targetClient, t.Namespace),
+			})
+		}
+		router := routing.NewSender(targets, c.targets.Routes, c.envCfg.EventTypePrefix, c.metricsCollector,
+			c.logger)
+		messageSender, healthChecker = router, router
+		c.namedLogger().Infow("Routing to multiple EventMesh targets is enabled!", "targets", len(targets))
+	}
+	if c.envCfg.RetryEnabled() {
+		messageSender = retry.NewSender(messageSender, c.envCfg.RetryConfig, c.metricsCollector, c.logger)
+		c.namedLogger().Infow("Retries are enabled!", "maxAttempts", c.envCfg.RetryMaxAttempts)
+	}
+	if c.envCfg.OutboxEnabled {
+		outboxSender, err := outbox.NewSender(messageSender, c.envCfg.OutboxConfig, c.metricsCollector, c.logger)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to open outbox for %s : %v", commanderName, err)
+		}
+		closer.Defer(func() { _ = outboxSender.Close() })
+		outboxSender.Start(ctx)
+		messageSender, healthChecker = outboxSender, outboxSender
+		c.namedLogger().Infow("Outbox is enabled!", "dir", c.envCfg.OutboxDir)
+	}
 
 	// cluster config
-	k8sConfig := config.GetConfigOrDie()
+	k8sConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get cluster config for %s : %v", commanderName, err)
+	}
 
 	// setup application lister
 	var applicationLister *application.Lister
 	if c.envCfg.ApplicationCRDEnabled {
-		dynamicClient := dynamic.NewForConfigOrDie(k8sConfig)
-		applicationLister = application.NewLister(ctx, dynamicClient)
+		dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create dynamic client for %s : %v", commanderName, err)
+		}
+		if applicationLister, err = application.NewLister(ctx, dynamicClient); err != nil {
+			return nil, xerrors.Errorf("failed to sync application informer cache for %s : %v", commanderName, err)
+		}
 		c.namedLogger().Info("Application CR lister is enabled!")
 	} else {
 		c.namedLogger().Info("Application CR lister is disabled!")
@@ -101,7 +227,10 @@ func (c *Commander) Start() error {
 	)
 
 	// Configure Subscription Lister
-	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
+	subDynamicSharedInfFactory, err := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create subscription informer for %s : %v", commanderName, err)
+	}
 	subLister := subDynamicSharedInfFactory.ForResource(subscribed.SubscriptionGVR()).Lister()
 	subscribedProcessor := &subscribed.Processor{
 		SubscriptionLister: &subLister,
@@ -109,9 +238,11 @@ func (c *Commander) Start() error {
 		Namespace:          c.envCfg.EventMeshNamespace,
 		Logger:             c.logger,
 	}
-	// Sync informer cache or die
+	// Sync informer cache
 	c.namedLogger().Info("Waiting for informers caches to sync")
-	informers.WaitForCacheSyncOrDie(ctx, subDynamicSharedInfFactory, c.logger)
+	if err := informers.WaitForCacheSync(ctx, subDynamicSharedInfFactory); err != nil {
+		return nil, xerrors.Errorf("failed to sync informer caches for %s : %v", commanderName, err)
+	}
 	c.namedLogger().Info("Informers were successfully synced")
 
 	// configure event type cleaner
@@ -124,11 +255,12 @@ func (c *Commander) Start() error {
 	ceBuilder := builder.NewEventMeshBuilder(c.envCfg.EventTypePrefix, c.envCfg.EventMeshNamespace, eventTypeCleaner,
 		applicationLister, c.logger)
 
-	// start handler which blocks until it receives a shutdown signal
-	if err := handler.New(
+	ceBuilder = c.pipeline.Builder(ctx, ceBuilder)
+
+	h := handler.New(
 		messageReceiver,
 		messageSender,
-		health.NewChecker(),
+		healthChecker,
 		c.envCfg.RequestTimeout,
 		legacyTransformer,
 		c.opts,
@@ -139,11 +271,9 @@ func (c *Commander) Start() error {
 		ceBuilder,
 		c.envCfg.EventTypePrefix,
 		env.EventMeshBackend,
-	).Start(ctx); err != nil {
-		return xerrors.Errorf("failed to start handler for %s : %v", commanderName, err)
-	}
-	c.namedLogger().Info("Event Publisher was shut down")
-	return nil
+	)
+	c.pipeline.Apply(ctx, h, applicationLister)
+	return h, nil
 }
 
 // Stop implements the Commander interface and stops the publisher.
@@ -152,6 +282,18 @@ func (c *Commander) Stop() error {
 	return nil
 }
 
+// newTargetSender returns the sender to a single EventMesh instance, protected by a circuit breaker if enabled.
+// The given source overrides the source of the events if not empty.
+func (c *Commander) newTargetSender(target string, client *http.Client, source string) sender.GenericSender {
+	eventMeshSender := eventmesh.NewSender(target, client, c.logger)
+	eventMeshSender.Rules, eventMeshSender.Source = c.rules, source
+	eventMeshSender.Collector = c.metricsCollector
+	if !c.envCfg.CircuitBreakerEnabled {
+		return eventMeshSender
+	}
+	return circuitbreaker.NewSender(eventMeshSender, c.envCfg.CircuitBreakerConfig, c.metricsCollector, c.logger)
+}
+
 func (c *Commander) namedLogger() *zap.SugaredLogger {
 	return c.logger.WithContext().Named(commanderName).With("backend", backend)
 }
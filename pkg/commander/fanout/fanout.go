@@ -0,0 +1,350 @@
+package fanout
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/rewrite"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	eppeventmesh "github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/informers"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	eppnats "github.com/kyma-project/eventing-publisher-proxy/pkg/nats"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/oauth"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/circuitbreaker"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/fanout"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/jetstream"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/signals"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/kyma-project/eventing-manager/pkg/backend/cleaner"
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp" // IMPORTANT: remove as this is only required in a dev setup
+)
+
+const (
+	backend       = "fanout"
+	commanderName = backend + "-commander"
+
+	backendEventMesh = "beb"
+	backendNATS      = "nats"
+)
+
+// compile time check.
+var _ commander.Builder = &Commander{}
+
+// Commander implements the Commander interface.
+type Commander struct {
+	cancel           context.CancelFunc
+	metricsCollector *metrics.Collector
+	logger           *logger.Logger
+	pipeline         *commander.Pipeline
+	envCfg           *env.FanoutConfig
+	natsCfg          *env.NATSConfig
+	eventMeshCfg     *env.EventMeshConfig
+	eventMeshRules   *eppeventmesh.Rules
+	opts             *options.Options
+}
+
+// NewCommander creates the Commander for publisher to multiple backends.
+func NewCommander(opts *options.Options, metricsCollector *metrics.Collector, logger *logger.Logger) *Commander {
+	return &Commander{
+		envCfg:           new(env.FanoutConfig),
+		natsCfg:          new(env.NATSConfig),
+		eventMeshCfg:     new(env.EventMeshConfig),
+		logger:           logger,
+		metricsCollector: metricsCollector,
+		opts:             opts,
+	}
+}
+
+// Init implements the Commander interface and initializes the publisher to multiple backends.
+func (c *Commander) Init() error {
+	if err := envconfig.Process("", c.envCfg); err != nil {
+		return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
+	}
+	if !fanout.Policy(c.envCfg.Policy).IsValid() {
+		return xerrors.Errorf("invalid policy %q for %s", c.envCfg.Policy, commanderName)
+	}
+	if len(c.envCfg.Backends) == 0 {
+		return xerrors.Errorf("no backends configured for %s", commanderName)
+	}
+	for i, b := range c.envCfg.Backends {
+		if slices.Contains(c.envCfg.Backends[:i], b) {
+			return xerrors.Errorf("duplicate backend %q for %s", b, commanderName)
+		}
+		switch b {
+		case backendNATS:
+			if err := envconfig.Process("", c.natsCfg); err != nil {
+				return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
+			}
+			if !c.natsCfg.PublishModeValid() {
+				return xerrors.Errorf("invalid publish mode %q for %s", c.natsCfg.PublishMode, commanderName)
+			}
+			if !c.natsCfg.ContentModeValid() {
+				return xerrors.Errorf("invalid content mode %q for %s", c.natsCfg.JSContentMode, commanderName)
+			}
+			if err := c.natsCfg.NATSAuthConfig.Validate(); err != nil {
+				return xerrors.Errorf("invalid NATS authentication for %s : %v", commanderName, err)
+			}
+		case backendEventMesh:
+			if err := envconfig.Process("", c.eventMeshCfg); err != nil {
+				return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
+			}
+			if err := c.eventMeshCfg.Validate(); err != nil {
+				return xerrors.Errorf("invalid OAuth configuration for %s : %v", commanderName, err)
+			}
+			if c.eventMeshCfg.EventMeshRulesFile != "" {
+				rules, err := eppeventmesh.LoadRules(c.eventMeshCfg.EventMeshRulesFile)
+				if err != nil {
+					return xerrors.Errorf("invalid EventMesh rules for %s : %v", commanderName, err)
+				}
+				c.eventMeshRules = rules
+			}
+			if c.eventMeshCfg.EventMeshTargetsFile != "" {
+				return xerrors.Errorf("multiple EventMesh targets are not supported by %s", commanderName)
+			}
+		default:
+			return xerrors.Errorf("invalid backend %q for %s", b, commanderName)
+		}
+	}
+	pipeline, err := commander.LoadPipeline(commanderName, commander.PipelineConfig{
+		Rewrite:    c.envCfg.RewriteConfig,
+		Alias:      c.envCfg.AliasConfig,
+		Policy:     c.envCfg.PolicyConfig,
+		Enrichment: c.envCfg.EnrichmentConfig,
+		Defaulter:  c.envCfg.DefaulterConfig,
+	}, c.metricsCollector, c.logger)
+	if err != nil {
+		return err
+	}
+	c.pipeline = pipeline
+	return nil
+}
+
+// Start implements the Commander interface and starts the publisher.
+func (c *Commander) Start() error {
+	c.namedLogger().Infow("Starting Event Publisher", "configuration", c.envCfg.String(), "startup arguments", c.opts)
+
+	// assure uniqueness
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(signals.NewContext())
+
+	h, release, err := c.Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// start handler which blocks until it receives a shutdown signal
+	if err := h.Start(ctx); err != nil {
+		return xerrors.Errorf("failed to start handler for %s : %v", commanderName, err)
+	}
+
+	c.namedLogger().Info("Event Publisher was shut down")
+
+	return nil
+}
+
+// Build implements the Builder interface and builds the handler of the publisher.
+func (c *Commander) Build(ctx context.Context) (*handler.Handler, func(), error) {
+	closer := &commander.Closer{}
+	h, err := c.build(ctx, closer)
+	if err != nil {
+		closer.Close()
+		return nil, nil, err
+	}
+	return h, closer.Close, nil
+}
+
+// build builds the handler of the publisher and adds the release of its resources to the given closer.
+func (c *Commander) build(ctx context.Context, closer *commander.Closer) (*handler.Handler, error) {
+	// configure the message senders
+	destinations := make([]fanout.Destination, 0, len(c.envCfg.Backends))
+	for _, b := range c.envCfg.Backends {
+		switch b {
+		case backendNATS:
+			authOpts, err := eppnats.WithAuth(&c.natsCfg.NATSAuthConfig)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to configure NATS authentication for %s : %v", commanderName, err)
+			}
+			connection, err := eppnats.Connect(c.natsCfg.URL, append([]eppnats.Opt{
+				eppnats.WithRetryOnFailedConnect(c.natsCfg.RetryOnFailedConnect),
+				eppnats.WithMaxReconnects(c.natsCfg.MaxReconnects),
+				eppnats.WithReconnectWait(c.natsCfg.ReconnectWait),
+				eppnats.WithName("Kyma Publisher"),
+			}, authOpts...)...)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to connect to backend server for %s : %v", commanderName, err)
+			}
+			closer.Defer(connection.Close)
+			eppnats.ReconnectOnRotation(ctx, connection, c.natsCfg.RotatedFiles(),
+				c.natsCfg.NATSCredentialsReloadInterval, c.logger)
+			var natsSender sender.GenericSender = jetstream.NewSender(ctx, connection, c.natsCfg, c.opts,
+				c.metricsCollector, c.logger)
+			if c.natsCfg.CircuitBreakerEnabled {
+				natsSender = circuitbreaker.NewSender(natsSender, c.natsCfg.CircuitBreakerConfig, c.metricsCollector,
+					c.logger)
+			}
+			destinations = append(destinations, fanout.Destination{Name: b, Sender: natsSender})
+		case backendEventMesh:
+			client, err := oauth.NewClient(ctx, c.eventMeshCfg, c.metricsCollector, c.logger)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to configure OAuth client for %s : %v", commanderName, err)
+			}
+			closer.Defer(client.CloseIdleConnections)
+			emsSender := eventmesh.NewSender(c.eventMeshCfg.EventMeshPublishURL, client, c.logger)
+			emsSender.Rules, emsSender.Collector = c.eventMeshRules, c.metricsCollector
+			var eventMeshSender sender.GenericSender = emsSender
+			if c.eventMeshCfg.CircuitBreakerEnabled {
+				eventMeshSender = circuitbreaker.NewSender(eventMeshSender, c.eventMeshCfg.CircuitBreakerConfig,
+					c.metricsCollector, c.logger)
+			}
+			destinations = append(destinations, fanout.Destination{Name: b, Sender: eventMeshSender})
+		}
+	}
+
+	// the primary backend defines the ingress and how the events are built by the handler
+	var (
+		port                  int
+		requestTimeout        time.Duration
+		applicationCRDEnabled bool
+		eventMeshNamespace    string
+		eventTypePrefix       string
+	)
+	primary := c.envCfg.Backends[0]
+	switch primary {
+	case backendNATS:
+		port, requestTimeout, applicationCRDEnabled = c.natsCfg.Port, c.natsCfg.RequestTimeout,
+			c.natsCfg.ApplicationCRDEnabled
+		eventMeshNamespace, eventTypePrefix = c.natsCfg.ToConfig().EventMeshNamespace, c.natsCfg.EventTypePrefix
+	case backendEventMesh:
+		port, requestTimeout, applicationCRDEnabled = c.eventMeshCfg.Port, c.eventMeshCfg.RequestTimeout,
+			c.eventMeshCfg.ApplicationCRDEnabled
+		eventMeshNamespace, eventTypePrefix = c.eventMeshCfg.EventMeshNamespace, c.eventMeshCfg.EventTypePrefix
+	}
+
+	// configure message receiver
+	messageReceiver := receiver.NewHTTPMessageReceiver(port)
+
+	// cluster config
+	k8sConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get cluster config for %s : %v", commanderName, err)
+	}
+
+	// setup application lister
+	var applicationLister *application.Lister
+	if applicationCRDEnabled {
+		dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create dynamic client for %s : %v", commanderName, err)
+		}
+		if applicationLister, err = application.NewLister(ctx, dynamicClient); err != nil {
+			return nil, xerrors.Errorf("failed to sync application informer cache for %s : %v", commanderName, err)
+		}
+		c.namedLogger().Info("Application CR lister is enabled!")
+	} else {
+		c.namedLogger().Info("Application CR lister is disabled!")
+	}
+
+	// configure legacyTransformer
+	legacyTransformer := legacy.NewTransformer(eventMeshNamespace, eventTypePrefix, applicationLister)
+
+	// configure Subscription Lister
+	subDynamicSharedInfFactory, err := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create subscription informer for %s : %v", commanderName, err)
+	}
+	subLister := subDynamicSharedInfFactory.ForResource(subscribed.SubscriptionGVR()).Lister()
+	subscribedProcessor := &subscribed.Processor{
+		SubscriptionLister: &subLister,
+		Prefix:             eventTypePrefix,
+		Namespace:          eventMeshNamespace,
+		Logger:             c.logger,
+	}
+
+	// sync informer cache
+	c.namedLogger().Info("Waiting for informers caches to sync")
+	if err := informers.WaitForCacheSync(ctx, subDynamicSharedInfFactory); err != nil {
+		return nil, xerrors.Errorf("failed to sync informer caches for %s : %v", commanderName, err)
+	}
+	c.namedLogger().Info("Informers are synced successfully")
+
+	// configure event type cleaner
+	eventTypeCleanerV1 := eventtype.NewCleaner(eventTypePrefix, applicationLister, c.logger)
+
+	// configure cloud event builder for subscription CRD v1alpha2
+	ceBuilder := c.newBuilder(primary, applicationLister)
+	var activeBackend env.ActiveBackend = env.JetStreamBackend
+	if primary == backendEventMesh {
+		activeBackend = env.EventMeshBackend
+	}
+
+	ceBuilder = c.pipeline.Builder(ctx, ceBuilder)
+
+	// the secondary destinations build the events as per specifications of their own backend, sharing the rewrite rules
+	rewriteBuilder, _ := ceBuilder.(*rewrite.Builder)
+	for i := 1; i < len(destinations); i++ {
+		destinations[i].Builder = c.newBuilder(destinations[i].Name, applicationLister)
+		if rewriteBuilder != nil {
+			destinations[i].Builder = rewriteBuilder.Wrap(destinations[i].Builder)
+		}
+	}
+	messageSender := fanout.NewSender(destinations, fanout.Policy(c.envCfg.Policy), c.metricsCollector, c.logger)
+
+	h := handler.New(
+		messageReceiver,
+		messageSender,
+		messageSender,
+		requestTimeout,
+		legacyTransformer,
+		c.opts,
+		subscribedProcessor,
+		c.logger,
+		c.metricsCollector,
+		eventTypeCleanerV1,
+		ceBuilder,
+		eventTypePrefix,
+		activeBackend,
+	)
+	c.pipeline.Apply(ctx, h, applicationLister)
+	return h, nil
+}
+
+// newBuilder returns the builder of the events as per specifications of the given backend.
+func (c *Commander) newBuilder(backend string, applicationLister *application.Lister) builder.CloudEventBuilder {
+	if backend == backendEventMesh {
+		return builder.NewEventMeshBuilder(c.eventMeshCfg.EventTypePrefix, c.eventMeshCfg.EventMeshNamespace,
+			cleaner.NewEventMeshCleaner(c.logger), applicationLister, c.logger)
+	}
+	return builder.NewGenericBuilder(env.JetStreamSubjectPrefix, cleaner.NewJetStreamCleaner(c.logger),
+		applicationLister, c.logger)
+}
+
+// Stop implements the Commander interface and stops the publisher.
+func (c *Commander) Stop() error {
+	c.cancel()
+	return nil
+}
+
+func (c *Commander) namedLogger() *zap.SugaredLogger {
+	return c.logger.WithContext().Named(commanderName).With("backend", backend)
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/memory"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/signals"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/kyma-project/eventing-manager/pkg/backend/cleaner"
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	backend       = "memory"
+	commanderName = backend + "-commander"
+)
+
+// compile time check.
+var _ commander.Builder = &Commander{}
+
+// Commander implements the Commander interface.
+type Commander struct {
+	cancel           context.CancelFunc
+	metricsCollector *metrics.Collector
+	logger           *logger.Logger
+	pipeline         *commander.Pipeline
+	envCfg           *env.MemoryConfig
+	opts             *options.Options
+}
+
+// NewCommander creates the Commander for publisher to the in-memory backend.
+func NewCommander(opts *options.Options, metricsCollector *metrics.Collector, logger *logger.Logger) *Commander {
+	return &Commander{
+		envCfg:           new(env.MemoryConfig),
+		logger:           logger,
+		metricsCollector: metricsCollector,
+		opts:             opts,
+	}
+}
+
+// Init implements the Commander interface and initializes the publisher to the in-memory backend.
+func (c *Commander) Init() error {
+	if err := envconfig.Process("", c.envCfg); err != nil {
+		return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
+	}
+	pipeline, err := commander.LoadPipeline(commanderName, commander.PipelineConfig{
+		Rewrite:    c.envCfg.RewriteConfig,
+		Alias:      c.envCfg.AliasConfig,
+		Policy:     c.envCfg.PolicyConfig,
+		Enrichment: c.envCfg.EnrichmentConfig,
+		Defaulter:  c.envCfg.DefaulterConfig,
+	}, c.metricsCollector, c.logger)
+	if err != nil {
+		return err
+	}
+	c.pipeline = pipeline
+	return nil
+}
+
+// Start implements the Commander interface and starts the publisher.
+func (c *Commander) Start() error {
+	c.namedLogger().Infow("Starting Event Publisher", "configuration", c.envCfg.String(), "startup arguments", c.opts)
+
+	// assure uniqueness
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(signals.NewContext())
+
+	h, release, err := c.Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// start handler which blocks until it receives a shutdown signal
+	if err := h.Start(ctx); err != nil {
+		return xerrors.Errorf("failed to start handler for %s : %v", commanderName, err)
+	}
+
+	c.namedLogger().Info("Event Publisher was shut down")
+
+	return nil
+}
+
+// Build implements the Builder interface and builds the handler of the publisher.
+// The in-memory backend has no resources to release.
+func (c *Commander) Build(ctx context.Context) (*handler.Handler, func(), error) {
+	// configure message receiver
+	messageReceiver := receiver.NewHTTPMessageReceiver(c.envCfg.Port)
+
+	// configure the message sender
+	messageSender := memory.NewSender(c.envCfg.BufferSize, c.logger)
+
+	// setup application lister, this is the only component which requires a Kubernetes cluster
+	var applicationLister *application.Lister
+	if c.envCfg.ApplicationCRDEnabled {
+		k8sConfig, err := config.GetConfig()
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to get cluster config for %s : %v", commanderName, err)
+		}
+		dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to create dynamic client for %s : %v", commanderName, err)
+		}
+		if applicationLister, err = application.NewLister(ctx, dynamicClient); err != nil {
+			return nil, nil, xerrors.Errorf("failed to sync application informer cache for %s : %v", commanderName,
+				err)
+		}
+		c.namedLogger().Info("Application CR lister is enabled!")
+	} else {
+		c.namedLogger().Info("Application CR lister is disabled!")
+	}
+
+	// configure legacyTransformer
+	legacyTransformer := legacy.NewTransformer(
+		c.envCfg.ToConfig().EventMeshNamespace,
+		c.envCfg.ToConfig().EventTypePrefix,
+		applicationLister,
+	)
+
+	// configure Subscription Lister, there are no subscriptions without a Kubernetes cluster
+	subLister := cache.NewGenericLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		subscribed.SubscriptionGVR().GroupResource())
+	subscribedProcessor := &subscribed.Processor{
+		SubscriptionLister: &subLister,
+		Prefix:             c.envCfg.ToConfig().EventTypePrefix,
+		Namespace:          c.envCfg.ToConfig().EventMeshNamespace,
+		Logger:             c.logger,
+	}
+
+	// configure event type cleaner
+	eventTypeCleanerV1 := eventtype.NewCleaner(c.envCfg.EventTypePrefix, applicationLister, c.logger)
+
+	// configure event type cleaner for subscription CRD v1alpha2
+	eventTypeCleaner := cleaner.NewJetStreamCleaner(c.logger)
+
+	// configure cloud event builder for subscription CRD v1alpha2
+	ceBuilder := builder.NewGenericBuilder(c.envCfg.EventTypePrefix, eventTypeCleaner, applicationLister, c.logger)
+
+	ceBuilder = c.pipeline.Builder(ctx, ceBuilder)
+
+	h := handler.New(
+		messageReceiver,
+		messageSender,
+		messageSender,
+		c.envCfg.RequestTimeout,
+		legacyTransformer,
+		c.opts,
+		subscribedProcessor,
+		c.logger,
+		c.metricsCollector,
+		eventTypeCleanerV1,
+		ceBuilder,
+		c.envCfg.EventTypePrefix,
+		env.MemoryBackend,
+	)
+	c.pipeline.Apply(ctx, h, applicationLister)
+	h.RouteRegistrars = append(h.RouteRegistrars, memory.NewAPI(messageSender, c.envCfg.MaxWaitTimeout))
+	return h, func() {}, nil
+}
+
+// Stop implements the Commander interface and stops the publisher.
+func (c *Commander) Stop() error {
+	c.cancel()
+	return nil
+}
+
+func (c *Commander) namedLogger() *zap.SugaredLogger {
+	return c.logger.WithContext().Named(commanderName).With("backend", backend)
+}
@@ -2,22 +2,34 @@ package nats
 
 import (
 	"context"
+	"errors"
+	"net/http"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/informers"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	eppnats "github.com/kyma-project/eventing-publisher-proxy/pkg/nats"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/admission"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/circuitbreaker"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/failover"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/jetstream"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/outbox"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/retry"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
+	natsgo "github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 	"golang.org/x/xerrors"
 	"k8s.io/client-go/dynamic"
@@ -34,11 +46,21 @@ const (
 	natsCommanderName = natsBackend + "-commander"
 )
 
+// checkedSender is a sender which also checks the health of its backend.
+type checkedSender interface {
+	sender.GenericSender
+	health.Checker
+}
+
+// compile time check.
+var _ commander.Builder = &Commander{}
+
 // Commander implements the Commander interface.
 type Commander struct {
 	cancel           context.CancelFunc
 	metricsCollector *metrics.Collector
 	logger           *logger.Logger
+	pipeline         *commander.Pipeline
 	envCfg           *env.NATSConfig
 	opts             *options.Options
 }
@@ -58,6 +80,34 @@ func (c *Commander) Init() error {
 	if err := envconfig.Process("", c.envCfg); err != nil {
 		return xerrors.Errorf("failed to read configuration for %s : %v", natsCommanderName, err)
 	}
+	if c.envCfg.FailoverNATSURL != "" && c.envCfg.FailoverHTTPSinkURL != "" {
+		return xerrors.Errorf("only one failover backend can be configured for %s", natsCommanderName)
+	}
+	if !c.envCfg.PublishModeValid() {
+		return xerrors.Errorf("invalid publish mode %q for %s", c.envCfg.PublishMode, natsCommanderName)
+	}
+	if !c.envCfg.ContentModeValid() {
+		return xerrors.Errorf("invalid content mode %q for %s", c.envCfg.JSContentMode, natsCommanderName)
+	}
+	if err := c.envCfg.NATSAuthConfig.Validate(); err != nil {
+		return xerrors.Errorf("invalid NATS authentication for %s : %v", natsCommanderName, err)
+	}
+	if c.envCfg.AdmissionControlEnabled {
+		if err := c.envCfg.AdmissionConfig.Validate(); err != nil {
+			return xerrors.Errorf("invalid admission control for %s : %v", natsCommanderName, err)
+		}
+	}
+	pipeline, err := commander.LoadPipeline(natsCommanderName, commander.PipelineConfig{
+		Rewrite:    c.envCfg.RewriteConfig,
+		Alias:      c.envCfg.AliasConfig,
+		Policy:     c.envCfg.PolicyConfig,
+		Enrichment: c.envCfg.EnrichmentConfig,
+		Defaulter:  c.envCfg.DefaulterConfig,
+	}, c.metricsCollector, c.logger)
+	if err != nil {
+		return err
+	}
+	c.pipeline = pipeline
 	return nil
 }
 
@@ -69,32 +119,117 @@ func (c *Commander) Start() error {
 	var ctx context.Context
 	ctx, c.cancel = context.WithCancel(signals.NewContext())
 
+	h, release, err := c.Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// start handler which blocks until it receives a shutdown signal
+	if err := h.Start(ctx); err != nil {
+		return xerrors.Errorf("failed to start handler for %s : %v", natsCommanderName, err)
+	}
+
+	c.namedLogger().Infof("Event Publisher was shut down")
+
+	return nil
+}
+
+// Build implements the Builder interface and builds the handler of the publisher.
+func (c *Commander) Build(ctx context.Context) (*handler.Handler, func(), error) {
+	closer := &commander.Closer{}
+	h, err := c.build(ctx, closer)
+	if err != nil {
+		closer.Close()
+		return nil, nil, err
+	}
+	return h, closer.Close, nil
+}
+
+// build builds the handler of the publisher and adds the release of its resources to the given closer.
+func (c *Commander) build(ctx context.Context, closer *commander.Closer) (*handler.Handler, error) {
 	// configure message receiver
 	messageReceiver := receiver.NewHTTPMessageReceiver(c.envCfg.Port)
 
 	// connect to nats
-	connection, err := eppnats.Connect(c.envCfg.URL,
-		eppnats.WithRetryOnFailedConnect(c.envCfg.RetryOnFailedConnect),
-		eppnats.WithMaxReconnects(c.envCfg.MaxReconnects),
-		eppnats.WithReconnectWait(c.envCfg.ReconnectWait),
-		eppnats.WithName("Kyma Publisher"),
-	)
+	connection, err := c.connect(ctx, c.envCfg.URL)
 	if err != nil {
-		return xerrors.Errorf("failed to connect to backend server for %s : %v", natsCommanderName, err)
+		return nil, xerrors.Errorf("failed to connect to backend server for %s : %v", natsCommanderName, err)
 	}
-	defer connection.Close()
+	closer.Defer(connection.Close)
 
 	// configure the message sender
-	messageSender := jetstream.NewSender(ctx, connection, c.envCfg, c.opts, c.logger)
+	jsSender := jetstream.NewSender(ctx, connection, c.envCfg, c.opts, c.metricsCollector, c.logger)
+	if err := jsSender.VerifyStream(); errors.Is(err, jetstream.ErrStreamNotFound) {
+		// the stream might be created later, the readiness check reports it until then
+		c.namedLogger().Warnw("Failed to verify stream", "error", err)
+	} else if err != nil {
+		return nil, xerrors.Errorf("failed to verify stream for %s : %v", natsCommanderName, err)
+	}
+	var messageSender checkedSender = jsSender
+	if c.envCfg.CircuitBreakerEnabled {
+		messageSender = circuitbreaker.NewSender(messageSender, c.envCfg.CircuitBreakerConfig, c.metricsCollector,
+			c.logger)
+		c.namedLogger().Info("Circuit breaker is enabled!")
+	}
+	if c.envCfg.FailoverEnabled() {
+		secondarySender, closeSecondary, err := c.newSecondarySender(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to configure failover for %s : %v", natsCommanderName, err)
+		}
+		closer.Defer(closeSecondary)
+		if c.envCfg.CircuitBreakerEnabled {
+			secondarySender = circuitbreaker.NewSender(secondarySender, c.envCfg.CircuitBreakerConfig,
+				c.metricsCollector, c.logger)
+		}
+		failoverSender := failover.NewSender(messageSender, secondarySender, failover.Config{
+			ErrorThreshold: c.envCfg.FailoverErrorThreshold,
+			CheckInterval:  c.envCfg.FailoverCheckInterval,
+			FailbackAfter:  c.envCfg.FailbackAfter,
+		}, c.metricsCollector, c.logger)
+		failoverSender.Start(ctx)
+		messageSender = failoverSender
+		c.namedLogger().Infow("Failover is enabled!", "secondary", secondarySender.URL())
+	}
+	if c.envCfg.RetryEnabled() {
+		messageSender = retry.NewSender(messageSender, c.envCfg.RetryConfig, c.metricsCollector, c.logger)
+		c.namedLogger().Infow("Retries are enabled!", "maxAttempts", c.envCfg.RetryMaxAttempts)
+	}
+	if c.envCfg.OutboxEnabled {
+		outboxSender, err := outbox.NewSender(messageSender, c.envCfg.OutboxConfig, c.metricsCollector, c.logger)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to open outbox for %s : %v", natsCommanderName, err)
+		}
+		closer.Defer(func() { _ = outboxSender.Close() })
+		outboxSender.Start(ctx)
+		messageSender = outboxSender
+		c.namedLogger().Infow("Outbox is enabled!", "dir", c.envCfg.OutboxDir)
+	}
+	// the admission control wraps the outbox, so that shed events are rejected instead of being stored
+	if c.envCfg.AdmissionControlEnabled {
+		admissionSender := admission.NewSender(messageSender, jsSender, c.envCfg.AdmissionConfig, c.metricsCollector,
+			c.logger)
+		admissionSender.Start(ctx)
+		messageSender = admissionSender
+		c.namedLogger().Infow("Admission control is enabled!", "criticalTypes", c.envCfg.AdmissionCriticalTypes)
+	}
 
 	// cluster config
-	k8sConfig := config.GetConfigOrDie()
+	k8sConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get cluster config for %s : %v", natsCommanderName, err)
+	}
 
 	// setup application lister
 	var applicationLister *application.Lister
 	if c.envCfg.ApplicationCRDEnabled {
-		dynamicClient := dynamic.NewForConfigOrDie(k8sConfig)
-		applicationLister = application.NewLister(ctx, dynamicClient)
+		dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create dynamic client for %s : %v", natsCommanderName, err)
+		}
+		if applicationLister, err = application.NewLister(ctx, dynamicClient); err != nil {
+			return nil, xerrors.Errorf("failed to sync application informer cache for %s : %v", natsCommanderName, err)
+		}
 		c.namedLogger().Info("Application CR lister is enabled!")
 	} else {
 		c.namedLogger().Info("Application CR lister is disabled!")
@@ -108,7 +243,10 @@ func (c *Commander) Start() error {
 	)
 
 	// configure Subscription Lister
-	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
+	subDynamicSharedInfFactory, err := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create subscription informer for %s : %v", natsCommanderName, err)
+	}
 	subLister := subDynamicSharedInfFactory.ForResource(subscribed.SubscriptionGVR()).Lister()
 	subscribedProcessor := &subscribed.Processor{
 		SubscriptionLister: &subLister,
@@ -117,9 +255,11 @@ func (c *Commander) Start() error {
 		Logger:             c.logger,
 	}
 
-	// sync informer cache or die
+	// sync informer cache
 	c.namedLogger().Info("Waiting for informers caches to sync")
-	informers.WaitForCacheSyncOrDie(ctx, subDynamicSharedInfFactory, c.logger)
+	if err := informers.WaitForCacheSync(ctx, subDynamicSharedInfFactory); err != nil {
+		return nil, xerrors.Errorf("failed to sync informer caches for %s : %v", natsCommanderName, err)
+	}
 	c.namedLogger().Info("Informers are synced successfully")
 
 	// configure event type cleaner
@@ -132,7 +272,8 @@ func (c *Commander) Start() error {
 	ceBuilder := builder.NewGenericBuilder(env.JetStreamSubjectPrefix, eventTypeCleaner,
 		applicationLister, c.logger)
 
-	// start handler which blocks until it receives a shutdown signal
+	ceBuilder = c.pipeline.Builder(ctx, ceBuilder)
+
 	h := handler.New(
 		messageReceiver,
 		messageSender,
@@ -148,13 +289,47 @@ func (c *Commander) Start() error {
 		c.envCfg.EventTypePrefix,
 		env.JetStreamBackend,
 	)
-	if err := h.Start(ctx); err != nil {
-		return xerrors.Errorf("failed to start handler for %s : %v", natsCommanderName, err)
+	c.pipeline.Apply(ctx, h, applicationLister)
+	return h, nil
+}
+
+// newSecondarySender returns the sender for the configured failover backend and a function to release its resources.
+func (c *Commander) newSecondarySender(ctx context.Context) (sender.GenericSender, func(), error) {
+	if c.envCfg.FailoverHTTPSinkURL != "" {
+		client := &http.Client{Timeout: c.envCfg.RequestTimeout}
+		return eventmesh.NewSender(c.envCfg.FailoverHTTPSinkURL, client, c.logger), client.CloseIdleConnections, nil
 	}
 
-	c.namedLogger().Infof("Event Publisher was shut down")
+	connection, err := c.connect(ctx, c.envCfg.FailoverNATSURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	secondaryCfg := *c.envCfg
+	secondaryCfg.URL = c.envCfg.FailoverNATSURL
+	return jetstream.NewSender(ctx, connection, &secondaryCfg, c.opts, c.metricsCollector, c.logger),
+		connection.Close, nil
+}
 
-	return nil
+// connect connects to the NATS server with the given URL using the configured authentication and reconnects
+// once the credentials are rotated.
+func (c *Commander) connect(ctx context.Context, url string) (*natsgo.Conn, error) {
+	authOpts, err := eppnats.WithAuth(&c.envCfg.NATSAuthConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts := append([]eppnats.Opt{
+		eppnats.WithRetryOnFailedConnect(c.envCfg.RetryOnFailedConnect),
+		eppnats.WithMaxReconnects(c.envCfg.MaxReconnects),
+		eppnats.WithReconnectWait(c.envCfg.ReconnectWait),
+		eppnats.WithName("Kyma Publisher"),
+	}, authOpts...)
+	connection, err := eppnats.Connect(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	eppnats.ReconnectOnRotation(ctx, connection, c.envCfg.RotatedFiles(), c.envCfg.NATSCredentialsReloadInterval,
+		c.logger)
+	return connection, nil
 }
 
 // Stop implements the Commander interface and stops the publisher.
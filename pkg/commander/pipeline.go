@@ -0,0 +1,133 @@
+package commander
+
+import (
+	"context"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/alias"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/defaulter"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/enrich"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/rewrite"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/policy"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+// PipelineConfig holds the configs of the steps the events pass before they are sent, which are embedded in the
+// configs of all backends.
+type PipelineConfig struct {
+	Rewrite    env.RewriteConfig
+	Alias      env.AliasConfig
+	Policy     env.PolicyConfig
+	Enrichment env.EnrichmentConfig
+	Defaulter  env.DefaulterConfig
+}
+
+// Pipeline holds the steps the events pass before they are sent: the defaulters, the event type aliases, the rewrite
+// rules, the enrichment rules and the policies. It is loaded once by the Init of a commander and applied to every
+// handler it builds.
+type Pipeline struct {
+	name             string
+	cfg              PipelineConfig
+	rewriteRules     *rewrite.Rules
+	aliases          *alias.Aliases
+	policies         *policy.Policies
+	enrichmentRules  *enrich.Rules
+	publishDefaulter ceclient.EventDefaulter
+	legacyDefaulter  ceclient.EventDefaulter
+	metricsCollector metrics.PublishingMetricsCollector
+	logger           *logger.Logger
+}
+
+// LoadPipeline loads and validates the configured files and defaulters of the commander with the given name.
+func LoadPipeline(name string, cfg PipelineConfig, metricsCollector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) (*Pipeline, error) {
+	p := &Pipeline{name: name, cfg: cfg, metricsCollector: metricsCollector, logger: logger}
+	if cfg.Rewrite.RewriteRulesFile != "" {
+		rules, err := rewrite.LoadRules(cfg.Rewrite.RewriteRulesFile)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid rewrite rules for %s : %v", name, err)
+		}
+		p.rewriteRules = rules
+	}
+	if cfg.Alias.AliasesFile != "" {
+		aliases, err := alias.LoadAliases(cfg.Alias.AliasesFile)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid event type aliases for %s : %v", name, err)
+		}
+		p.aliases = aliases
+	}
+	if cfg.Policy.PoliciesFile != "" {
+		policies, err := policy.LoadPolicies(cfg.Policy.PoliciesFile)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid policies for %s : %v", name, err)
+		}
+		p.policies = policies
+	}
+	if cfg.Enrichment.EnrichmentRulesFile != "" {
+		rules, err := enrich.LoadRules(cfg.Enrichment.EnrichmentRulesFile)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid enrichment rules for %s : %v", name, err)
+		}
+		p.enrichmentRules = rules
+	}
+	publishDefaulter, err := defaulter.New(cfg.Defaulter.PublishDefaulters, cfg.Defaulter.DefaultIDVersion,
+		cfg.Defaulter.DefaultDataContentType)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid publish defaulters for %s : %v", name, err)
+	}
+	p.publishDefaulter = publishDefaulter
+	legacyDefaulter, err := defaulter.New(cfg.Defaulter.LegacyDefaulters, cfg.Defaulter.DefaultIDVersion,
+		cfg.Defaulter.DefaultDataContentType)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid legacy defaulters for %s : %v", name, err)
+	}
+	p.legacyDefaulter = legacyDefaulter
+	return p, nil
+}
+
+// Builder returns the given cloud event builder with the rewrite rules applied, if there are any. The rewrite rules
+// are reloaded until the given context is done.
+func (p *Pipeline) Builder(ctx context.Context, ceBuilder builder.CloudEventBuilder) builder.CloudEventBuilder {
+	if p.rewriteRules == nil {
+		return ceBuilder
+	}
+	rewriteBuilder := rewrite.NewBuilder(ceBuilder, p.rewriteRules, p.metricsCollector, p.logger)
+	rewriteBuilder.Watch(ctx, p.cfg.Rewrite.RewriteRulesFile, p.cfg.Rewrite.RewriteRulesReloadInterval)
+	p.namedLogger().Infow("Rewrite rules are enabled!", "rules", len(p.rewriteRules.Rules))
+	return rewriteBuilder
+}
+
+// Apply sets the event type aliases, the enricher, the policies and the defaulters of the given handler. The files
+// are reloaded until the given context is done.
+func (p *Pipeline) Apply(ctx context.Context, h *handler.Handler, applicationLister *application.Lister) {
+	if p.aliases != nil {
+		h.Aliases = alias.NewRegistry(p.aliases, p.logger)
+		h.Aliases.Watch(ctx, p.cfg.Alias.AliasesFile, p.cfg.Alias.AliasesReloadInterval)
+		p.namedLogger().Infow("Event type aliases are enabled!", "aliases", len(p.aliases.Aliases))
+	}
+	if p.enrichmentRules != nil {
+		h.Enricher = enrich.NewEnricher(p.enrichmentRules, applicationLister, p.metricsCollector, p.logger)
+		h.Enricher.Watch(ctx, p.cfg.Enrichment.EnrichmentRulesFile, p.cfg.Enrichment.EnrichmentRulesReloadInterval)
+		p.namedLogger().Infow("Event enrichment is enabled!", "rules", len(p.enrichmentRules.Rules))
+	}
+	if p.policies != nil {
+		h.Policies = policy.NewEngine(p.policies, applicationLister, p.metricsCollector, p.logger)
+		h.Policies.Watch(ctx, p.cfg.Policy.PoliciesFile, p.cfg.Policy.PoliciesReloadInterval)
+		p.namedLogger().Infow("Policies are enabled!", "policies", len(p.policies.Policies), "dryRun",
+			p.policies.DryRun)
+	}
+	h.Defaulter, h.LegacyDefaulter = p.publishDefaulter, p.legacyDefaulter
+}
+
+func (p *Pipeline) namedLogger() *zap.SugaredLogger {
+	return p.logger.WithContext().Named(p.name)
+}
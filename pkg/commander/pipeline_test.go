@@ -0,0 +1,134 @@
+package commander
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/defaulter"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/rewrite"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/backend/cleaner"
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestLoadPipeline(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenCfg  func(dir string) PipelineConfig
+		wantError string
+	}{
+		{
+			name: "should load without files",
+			givenCfg: func(string) PipelineConfig {
+				return PipelineConfig{}
+			},
+		},
+		{
+			name: "should fail with invalid rewrite rules",
+			givenCfg: func(dir string) PipelineConfig {
+				return PipelineConfig{Rewrite: env.RewriteConfig{RewriteRulesFile: writeFile(t, dir, "rules: [")}}
+			},
+			wantError: "invalid rewrite rules for test-commander",
+		},
+		{
+			name: "should fail with missing aliases",
+			givenCfg: func(dir string) PipelineConfig {
+				return PipelineConfig{Alias: env.AliasConfig{AliasesFile: filepath.Join(dir, "missing.yaml")}}
+			},
+			wantError: "invalid event type aliases for test-commander",
+		},
+		{
+			name: "should fail with unknown legacy defaulters",
+			givenCfg: func(string) PipelineConfig {
+				return PipelineConfig{Defaulter: env.DefaulterConfig{LegacyDefaulters: []string{"unknown"}}}
+			},
+			wantError: "invalid legacy defaulters for test-commander",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			_, err := LoadPipeline("test-commander", tc.givenCfg(t.TempDir()),
+				metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+
+			// then
+			if tc.wantError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantError)
+		})
+	}
+}
+
+func TestPipeline_BuilderAndApply(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	log := newLogger(t)
+	pipeline, err := LoadPipeline("test-commander", PipelineConfig{
+		Rewrite: env.RewriteConfig{RewriteRulesFile: writeFile(t, dir, `rules:
+- name: sales-order
+  types:
+    Sales_Order-Created: order.created.v1
+`)},
+		Alias: env.AliasConfig{AliasesFile: writeFile(t, dir, `aliases:
+- type: order.created.v1
+  newType: order.placed.v1
+  deprecatedUntil: 2999-01-01T00:00:00Z
+`)},
+		Defaulter: env.DefaulterConfig{PublishDefaulters: []string{defaulter.NameID}, DefaultIDVersion: "v4"},
+	}, metrics.NewCollector(latency.NewBucketsProvider()), log)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := &handler.Handler{}
+
+	// when
+	ceBuilder := pipeline.Builder(ctx, builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(log), nil, log))
+	pipeline.Apply(ctx, h, nil)
+
+	// then
+	assert.IsType(t, &rewrite.Builder{}, ceBuilder)
+	require.NotNil(t, h.Aliases)
+	_, ok := h.Aliases.Lookup("order.created.v1")
+	assert.True(t, ok)
+	assert.NotNil(t, h.Defaulter)
+	assert.Nil(t, h.LegacyDefaulter)
+	assert.Nil(t, h.Enricher)
+	assert.Nil(t, h.Policies)
+}
+
+func writeFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	file, err := os.CreateTemp(dir, "*.yaml")
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	return file.Name()
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
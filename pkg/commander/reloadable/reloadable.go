@@ -0,0 +1,298 @@
+package reloadable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/signals"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	commanderName = "reloadable-commander"
+
+	// readyPollInterval is the interval in which the readiness of a new backend is checked.
+	readyPollInterval = time.Second
+)
+
+var (
+	ErrBackendMissing  = errors.New("backend is missing")
+	ErrBackendNotReady = errors.New("backend did not become ready in time")
+)
+
+// Factory returns the Builder of the given backend.
+type Factory func(backend string) (commander.Builder, error)
+
+// BackendFile is the content of the file which selects the backend.
+type BackendFile struct {
+	// Backend is the backend to publish to, e.g. "nats" or "beb".
+	Backend string `json:"backend"`
+}
+
+// LoadBackend reads the backend from the given YAML or JSON file.
+func LoadBackend(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read backend file: %w", err)
+	}
+	backendFile := &BackendFile{}
+	if err := yaml.UnmarshalStrict(content, backendFile); err != nil {
+		return "", fmt.Errorf("failed to parse backend file: %w", err)
+	}
+	backend := strings.TrimSpace(backendFile.Backend)
+	if backend == "" {
+		return "", ErrBackendMissing
+	}
+	return backend, nil
+}
+
+// compile time check.
+var _ commander.Commander = &Commander{}
+
+// Commander implements the Commander interface. It publishes to the backend selected by a file and switches to
+// another backend once the file changes. The new backend is built and checked for readiness before it replaces the
+// active one, the requests in flight are completed by the replaced backend before its resources are released.
+type Commander struct {
+	cancel           context.CancelFunc
+	envCfg           *env.BackendSwitchConfig
+	factory          Factory
+	logger           *logger.Logger
+	metricsCollector metrics.PublishingMetricsCollector
+	opts             *options.Options
+	handler          *handler.Switch
+
+	// initial is the Builder of the backend selected on Init.
+	initial     commander.Builder
+	initialName string
+
+	// mutex guards the active backend, which is swapped by the reload and released on shutdown.
+	mutex  sync.Mutex
+	active *backend
+	closed bool
+}
+
+// backend is a built backend together with the release of its resources.
+type backend struct {
+	name    string
+	handler *handler.Handler
+	release func()
+	cancel  context.CancelFunc
+}
+
+// close releases the resources of the backend.
+func (b *backend) close() {
+	b.release()
+	b.cancel()
+}
+
+// NewCommander creates the Commander which switches the backend at runtime. The given factory creates the Builder of
+// each backend.
+func NewCommander(opts *options.Options, metricsCollector metrics.PublishingMetricsCollector, logger *logger.Logger,
+	factory Factory,
+) *Commander {
+	return &Commander{
+		envCfg:           new(env.BackendSwitchConfig),
+		factory:          factory,
+		logger:           logger,
+		metricsCollector: metricsCollector,
+		opts:             opts,
+		handler:          &handler.Switch{},
+	}
+}
+
+// Init implements the Commander interface and initializes the Builder of the backend selected by the file.
+func (c *Commander) Init() error {
+	if err := envconfig.Process("", c.envCfg); err != nil {
+		return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
+	}
+	name, err := LoadBackend(c.envCfg.BackendFile)
+	if err != nil {
+		return xerrors.Errorf("invalid backend file for %s : %v", commanderName, err)
+	}
+	builder, err := c.newBuilder(name)
+	if err != nil {
+		return err
+	}
+	c.initial, c.initialName = builder, name
+	return nil
+}
+
+// Start implements the Commander interface and starts the publisher.
+func (c *Commander) Start() error {
+	c.namedLogger().Infow("Starting Event Publisher", "configuration", c.envCfg.String(), "startup arguments", c.opts,
+		"backend", c.initialName)
+
+	// assure uniqueness
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(signals.NewContext())
+
+	initial, err := c.build(ctx, c.initialName, c.initial)
+	if err != nil {
+		return err
+	}
+	c.handler.Swap(initial.handler.Router())
+	c.active = initial
+	c.metricsCollector.SetActiveBackend(initial.name, true)
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		c.closed = true
+		c.active.close()
+	}()
+
+	// switch the backend once the file changes
+	filewatch.Watch(ctx, []string{c.envCfg.BackendFile}, c.envCfg.BackendReloadInterval,
+		func() { c.reload(ctx) },
+		func(err error) { c.namedLogger().Warnw("Failed to read backend file", "error", err) },
+	)
+
+	// start receiver which blocks until it receives a shutdown signal
+	if err := receiver.NewHTTPMessageReceiver(c.envCfg.Port).StartListen(ctx, c.handler, c.logger); err != nil {
+		return xerrors.Errorf("failed to start receiver for %s : %v", commanderName, err)
+	}
+	c.namedLogger().Info("Event Publisher was shut down")
+	return nil
+}
+
+// Stop implements the Commander interface and stops the publisher.
+func (c *Commander) Stop() error {
+	c.cancel()
+	return nil
+}
+
+// reload switches to the backend selected by the file, if it differs from the active one and becomes ready in time.
+// The active backend stays in place if the new one fails.
+func (c *Commander) reload(ctx context.Context) {
+	name, err := LoadBackend(c.envCfg.BackendFile)
+	if err != nil {
+		c.namedLogger().Errorw("Failed to load backend file, keeping the active backend", "error", err)
+		return
+	}
+
+	c.mutex.Lock()
+	active := c.active.name
+	c.mutex.Unlock()
+
+	if name == active {
+		c.namedLogger().Infow("Backend is unchanged", "backend", name)
+		return
+	}
+	// the new backend is built and checked for readiness without holding the mutex, so a slow backend does not block
+	// the shutdown
+	c.namedLogger().Infow("Switching backend", "from", active, "to", name)
+	builder, err := c.newBuilder(name)
+	if err != nil {
+		c.namedLogger().Errorw("Failed to switch backend, keeping the active backend", "backend", name, "error", err)
+		return
+	}
+	next, err := c.build(ctx, name, builder)
+	if err != nil {
+		c.namedLogger().Errorw("Failed to switch backend, keeping the active backend", "backend", name, "error", err)
+		return
+	}
+	if err := c.waitReady(ctx, next.handler); err != nil {
+		next.close()
+		c.namedLogger().Errorw("Failed to switch backend, keeping the active backend", "backend", name, "error", err)
+		return
+	}
+
+	previous, drain, ok := c.swap(next)
+	if !ok {
+		next.close()
+		c.namedLogger().Infow("Publisher is shut down, discarding the new backend", "backend", name)
+		return
+	}
+	c.namedLogger().Infow("Switched backend", "from", previous.name, "to", next.name)
+
+	// the replaced backend completes its requests in flight before its resources are released
+	drainCtx, cancel := context.WithTimeout(context.Background(), c.envCfg.BackendDrainTimeout)
+	defer cancel()
+	if err := drain(drainCtx); err != nil {
+		c.namedLogger().Warnw("Requests in flight of the replaced backend were not completed", "backend",
+			previous.name, "error", err)
+	}
+	previous.close()
+}
+
+// swap replaces the active backend with the given one and returns the replaced backend together with the drain of
+// its requests in flight. It returns false if the publisher is already shut down.
+func (c *Commander) swap(next *backend) (*backend, func(context.Context) error, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil, nil, false
+	}
+	drain := c.handler.Swap(next.handler.Router())
+	previous := c.active
+	c.active = next
+	c.metricsCollector.SetActiveBackend(previous.name, false)
+	c.metricsCollector.SetActiveBackend(next.name, true)
+	return previous, drain, true
+}
+
+// newBuilder returns the initialized Builder of the given backend.
+func (c *Commander) newBuilder(name string) (commander.Builder, error) {
+	builder, err := c.factory(name)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid backend %s for %s : %v", name, commanderName, err)
+	}
+	if err := builder.Init(); err != nil {
+		return nil, xerrors.Errorf("failed to initialize backend %s for %s : %v", name, commanderName, err)
+	}
+	return builder, nil
+}
+
+// build builds the given backend with its own context, so its background routines stop once it is released.
+func (c *Commander) build(ctx context.Context, name string, builder commander.Builder) (*backend, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	h, release, err := builder.Build(ctx)
+	if err != nil {
+		cancel()
+		return nil, xerrors.Errorf("failed to build backend %s for %s : %v", name, commanderName, err)
+	}
+	return &backend{name: name, handler: h, release: release, cancel: cancel}, nil
+}
+
+// waitReady waits until the readiness check of the given handler succeeds or the ready timeout expires.
+func (c *Commander) waitReady(ctx context.Context, h *handler.Handler) error {
+	ctx, cancel := context.WithTimeout(ctx, c.envCfg.BackendReadyTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, health.ReadinessURI, nil)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for !health.IsReady(h.HealthChecker, request) {
+		select {
+		case <-ctx.Done():
+			return ErrBackendNotReady
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (c *Commander) namedLogger() *zap.SugaredLogger {
+	return c.logger.WithContext().Named(commanderName)
+}
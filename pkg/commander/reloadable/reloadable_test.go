@@ -0,0 +1,182 @@
+package reloadable
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/commander"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestLoadBackend(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		givenFile   string
+		wantBackend string
+		wantError   bool
+	}{
+		{
+			name:        "should load YAML",
+			givenFile:   "backend: nats\n",
+			wantBackend: "nats",
+		},
+		{
+			name:        "should load JSON",
+			givenFile:   `{"backend": " beb "}`,
+			wantBackend: "beb",
+		},
+		{
+			name:      "should fail without backend",
+			givenFile: "backend: \"\"\n",
+			wantError: true,
+		},
+		{
+			name:      "should fail with unknown fields",
+			givenFile: "backend: nats\nport: 8080\n",
+			wantError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			file := filepath.Join(t.TempDir(), "backend.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tc.givenFile), 0o600))
+
+			// when
+			backend, err := LoadBackend(file)
+
+			// then
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBackend, backend)
+		})
+	}
+}
+
+func TestCommander_reload(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		givenClosed        bool
+		givenBuildErr      error
+		wantActive         string
+		wantActiveReleased bool
+		wantNextReleased   bool
+	}{
+		{
+			name:               "should switch to the new backend without holding the mutex while building it",
+			wantActive:         "beb",
+			wantActiveReleased: true,
+		},
+		{
+			name:             "should discard the new backend once the publisher is shut down",
+			givenClosed:      true,
+			wantActive:       "nats",
+			wantNextReleased: true,
+		},
+		{
+			name:          "should keep serving with the active backend if the new backend fails to build",
+			givenBuildErr: errors.New("failed to get cluster config"),
+			wantActive:    "nats",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			log, err := logger.New("json", "info")
+			require.NoError(t, err)
+			collector := metrics.NewCollector(latency.NewBucketsProvider())
+			file := filepath.Join(t.TempDir(), "backend.yaml")
+			require.NoError(t, os.WriteFile(file, []byte("backend: beb\n"), 0o600))
+			var c *Commander
+			next := &builderStub{collector: collector, logger: log, err: tc.givenBuildErr, onBuild: func() {
+				// the mutex is free while the new backend is built, so the shutdown is not blocked
+				assert.True(t, c.mutex.TryLock())
+				c.mutex.Unlock()
+			}}
+			c = NewCommander(&options.Options{}, collector, log, func(string) (commander.Builder, error) {
+				return next, nil
+			})
+			c.envCfg = &env.BackendSwitchConfig{BackendFile: file, BackendReadyTimeout: time.Second,
+				BackendDrainTimeout: time.Second}
+			active := &builderStub{collector: collector, logger: log}
+			initial, err := c.build(context.Background(), "nats", active)
+			require.NoError(t, err)
+			c.handler.Swap(initial.handler.Router())
+			c.active = initial
+			c.closed = tc.givenClosed
+
+			// when
+			c.reload(context.Background())
+
+			// then
+			assert.Equal(t, tc.wantActive, c.active.name)
+			assert.True(t, next.built)
+			assert.Equal(t, tc.wantActiveReleased, active.released)
+			assert.Equal(t, tc.wantNextReleased, next.released)
+			writer := httptest.NewRecorder()
+			c.handler.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+			assert.Equal(t, health.StatusCodeHealthy, writer.Code)
+		})
+	}
+}
+
+// builderStub is a Builder which builds a ready handler, or fails with the given error, and records the release of
+// its resources.
+type builderStub struct {
+	collector *metrics.Collector
+	logger    *logger.Logger
+	err       error
+	onBuild   func()
+	built     bool
+	released  bool
+}
+
+func (b *builderStub) Init() error { return nil }
+
+func (b *builderStub) Start() error { return nil }
+
+func (b *builderStub) Stop() error { return nil }
+
+func (b *builderStub) Build(context.Context) (*handler.Handler, func(), error) {
+	if b.onBuild != nil {
+		b.onBuild()
+	}
+	b.built = true
+	if b.err != nil {
+		return nil, nil, b.err
+	}
+	h := handler.New(nil, nil, health.NewChecker(), 0, nil, &options.Options{}, &subscribed.Processor{}, b.logger,
+		b.collector, nil, nil, "", "")
+	return h, func() { b.released = true }, nil
+}
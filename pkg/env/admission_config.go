@@ -0,0 +1,32 @@
+package env
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAdmissionThresholdsInvalid = errors.New("admission recover threshold has to be below the shed threshold")
+
+// AdmissionConfig represents the environment config for the admission control which sheds load
+// while the storage of the backend is under pressure.
+type AdmissionConfig struct {
+	AdmissionControlEnabled bool `default:"false" envconfig:"ADMISSION_CONTROL_ENABLED"`
+	// AdmissionShedThreshold is the storage usage of the stream at which non-critical events are rejected.
+	AdmissionShedThreshold float64 `default:"0.9" envconfig:"ADMISSION_SHED_THRESHOLD"`
+	// AdmissionRecoverThreshold is the storage usage of the stream below which all events are admitted again.
+	AdmissionRecoverThreshold float64 `default:"0.8" envconfig:"ADMISSION_RECOVER_THRESHOLD"`
+	// AdmissionCheckInterval is the interval in which the storage usage of the stream is checked.
+	AdmissionCheckInterval time.Duration `default:"5s" envconfig:"ADMISSION_CHECK_INTERVAL"`
+	// AdmissionCriticalTypes are the event type patterns of the events which are admitted while shedding load.
+	// The patterns support the NATS wildcards `*` and `>`.
+	AdmissionCriticalTypes []string `envconfig:"ADMISSION_CRITICAL_TYPES"`
+}
+
+// Validate returns an error if the recover threshold is not below the shed threshold, because the load would never
+// be shed or never be admitted again otherwise.
+func (c *AdmissionConfig) Validate() error {
+	if c.AdmissionRecoverThreshold >= c.AdmissionShedThreshold {
+		return ErrAdmissionThresholdsInvalid
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmissionConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenCfg  AdmissionConfig
+		wantError error
+	}{
+		{
+			name:     "should accept a recover threshold below the shed threshold",
+			givenCfg: AdmissionConfig{AdmissionShedThreshold: 0.9, AdmissionRecoverThreshold: 0.8},
+		},
+		{
+			name:      "should reject a recover threshold equal to the shed threshold",
+			givenCfg:  AdmissionConfig{AdmissionShedThreshold: 0.9, AdmissionRecoverThreshold: 0.9},
+			wantError: ErrAdmissionThresholdsInvalid,
+		},
+		{
+			name:      "should reject a recover threshold above the shed threshold",
+			givenCfg:  AdmissionConfig{AdmissionShedThreshold: 0.8, AdmissionRecoverThreshold: 0.9},
+			wantError: ErrAdmissionThresholdsInvalid,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.givenCfg.Validate()
+
+			if tc.wantError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantError)
+		})
+	}
+}
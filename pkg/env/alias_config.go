@@ -0,0 +1,14 @@
+package env
+
+import (
+	"time"
+)
+
+// AliasConfig represents the environment config for the aliases of deprecated event types.
+// It is embedded in the configs of all backends.
+type AliasConfig struct {
+	// AliasesFile is the YAML or JSON file which maps the deprecated event types to their new types.
+	AliasesFile string `envconfig:"EVENT_TYPE_ALIASES_FILE"`
+	// AliasesReloadInterval is the interval in which the AliasesFile is checked for changes.
+	AliasesReloadInterval time.Duration `default:"10s" envconfig:"EVENT_TYPE_ALIASES_RELOAD_INTERVAL"`
+}
@@ -0,0 +1,27 @@
+package env
+
+import (
+	"fmt"
+	"time"
+)
+
+// compile time check.
+var _ fmt.Stringer = &BackendSwitchConfig{}
+
+// BackendSwitchConfig represents the environment config for the Event Publisher which switches the backend at runtime.
+type BackendSwitchConfig struct {
+	Port int `default:"8080" envconfig:"INGRESS_PORT"`
+	// BackendFile is the file which selects the backend, e.g. a mounted ConfigMap.
+	BackendFile string `envconfig:"BACKEND_FILE" required:"true"`
+	// BackendReloadInterval is the interval in which the BackendFile is checked for changes.
+	BackendReloadInterval time.Duration `default:"10s" envconfig:"BACKEND_RELOAD_INTERVAL"`
+	// BackendReadyTimeout is the maximum time to wait for a new backend to become ready before it is activated.
+	BackendReadyTimeout time.Duration `default:"1m" envconfig:"BACKEND_READY_TIMEOUT"`
+	// BackendDrainTimeout is the maximum time to wait for the requests in flight of a replaced backend.
+	BackendDrainTimeout time.Duration `default:"30s" envconfig:"BACKEND_DRAIN_TIMEOUT"`
+}
+
+// String implements the fmt.Stringer interface.
+func (c *BackendSwitchConfig) String() string {
+	return fmt.Sprintf("%#v", c)
+}
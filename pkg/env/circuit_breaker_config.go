@@ -0,0 +1,21 @@
+package env
+
+import (
+	"time"
+)
+
+// CircuitBreakerConfig represents the environment config for the circuit breakers of the backend destinations.
+// It is embedded in the configs of the backends which support circuit breakers.
+type CircuitBreakerConfig struct {
+	CircuitBreakerEnabled bool `default:"false" envconfig:"CIRCUIT_BREAKER_ENABLED"`
+	// CircuitBreakerFailureRate is the ratio of failed sends within a window which opens the circuit.
+	CircuitBreakerFailureRate float64 `default:"0.5" envconfig:"CIRCUIT_BREAKER_FAILURE_RATE"`
+	// CircuitBreakerMinRequests is the minimum number of sends within a window before the failure rate is evaluated.
+	CircuitBreakerMinRequests int `default:"20" envconfig:"CIRCUIT_BREAKER_MIN_REQUESTS"`
+	// CircuitBreakerWindow is the period the sends are counted in while the circuit is closed.
+	CircuitBreakerWindow time.Duration `default:"10s" envconfig:"CIRCUIT_BREAKER_WINDOW"`
+	// CircuitBreakerOpenTimeout is the period the circuit stays open before trial sends are let through.
+	CircuitBreakerOpenTimeout time.Duration `default:"30s" envconfig:"CIRCUIT_BREAKER_OPEN_TIMEOUT"`
+	// CircuitBreakerHalfOpenRequests is the number of successful trial sends which close the circuit again.
+	CircuitBreakerHalfOpenRequests int `default:"1" envconfig:"CIRCUIT_BREAKER_HALF_OPEN_REQUESTS"`
+}
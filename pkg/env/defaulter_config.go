@@ -0,0 +1,14 @@
+package env
+
+// DefaulterConfig represents the environment config for the defaulters which set missing attributes of the events.
+// It is embedded in the configs of all backends.
+type DefaulterConfig struct {
+	// PublishDefaulters are the defaulters applied in order to the events of the CloudEvents endpoint.
+	PublishDefaulters []string `envconfig:"PUBLISH_DEFAULTERS"`
+	// LegacyDefaulters are the defaulters applied in order to the events of the legacy endpoint.
+	LegacyDefaulters []string `envconfig:"LEGACY_DEFAULTERS"`
+	// DefaultIDVersion is the version of the UUIDs generated as event IDs, v4 or v7.
+	DefaultIDVersion string `default:"v4" envconfig:"DEFAULT_ID_VERSION"`
+	// DefaultDataContentType is the datacontenttype of the events with data but without a datacontenttype.
+	DefaultDataContentType string `default:"application/json" envconfig:"DEFAULT_DATACONTENTTYPE"`
+}
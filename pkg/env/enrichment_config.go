@@ -0,0 +1,14 @@
+package env
+
+import (
+	"time"
+)
+
+// EnrichmentConfig represents the environment config for the rules which set extensions on the events.
+// It is embedded in the configs of all backends.
+type EnrichmentConfig struct {
+	// EnrichmentRulesFile is the YAML or JSON file with the enrichment rules.
+	EnrichmentRulesFile string `envconfig:"ENRICHMENT_RULES_FILE"`
+	// EnrichmentRulesReloadInterval is the interval in which the EnrichmentRulesFile is checked for changes.
+	EnrichmentRulesReloadInterval time.Duration `default:"10s" envconfig:"ENRICHMENT_RULES_RELOAD_INTERVAL"`
+}
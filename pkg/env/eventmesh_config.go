@@ -12,8 +12,8 @@ var _ fmt.Stringer = &EventMeshConfig{}
 // EventMeshConfig represents the environment config for the Event Publisher to EventMesh.
 type EventMeshConfig struct {
 	Port                int           `default:"8080"              envconfig:"INGRESS_PORT"`
-	ClientID            string        `envconfig:"CLIENT_ID"       required:"true"`
-	ClientSecret        string        `envconfig:"CLIENT_SECRET"   required:"true"`
+	ClientID            string        `envconfig:"CLIENT_ID"`
+	ClientSecret        string        `envconfig:"CLIENT_SECRET"`
 	TokenEndpoint       string        `envconfig:"TOKEN_ENDPOINT"  required:"true"`
 	EventMeshPublishURL string        `envconfig:"EMS_PUBLISH_URL" required:"true"`
 	MaxIdleConns        int           `default:"100"               envconfig:"MAX_IDLE_CONNS"`
@@ -25,6 +25,24 @@ type EventMeshConfig struct {
 	// It follows the eventType format: <eventTypePrefix>.<appName>.<event-name>.<version>
 	EventTypePrefix       string `default:""     envconfig:"EVENT_TYPE_PREFIX"`
 	ApplicationCRDEnabled bool   `default:"true" envconfig:"APPLICATION_CRD_ENABLED"`
+	// ClientIDFile and ClientSecretFile are the files to read the client ID and secret from instead,
+	// e.g. a mounted secret. They are checked for rotation in OAuthCredentialsReloadInterval.
+	ClientIDFile     string `envconfig:"CLIENT_ID_FILE"`
+	ClientSecretFile string `envconfig:"CLIENT_SECRET_FILE"`
+	// EventMeshRulesFile is the YAML or JSON file with the qos, content mode and extra headers per event type.
+	EventMeshRulesFile string `envconfig:"EMS_RULES_FILE"`
+	// EventMeshTargetsFile is the YAML or JSON file with additional EventMesh instances and the routes to them.
+	EventMeshTargetsFile string `envconfig:"EMS_TARGETS_FILE"`
+
+	OAuthConfig
+	RetryConfig
+	CircuitBreakerConfig
+	OutboxConfig
+	RewriteConfig
+	AliasConfig
+	PolicyConfig
+	EnrichmentConfig
+	DefaulterConfig
 }
 
 // ConfigureTransport receives an HTTP transport and configure its max idle connection properties.
@@ -33,10 +51,37 @@ func (c *EventMeshConfig) ConfigureTransport(transport *http.Transport) {
 	transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
 }
 
+// Validate returns an error if the client ID is missing or if the OAuth client authentication is invalid.
+func (c *EventMeshConfig) Validate() error {
+	if c.ClientID == "" && c.ClientIDFile == "" {
+		return ErrOAuthClientIDMissing
+	}
+	if c.ClientIDFile != "" || c.ClientSecretFile != "" {
+		if err := validateFiles([]string{c.ClientIDFile, c.ClientSecretFile}); err != nil {
+			return err
+		}
+	}
+	return c.OAuthConfig.Validate(c.ClientSecret != "" || c.ClientSecretFile != "")
+}
+
+// CredentialFiles returns the configured files which contain the credentials of the client.
+func (c *EventMeshConfig) CredentialFiles() []string {
+	files := make([]string, 0)
+	for _, file := range []string{c.ClientIDFile, c.ClientSecretFile, c.OAuthJWTKeyFile, c.OAuthTLSCertFile,
+		c.OAuthTLSKeyFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
 // String implements the fmt.Stringer interface.
 func (c *EventMeshConfig) String() string {
 	return fmt.Sprintf("EventMeshConfig{ Port: %v; TokenEndPoint: %v; EmsPublishURL: %v; "+
-		"MaxIdleConns: %v; MaxIdleConnsPerHost: %v; RequestTimeout: %v; BEBNamespace: %v; EventTypePrefix: %v }",
+		"MaxIdleConns: %v; MaxIdleConnsPerHost: %v; RequestTimeout: %v; BEBNamespace: %v; EventTypePrefix: %v; "+
+		"RulesFile: %v; TargetsFile: %v; OAuthClientAuthMethod: %v }",
 		c.Port, c.TokenEndpoint, c.EventMeshPublishURL, c.MaxIdleConns,
-		c.MaxIdleConnsPerHost, c.RequestTimeout, c.EventMeshNamespace, c.EventTypePrefix)
+		c.MaxIdleConnsPerHost, c.RequestTimeout, c.EventMeshNamespace, c.EventTypePrefix,
+		c.EventMeshRulesFile, c.EventMeshTargetsFile, c.OAuthClientAuthMethod)
 }
@@ -1,7 +1,10 @@
 package env
 
 import (
+	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -26,3 +29,49 @@ func TestConfigureTransport(t *testing.T) {
 			maxIdleConnectionsPerHost, transport.MaxIdleConnsPerHost)
 	}
 }
+
+func TestEventMeshConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "credential")
+	if err := os.WriteFile(file, []byte("value"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name      string
+		givenCfg  EventMeshConfig
+		wantError error
+		wantFiles []string
+	}{
+		{
+			name:      "should fail without client ID",
+			givenCfg:  EventMeshConfig{ClientSecret: "secret"},
+			wantError: ErrOAuthClientIDMissing,
+		},
+		{
+			name:      "should fail without client secret",
+			givenCfg:  EventMeshConfig{ClientID: "id", OAuthConfig: OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthSecret}},
+			wantError: ErrOAuthClientSecretMissing,
+		},
+		{
+			name: "should accept credential files",
+			givenCfg: EventMeshConfig{ClientIDFile: file, ClientSecretFile: file,
+				OAuthConfig: OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthSecret}},
+			wantFiles: []string{file, file},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tc.givenCfg.Validate(); !errors.Is(err, tc.wantError) {
+				t.Errorf("Validate returned error: %v but want: %v", err, tc.wantError)
+			}
+			if files := tc.givenCfg.CredentialFiles(); len(files) != len(tc.wantFiles) {
+				t.Errorf("CredentialFiles returned: %v but want: %v", files, tc.wantFiles)
+			}
+		})
+	}
+}
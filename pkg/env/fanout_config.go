@@ -0,0 +1,30 @@
+package env
+
+import (
+	"fmt"
+)
+
+// compile time check.
+var _ fmt.Stringer = &FanoutConfig{}
+
+// FanoutConfig represents the environment config for the Event Publisher to multiple backends.
+// The configuration of each backend is read from its own environment config, e.g. NATSConfig or EventMeshConfig.
+type FanoutConfig struct {
+	// Backends is the comma separated list of backends ("nats" or "beb") every event is published to.
+	// The first backend is the primary one, which defines the ingress. Every backend builds the events as per its own
+	// specifications.
+	Backends []string `envconfig:"FANOUT_BACKENDS" required:"true"`
+	// Policy decides when publishing is successful, it could be "all", "primary" or "any".
+	Policy string `default:"all" envconfig:"FANOUT_POLICY"`
+
+	RewriteConfig
+	AliasConfig
+	PolicyConfig
+	EnrichmentConfig
+	DefaulterConfig
+}
+
+// String implements the fmt.Stringer interface.
+func (c *FanoutConfig) String() string {
+	return fmt.Sprintf("%#v", c)
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"fmt"
+	"time"
+)
+
+// compile time check.
+var _ fmt.Stringer = &MemoryConfig{}
+
+// MemoryConfig represents the environment config for the Event Publisher to the in-memory backend.
+type MemoryConfig struct {
+	Port           int           `default:"8080" envconfig:"INGRESS_PORT"`
+	RequestTimeout time.Duration `default:"5s"   envconfig:"REQUEST_TIMEOUT"`
+	// ApplicationCRDEnabled is disabled by default, so that the in-memory backend can run without a Kubernetes cluster.
+	ApplicationCRDEnabled bool `default:"false" envconfig:"APPLICATION_CRD_ENABLED"`
+
+	// Legacy Namespace is used as the event source for legacy events
+	LegacyNamespace string `default:"kyma" envconfig:"LEGACY_NAMESPACE"`
+	// EventTypePrefix is the prefix of each event as per the eventing specification
+	// It follows the eventType format: <eventTypePrefix>.<appName>.<event-name>.<version>
+	EventTypePrefix string `default:"kyma" envconfig:"EVENT_TYPE_PREFIX"`
+
+	// In-memory specific configs
+	// BufferSize is the maximum number of captured events, the oldest events are dropped first.
+	BufferSize int `default:"1000" envconfig:"MEMORY_BUFFER_SIZE"`
+	// MaxWaitTimeout is the upper bound for the timeout of a single wait-for-event request.
+	MaxWaitTimeout time.Duration `default:"1m" envconfig:"MEMORY_MAX_WAIT_TIMEOUT"`
+
+	RewriteConfig
+	AliasConfig
+	PolicyConfig
+	EnrichmentConfig
+	DefaulterConfig
+}
+
+// ToConfig converts to a default EventMeshConfig.
+func (c *MemoryConfig) ToConfig() *EventMeshConfig {
+	cfg := &EventMeshConfig{
+		EventMeshNamespace: c.LegacyNamespace,
+		EventTypePrefix:    c.EventTypePrefix,
+	}
+	return cfg
+}
+
+// String implements the fmt.Stringer interface.
+func (c *MemoryConfig) String() string {
+	return fmt.Sprintf("%#v", c)
+}
@@ -0,0 +1,89 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	ErrMultipleNATSAuthMethods  = errors.New("only one NATS authentication method can be configured")
+	ErrNATSPasswordMissing      = errors.New("NATS user is configured without a password")
+	ErrNATSTLSKeyPairIncomplete = errors.New("NATS TLS certificate and key have to be configured together")
+)
+
+// NATSAuthConfig represents the environment config for the authentication against NATS and the TLS of the connection.
+// At most one of credentials file, NKey seed file, token and user/password can be configured.
+type NATSAuthConfig struct {
+	// NATSCredentialsFile is the user credentials file containing a JWT and an NKey seed.
+	NATSCredentialsFile string `envconfig:"NATS_CREDENTIALS_FILE"`
+	// NATSNKeySeedFile is the file containing an NKey user seed.
+	NATSNKeySeedFile string `envconfig:"NATS_NKEY_SEED_FILE"`
+	NATSToken        string `envconfig:"NATS_TOKEN"`
+	NATSUser         string `envconfig:"NATS_USER"`
+	NATSPassword     string `envconfig:"NATS_PASSWORD"`
+	// NATSTLSCertFile and NATSTLSKeyFile are the client certificate and key for mutual TLS.
+	NATSTLSCertFile string `envconfig:"NATS_TLS_CERT_FILE"`
+	NATSTLSKeyFile  string `envconfig:"NATS_TLS_KEY_FILE"`
+	// NATSTLSCAFile is the CA to verify the server certificate with, the system CAs are used if it is empty.
+	NATSTLSCAFile string `envconfig:"NATS_TLS_CA_FILE"`
+	// NATSCredentialsReloadInterval is the interval in which the credentials and TLS files are checked for rotation.
+	// A rotation forces a reconnect with the new files. Zero disables the checks.
+	NATSCredentialsReloadInterval time.Duration `default:"30s" envconfig:"NATS_CREDENTIALS_RELOAD_INTERVAL"`
+}
+
+// Validate returns an error if the auth methods are ambiguous, incomplete or if a configured file does not exist.
+func (c *NATSAuthConfig) Validate() error {
+	methods := 0
+	for _, set := range []bool{c.NATSCredentialsFile != "", c.NATSNKeySeedFile != "", c.NATSToken != "",
+		c.NATSUser != ""} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return ErrMultipleNATSAuthMethods
+	}
+	if c.NATSUser != "" && c.NATSPassword == "" {
+		return ErrNATSPasswordMissing
+	}
+	if (c.NATSTLSCertFile == "") != (c.NATSTLSKeyFile == "") {
+		return ErrNATSTLSKeyPairIncomplete
+	}
+	for _, file := range []string{c.NATSCredentialsFile, c.NATSNKeySeedFile, c.NATSTLSCertFile, c.NATSTLSKeyFile,
+		c.NATSTLSCAFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("invalid NATS auth file: %w", err)
+		}
+	}
+	return nil
+}
+
+// RotatedFiles returns the configured files which are read again on each reconnect.
+// The NKey seed file is not part of it, because its public key is fixed for the lifetime of the connection.
+func (c *NATSAuthConfig) RotatedFiles() []string {
+	files := make([]string, 0)
+	for _, file := range []string{c.NATSCredentialsFile, c.NATSTLSCertFile, c.NATSTLSKeyFile, c.NATSTLSCAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// GoString implements the fmt.GoStringer interface and hides the secrets.
+func (c NATSAuthConfig) GoString() string {
+	redacted := c
+	if redacted.NATSToken != "" {
+		redacted.NATSToken = "***"
+	}
+	if redacted.NATSPassword != "" {
+		redacted.NATSPassword = "***"
+	}
+	type plain NATSAuthConfig
+	return fmt.Sprintf("%#v", plain(redacted))
+}
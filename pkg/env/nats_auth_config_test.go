@@ -0,0 +1,79 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSAuthConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, []byte("content"), 0o600))
+
+	testCases := []struct {
+		name      string
+		givenCfg  NATSAuthConfig
+		wantError error
+	}{
+		{
+			name:     "should accept no authentication",
+			givenCfg: NATSAuthConfig{},
+		},
+		{
+			name:     "should accept a credentials file with TLS",
+			givenCfg: NATSAuthConfig{NATSCredentialsFile: file, NATSTLSCertFile: file, NATSTLSKeyFile: file},
+		},
+		{
+			name:      "should reject multiple authentication methods",
+			givenCfg:  NATSAuthConfig{NATSToken: "token", NATSUser: "user", NATSPassword: "password"},
+			wantError: ErrMultipleNATSAuthMethods,
+		},
+		{
+			name:      "should reject a user without a password",
+			givenCfg:  NATSAuthConfig{NATSUser: "user"},
+			wantError: ErrNATSPasswordMissing,
+		},
+		{
+			name:      "should reject a TLS certificate without a key",
+			givenCfg:  NATSAuthConfig{NATSTLSCertFile: file},
+			wantError: ErrNATSTLSKeyPairIncomplete,
+		},
+		{
+			name:      "should reject a missing file",
+			givenCfg:  NATSAuthConfig{NATSNKeySeedFile: filepath.Join(t.TempDir(), "missing")},
+			wantError: os.ErrNotExist,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.givenCfg.Validate()
+
+			if tc.wantError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantError)
+		})
+	}
+}
+
+func TestNATSConfig_String_HidesSecrets(t *testing.T) {
+	t.Parallel()
+
+	cfg := NATSConfig{NATSAuthConfig: NATSAuthConfig{NATSToken: "token", NATSUser: "user", NATSPassword: "password"}}
+
+	s := cfg.String()
+
+	assert.NotContains(t, s, `"token"`)
+	assert.NotContains(t, s, `"password"`)
+	assert.Contains(t, s, `"user"`)
+}
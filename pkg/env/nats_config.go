@@ -10,6 +10,22 @@ var _ fmt.Stringer = &NATSConfig{}
 
 const JetStreamSubjectPrefix = "kyma"
 
+// The content modes of the JetStream messages as per the CloudEvents NATS protocol binding.
+const (
+	// ContentModeStructured puts the whole event as JSON into the message body.
+	ContentModeStructured = "structured"
+	// ContentModeBinary puts the event attributes into the message headers and the event data into the message body.
+	ContentModeBinary = "binary"
+)
+
+// The publish modes of the NATS backend.
+const (
+	// PublishModeJetStream publishes events to JetStream and waits for the ack of the stream.
+	PublishModeJetStream = "jetstream"
+	// PublishModeCore publishes events with core NATS without waiting for an ack.
+	PublishModeCore = "core"
+)
+
 // NATSConfig represents the environment config for the Event Publisher to NATS.
 type NATSConfig struct {
 	Port                  int           `default:"8080"       envconfig:"INGRESS_PORT"`
@@ -28,6 +44,62 @@ type NATSConfig struct {
 
 	// JetStream-specific configs
 	JSStreamName string `default:"kyma" envconfig:"JS_STREAM_NAME"`
+	// JSPublishAsync enables publishing with PublishMsgAsync, otherwise each event waits for a synchronous PublishMsg.
+	JSPublishAsync bool `default:"true" envconfig:"JS_PUBLISH_ASYNC"`
+	// JSPublishAsyncMaxPending is the maximum number of asynchronously published events waiting for an ack.
+	JSPublishAsyncMaxPending int `default:"4000" envconfig:"JS_PUBLISH_ASYNC_MAX_PENDING"`
+	// JSReadinessStreamCheck enables the readiness checks of the stream leader and the storage usage of the stream.
+	JSReadinessStreamCheck bool `default:"false" envconfig:"JS_READINESS_STREAM_CHECK"`
+	// JSReadinessMaxStorageUsage is the ratio of the stream limits above which the readiness check fails.
+	JSReadinessMaxStorageUsage float64 `default:"0.95" envconfig:"JS_READINESS_MAX_STORAGE_USAGE"`
+	// JSReadinessCacheTTL is the period the results of the stream readiness checks are reused for.
+	JSReadinessCacheTTL time.Duration `default:"5s" envconfig:"JS_READINESS_CACHE_TTL"`
+	// JSContentMode is the content mode of the published messages, either structured or binary.
+	JSContentMode string `default:"structured" envconfig:"JS_CONTENT_MODE"`
+
+	// PublishMode is the publish mode of all events, either jetstream or core.
+	PublishMode string `default:"jetstream" envconfig:"NATS_PUBLISH_MODE"`
+	// CorePublishTypes are the event type patterns of the events which are published with core NATS
+	// in jetstream publish mode. The patterns support the NATS wildcards `*` and `>`.
+	CorePublishTypes []string `envconfig:"NATS_CORE_PUBLISH_TYPES"`
+	// CorePublishFlush enables flushing the connection after each core NATS publish, so the event is
+	// received by the server before the request is answered.
+	CorePublishFlush bool `default:"false" envconfig:"NATS_CORE_PUBLISH_FLUSH"`
+
+	// Failover configs, failover is enabled if either a secondary NATS URL or an HTTP sink URL is set
+	// FailoverNATSURL is the URL of a secondary NATS cluster
+	FailoverNATSURL string `envconfig:"FAILOVER_NATS_URL"`
+	// FailoverHTTPSinkURL is the URL of a secondary HTTP sink which accepts CloudEvents
+	FailoverHTTPSinkURL    string        `envconfig:"FAILOVER_HTTP_SINK_URL"`
+	FailoverErrorThreshold int           `default:"5"  envconfig:"FAILOVER_ERROR_THRESHOLD"`
+	FailoverCheckInterval  time.Duration `default:"5s" envconfig:"FAILOVER_CHECK_INTERVAL"`
+	FailbackAfter          time.Duration `default:"1m" envconfig:"FAILBACK_AFTER"`
+
+	NATSAuthConfig
+	RetryConfig
+	CircuitBreakerConfig
+	OutboxConfig
+	AdmissionConfig
+	RewriteConfig
+	AliasConfig
+	PolicyConfig
+	EnrichmentConfig
+	DefaulterConfig
+}
+
+// FailoverEnabled returns true if a secondary backend is configured.
+func (c *NATSConfig) FailoverEnabled() bool {
+	return c.FailoverNATSURL != "" || c.FailoverHTTPSinkURL != ""
+}
+
+// ContentModeValid returns true if the configured content mode is supported.
+func (c *NATSConfig) ContentModeValid() bool {
+	return c.JSContentMode == ContentModeStructured || c.JSContentMode == ContentModeBinary
+}
+
+// PublishModeValid returns true if the configured publish mode is supported.
+func (c *NATSConfig) PublishModeValid() bool {
+	return c.PublishMode == PublishModeJetStream || c.PublishMode == PublishModeCore
 }
 
 // ToConfig converts to a default EventMeshConfig.
@@ -0,0 +1,87 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// The supported methods to authenticate the client at the token endpoint.
+const (
+	// OAuthClientAuthSecret authenticates with the client secret.
+	OAuthClientAuthSecret = "client_secret"
+	// OAuthClientAuthTLS authenticates with a client certificate (RFC 8705), the tokens are bound to it.
+	OAuthClientAuthTLS = "tls_client_auth"
+	// OAuthClientAuthPrivateKeyJWT authenticates with a JWT client assertion signed by a private key (RFC 7523).
+	OAuthClientAuthPrivateKeyJWT = "private_key_jwt"
+)
+
+var (
+	ErrOAuthClientAuthMethodInvalid = errors.New("invalid OAuth client authentication method")
+	ErrOAuthClientIDMissing         = errors.New("OAuth client ID is required")
+	ErrOAuthClientSecretMissing     = errors.New("OAuth client secret is required")
+	ErrOAuthTLSKeyPairMissing       = errors.New("OAuth TLS certificate and key have to be configured together")
+	ErrOAuthJWTKeyMissing           = errors.New("OAuth JWT key file is required")
+)
+
+// OAuthConfig represents the environment config for the authentication of the client at the token endpoint.
+type OAuthConfig struct {
+	// OAuthClientAuthMethod is one of client_secret, tls_client_auth and private_key_jwt.
+	OAuthClientAuthMethod string `default:"client_secret" envconfig:"OAUTH_CLIENT_AUTH_METHOD"`
+	// OAuthTLSCertFile and OAuthTLSKeyFile are the client certificate and key for mutual TLS with the token endpoint
+	// and the publish URL, so certificate-bound tokens are accepted. They are required for tls_client_auth.
+	OAuthTLSCertFile string `envconfig:"OAUTH_TLS_CERT_FILE"`
+	OAuthTLSKeyFile  string `envconfig:"OAUTH_TLS_KEY_FILE"`
+	// OAuthTLSCAFile is the CA to verify the server certificates with, the system CAs are used if it is empty.
+	OAuthTLSCAFile string `envconfig:"OAUTH_TLS_CA_FILE"`
+	// OAuthJWTKeyFile is the PEM encoded RSA or ECDSA P-256 private key which signs the client assertions.
+	OAuthJWTKeyFile string `envconfig:"OAUTH_JWT_KEY_FILE"`
+	// OAuthJWTKeyID is the optional kid header of the client assertions.
+	OAuthJWTKeyID string `envconfig:"OAUTH_JWT_KEY_ID"`
+	// OAuthJWTAudience is the audience of the client assertions, the token endpoint is used if it is empty.
+	OAuthJWTAudience string `envconfig:"OAUTH_JWT_AUDIENCE"`
+	// OAuthTokenRefreshBefore is the period before the expiry of a token in which it is already refreshed.
+	OAuthTokenRefreshBefore time.Duration `default:"30s" envconfig:"OAUTH_TOKEN_REFRESH_BEFORE"`
+	// OAuthCredentialsReloadInterval is the interval in which the credential, key and certificate files
+	// are checked for rotation. A rotation swaps the credentials and drops the cached token. Zero disables the checks.
+	OAuthCredentialsReloadInterval time.Duration `default:"30s" envconfig:"OAUTH_CREDENTIALS_RELOAD_INTERVAL"`
+}
+
+// Validate returns an error if the client authentication method is unknown, its credentials are incomplete
+// or if a configured file does not exist. A client secret is only required for client_secret.
+func (c *OAuthConfig) Validate(hasClientSecret bool) error {
+	switch c.OAuthClientAuthMethod {
+	case OAuthClientAuthSecret:
+		if !hasClientSecret {
+			return ErrOAuthClientSecretMissing
+		}
+	case OAuthClientAuthTLS:
+		if c.OAuthTLSCertFile == "" {
+			return ErrOAuthTLSKeyPairMissing
+		}
+	case OAuthClientAuthPrivateKeyJWT:
+		if c.OAuthJWTKeyFile == "" {
+			return ErrOAuthJWTKeyMissing
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrOAuthClientAuthMethodInvalid, c.OAuthClientAuthMethod)
+	}
+	if (c.OAuthTLSCertFile == "") != (c.OAuthTLSKeyFile == "") {
+		return ErrOAuthTLSKeyPairMissing
+	}
+	return validateFiles([]string{c.OAuthTLSCertFile, c.OAuthTLSKeyFile, c.OAuthTLSCAFile, c.OAuthJWTKeyFile})
+}
+
+// validateFiles returns an error if one of the given files does not exist, empty file names are skipped.
+func validateFiles(files []string) error {
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("invalid OAuth file: %w", err)
+		}
+	}
+	return nil
+}
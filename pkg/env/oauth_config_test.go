@@ -0,0 +1,89 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, []byte("content"), 0o600))
+
+	testCases := []struct {
+		name              string
+		givenCfg          OAuthConfig
+		givenClientSecret bool
+		wantError         error
+	}{
+		{
+			name:              "should accept a client secret",
+			givenCfg:          OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthSecret},
+			givenClientSecret: true,
+		},
+		{
+			name:      "should reject a missing client secret",
+			givenCfg:  OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthSecret},
+			wantError: ErrOAuthClientSecretMissing,
+		},
+		{
+			name: "should accept a TLS client certificate without a client secret",
+			givenCfg: OAuthConfig{
+				OAuthClientAuthMethod: OAuthClientAuthTLS, OAuthTLSCertFile: file, OAuthTLSKeyFile: file,
+				OAuthTLSCAFile: file,
+			},
+		},
+		{
+			name:      "should reject a TLS client authentication without a certificate",
+			givenCfg:  OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthTLS},
+			wantError: ErrOAuthTLSKeyPairMissing,
+		},
+		{
+			name:      "should reject a TLS certificate without a key",
+			givenCfg:  OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthTLS, OAuthTLSCertFile: file},
+			wantError: ErrOAuthTLSKeyPairMissing,
+		},
+		{
+			name:     "should accept a private key JWT",
+			givenCfg: OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthPrivateKeyJWT, OAuthJWTKeyFile: file},
+		},
+		{
+			name:      "should reject a private key JWT without a key",
+			givenCfg:  OAuthConfig{OAuthClientAuthMethod: OAuthClientAuthPrivateKeyJWT},
+			wantError: ErrOAuthJWTKeyMissing,
+		},
+		{
+			name: "should reject a missing file",
+			givenCfg: OAuthConfig{
+				OAuthClientAuthMethod: OAuthClientAuthPrivateKeyJWT,
+				OAuthJWTKeyFile:       filepath.Join(t.TempDir(), "missing"),
+			},
+			wantError: os.ErrNotExist,
+		},
+		{
+			name:      "should reject an unknown method",
+			givenCfg:  OAuthConfig{OAuthClientAuthMethod: "client_secret_jwt"},
+			wantError: ErrOAuthClientAuthMethodInvalid,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.givenCfg.Validate(tc.givenClientSecret)
+
+			if tc.wantError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantError)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package env
+
+import (
+	"time"
+)
+
+// OutboxConfig represents the environment config for the local outbox which stores events during backend outages.
+// It is embedded in the configs of the backends which support the outbox.
+type OutboxConfig struct {
+	OutboxEnabled bool   `default:"false"                               envconfig:"OUTBOX_ENABLED"`
+	OutboxDir     string `default:"/tmp/eventing-publisher-proxy/outbox" envconfig:"OUTBOX_DIR"`
+	// OutboxMaxSize is the maximum size in bytes of the stored events which were not replayed yet, events are
+	// rejected with 507 once it is reached.
+	OutboxMaxSize int64 `default:"104857600" envconfig:"OUTBOX_MAX_SIZE"`
+	// OutboxReplayInterval is the interval in which stored events are replayed once the backend is ready.
+	OutboxReplayInterval time.Duration `default:"1s" envconfig:"OUTBOX_REPLAY_INTERVAL"`
+	// OutboxReplayTimeout is the timeout of sending a single stored event to the backend.
+	OutboxReplayTimeout time.Duration `default:"10s" envconfig:"OUTBOX_REPLAY_TIMEOUT"`
+	// OutboxCodes are the HTTP status codes of publish errors which cause events to be stored in the outbox.
+	OutboxCodes []int `default:"502,503,504" envconfig:"OUTBOX_CODES"`
+}
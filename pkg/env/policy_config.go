@@ -0,0 +1,14 @@
+package env
+
+import (
+	"time"
+)
+
+// PolicyConfig represents the environment config for the policies which decide whether events are published.
+// It is embedded in the configs of all backends.
+type PolicyConfig struct {
+	// PoliciesFile is the YAML or JSON file with the ordered policies.
+	PoliciesFile string `envconfig:"POLICIES_FILE"`
+	// PoliciesReloadInterval is the interval in which the PoliciesFile is checked for changes.
+	PoliciesReloadInterval time.Duration `default:"10s" envconfig:"POLICIES_RELOAD_INTERVAL"`
+}
@@ -0,0 +1,24 @@
+package env
+
+import (
+	"time"
+)
+
+// RetryConfig represents the environment config for retrying failed sends to the backend.
+// It is embedded in the configs of the backends which support retries.
+type RetryConfig struct {
+	// RetryMaxAttempts is the maximum number of attempts to send an event, 1 disables retries.
+	RetryMaxAttempts       int           `default:"1"     envconfig:"RETRY_MAX_ATTEMPTS"`
+	RetryInitialBackoff    time.Duration `default:"100ms" envconfig:"RETRY_INITIAL_BACKOFF"`
+	RetryMaxBackoff        time.Duration `default:"1s"    envconfig:"RETRY_MAX_BACKOFF"`
+	RetryBackoffMultiplier float64       `default:"2"     envconfig:"RETRY_BACKOFF_MULTIPLIER"`
+	// RetryBudget is the overall time available for all attempts, 0 means it is only bounded by REQUEST_TIMEOUT.
+	RetryBudget time.Duration `default:"0" envconfig:"RETRY_BUDGET"`
+	// RetryableCodes are the HTTP status codes of publish errors which are retried.
+	RetryableCodes []int `default:"502,503,504" envconfig:"RETRYABLE_CODES"`
+}
+
+// RetryEnabled returns true if more than one attempt is configured.
+func (c RetryConfig) RetryEnabled() bool {
+	return c.RetryMaxAttempts > 1
+}
@@ -0,0 +1,14 @@
+package env
+
+import (
+	"time"
+)
+
+// RewriteConfig represents the environment config for rewriting the types and sources of the events before they are
+// built. It is embedded in the configs of all backends.
+type RewriteConfig struct {
+	// RewriteRulesFile is the YAML or JSON file with the ordered rewrite rules.
+	RewriteRulesFile string `envconfig:"REWRITE_RULES_FILE"`
+	// RewriteRulesReloadInterval is the interval in which the RewriteRulesFile is checked for changes.
+	RewriteRulesReloadInterval time.Duration `default:"10s" envconfig:"REWRITE_RULES_RELOAD_INTERVAL"`
+}
@@ -5,4 +5,5 @@ type ActiveBackend string
 const (
 	JetStreamBackend = "JetStream"
 	EventMeshBackend = "EventMesh"
+	MemoryBackend    = "Memory"
 )
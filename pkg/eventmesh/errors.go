@@ -0,0 +1,116 @@
+package eventmesh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorClass classifies the errors EventMesh answers publish requests with.
+type ErrorClass string
+
+const (
+	// ErrorClassAuthorization the client is not authorized to publish, e.g. because of invalid credentials.
+	ErrorClassAuthorization ErrorClass = "authorization"
+	// ErrorClassUnknownTopic the topic of the event type does not exist.
+	ErrorClassUnknownTopic ErrorClass = "unknown_topic"
+	// ErrorClassQuotaExceeded the publishing quota of the instance is exceeded.
+	ErrorClassQuotaExceeded ErrorClass = "quota_exceeded"
+	// ErrorClassPayloadTooLarge the event exceeds the maximum message size.
+	ErrorClassPayloadTooLarge ErrorClass = "payload_too_large"
+	// ErrorClassOther any other error.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ErrorResponse is the parsed error document of a rejected publish request.
+type ErrorResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the EventMesh error code.
+	Code string
+	// Message is the EventMesh error message.
+	Message string
+	// Topic is the topic the event was published to.
+	Topic string
+	// Class is the class of the error.
+	Class ErrorClass
+}
+
+// errorDocument is the JSON error document of EventMesh. The fields are either on the top level or nested in error.
+type errorDocument struct {
+	Code    any            `json:"code"`
+	Message string         `json:"message"`
+	Topic   string         `json:"topic"`
+	Error   *errorDocument `json:"error"`
+}
+
+// ParseErrorResponse parses the given response body of a rejected publish request and classifies the error.
+// Bodies which are no EventMesh error document are classified by the status code only.
+func ParseErrorResponse(statusCode int, body []byte) ErrorResponse {
+	response := ErrorResponse{StatusCode: statusCode}
+	document := &errorDocument{}
+	if err := json.Unmarshal(body, document); err == nil {
+		if document.Error != nil {
+			document = document.Error
+		}
+		if document.Code != nil {
+			response.Code = fmt.Sprint(document.Code)
+		}
+		response.Message, response.Topic = document.Message, document.Topic
+	}
+	response.Class = classify(response)
+	return response
+}
+
+// classify returns the class of the given error response by its status code and its error code and message.
+func classify(response ErrorResponse) ErrorClass {
+	text := strings.ToLower(response.Code + " " + response.Message)
+	switch {
+	case response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden:
+		return ErrorClassAuthorization
+	case response.StatusCode == http.StatusRequestEntityTooLarge || strings.Contains(text, "too large"):
+		return ErrorClassPayloadTooLarge
+	case response.StatusCode == http.StatusTooManyRequests || strings.Contains(text, "quota"):
+		return ErrorClassQuotaExceeded
+	case response.StatusCode == http.StatusNotFound ||
+		(strings.Contains(text, "topic") && (strings.Contains(text, "not found") || strings.Contains(text, "unknown"))):
+		return ErrorClassUnknownTopic
+	default:
+		return ErrorClassOther
+	}
+}
+
+// Status returns the HTTP status code the clients are answered with for the given error response.
+// Authorization errors are internal errors, because the publisher proxy is not authorized and not the client.
+func (r ErrorResponse) Status() int {
+	switch r.Class {
+	case ErrorClassAuthorization:
+		return http.StatusInternalServerError
+	case ErrorClassUnknownTopic:
+		return http.StatusNotFound
+	case ErrorClassQuotaExceeded:
+		return http.StatusTooManyRequests
+	case ErrorClassPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return r.StatusCode
+	}
+}
+
+// Description returns the message the clients are answered with for the given error response.
+// It does not contain the EventMesh error message, which might reveal internals of the instance.
+func (r ErrorResponse) Description() string {
+	switch r.Class {
+	case ErrorClassAuthorization:
+		return "not authorized to publish to EventMesh"
+	case ErrorClassUnknownTopic:
+		return "event type is not known to EventMesh"
+	case ErrorClassQuotaExceeded:
+		return "EventMesh publishing quota exceeded"
+	case ErrorClassPayloadTooLarge:
+		return "event exceeds the maximum message size of EventMesh"
+	default:
+		return "publishing to EventMesh failed"
+	}
+}
@@ -0,0 +1,98 @@
+package eventmesh
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		givenStatusCode int
+		givenBody       string
+		wantResponse    ErrorResponse
+		wantStatus      int
+		wantDescription string
+	}{
+		{
+			name:            "authorization error without error document",
+			givenStatusCode: http.StatusUnauthorized,
+			givenBody:       "Unauthorized",
+			wantResponse:    ErrorResponse{StatusCode: http.StatusUnauthorized, Class: ErrorClassAuthorization},
+			wantStatus:      http.StatusInternalServerError,
+			wantDescription: "not authorized to publish to EventMesh",
+		},
+		{
+			name:            "unknown topic by error message",
+			givenStatusCode: http.StatusBadRequest,
+			givenBody:       `{"code":400,"message":"Topic not found","topic":"namespace/app/order/created/v1"}`,
+			wantResponse: ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Code:       "400",
+				Message:    "Topic not found",
+				Topic:      "namespace/app/order/created/v1",
+				Class:      ErrorClassUnknownTopic,
+			},
+			wantStatus:      http.StatusNotFound,
+			wantDescription: "event type is not known to EventMesh",
+		},
+		{
+			name:            "quota exceeded with nested error document",
+			givenStatusCode: http.StatusBadRequest,
+			givenBody:       `{"error":{"code":"QUOTA_EXCEEDED","message":"message rate exceeded"}}`,
+			wantResponse: ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Code:       "QUOTA_EXCEEDED",
+				Message:    "message rate exceeded",
+				Class:      ErrorClassQuotaExceeded,
+			},
+			wantStatus:      http.StatusTooManyRequests,
+			wantDescription: "EventMesh publishing quota exceeded",
+		},
+		{
+			name:            "payload too large",
+			givenStatusCode: http.StatusRequestEntityTooLarge,
+			givenBody:       `{"code":413,"message":"the message exceeds the maximum size"}`,
+			wantResponse: ErrorResponse{
+				StatusCode: http.StatusRequestEntityTooLarge,
+				Code:       "413",
+				Message:    "the message exceeds the maximum size",
+				Class:      ErrorClassPayloadTooLarge,
+			},
+			wantStatus:      http.StatusRequestEntityTooLarge,
+			wantDescription: "event exceeds the maximum message size of EventMesh",
+		},
+		{
+			name:            "other errors keep the status code",
+			givenStatusCode: http.StatusServiceUnavailable,
+			givenBody:       `{"code":503,"message":"service unavailable"}`,
+			wantResponse: ErrorResponse{
+				StatusCode: http.StatusServiceUnavailable,
+				Code:       "503",
+				Message:    "service unavailable",
+				Class:      ErrorClassOther,
+			},
+			wantStatus:      http.StatusServiceUnavailable,
+			wantDescription: "publishing to EventMesh failed",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			response := ParseErrorResponse(tc.givenStatusCode, []byte(tc.givenBody))
+
+			// then
+			assert.Equal(t, tc.wantResponse, response)
+			assert.Equal(t, tc.wantStatus, response.Status())
+			assert.Equal(t, tc.wantDescription, response.Description())
+		})
+	}
+}
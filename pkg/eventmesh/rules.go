@@ -0,0 +1,98 @@
+package eventmesh
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrRuleWithoutTypes   = errors.New("rule has no event type patterns")
+	ErrInvalidQos         = errors.New("invalid qos")
+	ErrInvalidContentMode = errors.New("invalid content mode")
+	ErrReservedHeader     = errors.New("header is reserved")
+
+	// reservedHeaders are set by the sender and cannot be overridden by the rules.
+	reservedHeaders = []string{"Qos", "Accept", "Content-Type"} //nolint:gochecknoglobals // immutable list.
+)
+
+// Rules configure the publish requests to EventMesh per event type.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule configures the publish requests of the events whose type matches one of its patterns.
+type Rule struct {
+	// Types are the event type patterns, they support the NATS wildcards `*` and `>`.
+	Types []string `json:"types"`
+	// Qos is the quality of service of the events, AT_LEAST_ONCE if empty.
+	Qos Qos `json:"qos,omitempty"`
+	// ContentMode is the content mode of the requests, it is chosen by the CloudEvents SDK if empty.
+	ContentMode ContentMode `json:"contentMode,omitempty"`
+	// Headers are additional headers of the requests.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// LoadRules reads the rules from the given YAML or JSON file and validates them.
+func LoadRules(file string) (*Rules, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read EventMesh rules: %w", err)
+	}
+	rules := &Rules{}
+	if err := yaml.UnmarshalStrict(content, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse EventMesh rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate returns an error if a rule has no patterns, an unknown qos or content mode, or sets a reserved header.
+func (r *Rules) Validate() error {
+	for i, rule := range r.Rules {
+		if len(rule.Types) == 0 {
+			return fmt.Errorf("rule %d: %w", i, ErrRuleWithoutTypes)
+		}
+		if rule.Qos != "" && rule.Qos != QosAtLeastOnce && rule.Qos != QosAtMostOnce {
+			return fmt.Errorf("rule %d: %w: %s", i, ErrInvalidQos, rule.Qos)
+		}
+		if rule.ContentMode != "" && rule.ContentMode != ContentModeStructured && rule.ContentMode != ContentModeBinary {
+			return fmt.Errorf("rule %d: %w: %s", i, ErrInvalidContentMode, rule.ContentMode)
+		}
+		for name := range rule.Headers {
+			if slices.Contains(reservedHeaders, http.CanonicalHeaderKey(name)) {
+				return fmt.Errorf("rule %d: %w: %s", i, ErrReservedHeader, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule matching the given event type with the default qos applied,
+// or a rule with the default qos if none matches. It can be called on nil Rules.
+func (r *Rules) Match(eventType string) Rule {
+	defaultRule := Rule{Qos: QosAtLeastOnce}
+	if r == nil {
+		return defaultRule
+	}
+	for _, rule := range r.Rules {
+		matches := slices.ContainsFunc(rule.Types, func(pattern string) bool {
+			return eventtype.Matches(pattern, eventType)
+		})
+		if !matches {
+			continue
+		}
+		if rule.Qos == "" {
+			rule.Qos = QosAtLeastOnce
+		}
+		return rule
+	}
+	return defaultRule
+}
@@ -0,0 +1,154 @@
+package eventmesh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenFile string
+		wantRules *Rules
+		wantErr   error
+	}{
+		{
+			name: "valid rules",
+			givenFile: `
+rules:
+  - types: ["prefix.app.telemetry.>"]
+    qos: AT_MOST_ONCE
+    contentMode: binary
+    headers:
+      x-priority: low
+  - types: ["prefix.app.order.*.v1"]
+    contentMode: structured
+`,
+			wantRules: &Rules{Rules: []Rule{
+				{
+					Types:       []string{"prefix.app.telemetry.>"},
+					Qos:         QosAtMostOnce,
+					ContentMode: ContentModeBinary,
+					Headers:     map[string]string{"x-priority": "low"},
+				},
+				{Types: []string{"prefix.app.order.*.v1"}, ContentMode: ContentModeStructured},
+			}},
+		},
+		{
+			name:      "rule without types",
+			givenFile: `{"rules": [{"qos": "AT_MOST_ONCE"}]}`,
+			wantErr:   ErrRuleWithoutTypes,
+		},
+		{
+			name:      "invalid qos",
+			givenFile: `{"rules": [{"types": [">"], "qos": "EXACTLY_ONCE"}]}`,
+			wantErr:   ErrInvalidQos,
+		},
+		{
+			name:      "invalid content mode",
+			givenFile: `{"rules": [{"types": [">"], "contentMode": "batched"}]}`,
+			wantErr:   ErrInvalidContentMode,
+		},
+		{
+			name:      "reserved header",
+			givenFile: `{"rules": [{"types": [">"], "headers": {"QOS": "AT_MOST_ONCE"}}]}`,
+			wantErr:   ErrReservedHeader,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			file := filepath.Join(t.TempDir(), "rules.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tc.givenFile), 0o600))
+
+			// when
+			rules, err := LoadRules(file)
+
+			// then
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRules, rules)
+		})
+	}
+}
+
+func TestRules_Match(t *testing.T) {
+	t.Parallel()
+
+	rules := &Rules{Rules: []Rule{
+		{Types: []string{"prefix.app.telemetry.>"}, Qos: QosAtMostOnce, ContentMode: ContentModeBinary},
+		{Types: []string{"prefix.app.order.created.v1", "prefix.*.order.deleted.v1"}, Headers: map[string]string{
+			"x-priority": "high",
+		}},
+		{Types: []string{">"}, Qos: QosAtMostOnce},
+	}}
+
+	testCases := []struct {
+		name      string
+		givenRule *Rules
+		eventType string
+		wantRule  Rule
+	}{
+		{
+			name:      "nil rules",
+			eventType: "prefix.app.order.created.v1",
+			wantRule:  Rule{Qos: QosAtLeastOnce},
+		},
+		{
+			name:      "no matching rule",
+			givenRule: &Rules{Rules: rules.Rules[:2]},
+			eventType: "prefix.app.order.updated.v1",
+			wantRule:  Rule{Qos: QosAtLeastOnce},
+		},
+		{
+			name:      "multi token wildcard",
+			givenRule: rules,
+			eventType: "prefix.app.telemetry.cpu.v1",
+			wantRule:  rules.Rules[0],
+		},
+		{
+			name:      "default qos of matching rule",
+			givenRule: rules,
+			eventType: "prefix.other.order.deleted.v1",
+			wantRule: Rule{
+				Types:   rules.Rules[1].Types,
+				Qos:     QosAtLeastOnce,
+				Headers: rules.Rules[1].Headers,
+			},
+		},
+		{
+			name:      "first matching rule wins",
+			givenRule: rules,
+			eventType: "prefix.app.order.created.v1",
+			wantRule: Rule{
+				Types:   rules.Rules[1].Types,
+				Qos:     QosAtLeastOnce,
+				Headers: rules.Rules[1].Headers,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			rule := tc.givenRule.Match(tc.eventType)
+
+			// then
+			assert.Equal(t, tc.wantRule, rule)
+		})
+	}
+}
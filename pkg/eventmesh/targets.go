@@ -0,0 +1,117 @@
+package eventmesh
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultTarget is the name of the target configured by the environment, it receives all events without a route.
+const DefaultTarget = "default"
+
+var (
+	ErrTargetNameInvalid    = errors.New("target name is empty or reserved")
+	ErrTargetNameDuplicate  = errors.New("duplicate target name")
+	ErrTargetIncomplete     = errors.New("target requires publishURL, tokenEndpoint and clientID")
+	ErrRouteUnknownTarget   = errors.New("route references an unknown target")
+	ErrRouteWithoutCriteria = errors.New("route has neither sources nor types")
+)
+
+// Targets are additional EventMesh instances and the routes which pick one of them per event.
+type Targets struct {
+	Targets []Target `json:"targets"`
+	Routes  []Route  `json:"routes"`
+}
+
+// Target is an EventMesh instance with its own credentials.
+type Target struct {
+	Name          string `json:"name"`
+	PublishURL    string `json:"publishURL"`
+	TokenEndpoint string `json:"tokenEndpoint"`
+	ClientID      string `json:"clientID"`
+	// ClientSecret is only required for the client_secret client authentication.
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// Namespace is the namespace of the target which is used as the event source, BEB_NAMESPACE is used if empty.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Route sends the events to its target if their source or their type matches.
+type Route struct {
+	Target string `json:"target"`
+	// Sources are the application names or sources of the events.
+	Sources []string `json:"sources,omitempty"`
+	// Types are the event type patterns, they support the NATS wildcards `*` and `>`.
+	Types []string `json:"types,omitempty"`
+}
+
+// LoadTargets reads the targets and routes from the given YAML or JSON file and validates them.
+func LoadTargets(file string) (*Targets, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read EventMesh targets: %w", err)
+	}
+	targets := &Targets{}
+	if err := yaml.UnmarshalStrict(content, targets); err != nil {
+		return nil, fmt.Errorf("failed to parse EventMesh targets: %w", err)
+	}
+	if err := targets.Validate(); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// Validate returns an error if a target is incomplete or not unique, or if a route has no criteria
+// or references an unknown target.
+func (t *Targets) Validate() error {
+	names := []string{DefaultTarget}
+	for i, target := range t.Targets {
+		if target.Name == "" || target.Name == DefaultTarget {
+			return fmt.Errorf("target %d: %w: %q", i, ErrTargetNameInvalid, target.Name)
+		}
+		if slices.Contains(names, target.Name) {
+			return fmt.Errorf("target %d: %w: %s", i, ErrTargetNameDuplicate, target.Name)
+		}
+		if target.PublishURL == "" || target.TokenEndpoint == "" || target.ClientID == "" {
+			return fmt.Errorf("target %s: %w", target.Name, ErrTargetIncomplete)
+		}
+		names = append(names, target.Name)
+	}
+	for i, route := range t.Routes {
+		if !slices.Contains(names, route.Target) {
+			return fmt.Errorf("route %d: %w: %s", i, ErrRouteUnknownTarget, route.Target)
+		}
+		if len(route.Sources) == 0 && len(route.Types) == 0 {
+			return fmt.Errorf("route %d: %w", i, ErrRouteWithoutCriteria)
+		}
+	}
+	return nil
+}
+
+// Config returns a copy of the given config with the publish URL, the credentials and the namespace of the target.
+// The credential files of the given config are not used for the target.
+func (t *Target) Config(cfg *env.EventMeshConfig) *env.EventMeshConfig {
+	targetCfg := *cfg
+	targetCfg.EventMeshPublishURL = t.PublishURL
+	targetCfg.TokenEndpoint = t.TokenEndpoint
+	targetCfg.ClientID = t.ClientID
+	targetCfg.ClientSecret = t.ClientSecret
+	targetCfg.ClientIDFile, targetCfg.ClientSecretFile = "", ""
+	if t.Namespace != "" {
+		targetCfg.EventMeshNamespace = t.Namespace
+	}
+	return &targetCfg
+}
+
+// GoString implements the fmt.GoStringer interface and hides the client secret.
+func (t Target) GoString() string {
+	redacted := t
+	if redacted.ClientSecret != "" {
+		redacted.ClientSecret = "***"
+	}
+	type plain Target
+	return fmt.Sprintf("%#v", plain(redacted))
+}
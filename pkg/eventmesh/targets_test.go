@@ -0,0 +1,138 @@
+package eventmesh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTargets(t *testing.T) {
+	t.Parallel()
+
+	const target = `{"name": "eu", "publishURL": "https://eu/events", "tokenEndpoint": "https://eu/token", ` +
+		`"clientID": "id", "clientSecret": "secret"}`
+
+	testCases := []struct {
+		name        string
+		givenFile   string
+		wantTargets *Targets
+		wantErr     error
+	}{
+		{
+			name: "valid targets",
+			givenFile: `
+targets:
+  - name: eu
+    publishURL: https://eu/events
+    tokenEndpoint: https://eu/token
+    clientID: id
+    clientSecret: secret
+    namespace: /eu/namespace
+routes:
+  - target: eu
+    sources: ["commerce"]
+  - target: default
+    types: ["prefix.commerce.internal.>"]
+`,
+			wantTargets: &Targets{
+				Targets: []Target{{
+					Name:          "eu",
+					PublishURL:    "https://eu/events",
+					TokenEndpoint: "https://eu/token",
+					ClientID:      "id",
+					ClientSecret:  "secret",
+					Namespace:     "/eu/namespace",
+				}},
+				Routes: []Route{
+					{Target: "eu", Sources: []string{"commerce"}},
+					{Target: DefaultTarget, Types: []string{"prefix.commerce.internal.>"}},
+				},
+			},
+		},
+		{
+			name:      "reserved target name",
+			givenFile: `{"targets": [{"name": "default"}]}`,
+			wantErr:   ErrTargetNameInvalid,
+		},
+		{
+			name:      "duplicate target name",
+			givenFile: fmt.Sprintf(`{"targets": [%s, %s]}`, target, target),
+			wantErr:   ErrTargetNameDuplicate,
+		},
+		{
+			name:      "incomplete target",
+			givenFile: `{"targets": [{"name": "eu", "publishURL": "https://eu/events"}]}`,
+			wantErr:   ErrTargetIncomplete,
+		},
+		{
+			name:      "route to unknown target",
+			givenFile: fmt.Sprintf(`{"targets": [%s], "routes": [{"target": "us", "types": [">"]}]}`, target),
+			wantErr:   ErrRouteUnknownTarget,
+		},
+		{
+			name:      "route without criteria",
+			givenFile: fmt.Sprintf(`{"targets": [%s], "routes": [{"target": "eu"}]}`, target),
+			wantErr:   ErrRouteWithoutCriteria,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			file := filepath.Join(t.TempDir(), "targets.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tc.givenFile), 0o600))
+
+			// when
+			targets, err := LoadTargets(file)
+
+			// then
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTargets, targets)
+		})
+	}
+}
+
+func TestTarget_Config(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cfg := &env.EventMeshConfig{
+		EventMeshPublishURL: "https://default/events",
+		TokenEndpoint:       "https://default/token",
+		ClientID:            "default-id",
+		ClientSecret:        "default-secret",
+		EventMeshNamespace:  "/default/namespace",
+		MaxIdleConns:        10,
+	}
+	target := Target{
+		Name:          "eu",
+		PublishURL:    "https://eu/events",
+		TokenEndpoint: "https://eu/token",
+		ClientID:      "eu-id",
+		ClientSecret:  "eu-secret",
+	}
+
+	// when
+	targetCfg := target.Config(cfg)
+
+	// then
+	assert.Equal(t, "https://eu/events", targetCfg.EventMeshPublishURL)
+	assert.Equal(t, "https://eu/token", targetCfg.TokenEndpoint)
+	assert.Equal(t, "eu-id", targetCfg.ClientID)
+	assert.Equal(t, "eu-secret", targetCfg.ClientSecret)
+	assert.Equal(t, "/default/namespace", targetCfg.EventMeshNamespace)
+	assert.Equal(t, 10, targetCfg.MaxIdleConns)
+	assert.Equal(t, "https://default/events", cfg.EventMeshPublishURL)
+	assert.NotContains(t, fmt.Sprintf("%#v", target), "eu-secret")
+}
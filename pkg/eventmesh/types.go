@@ -5,4 +5,16 @@ type Qos string
 const (
 	// QosAtLeastOnce the quality of service supported by EMS to send Events with at least once guarantee.
 	QosAtLeastOnce Qos = "AT_LEAST_ONCE"
+	// QosAtMostOnce the quality of service supported by EMS to send Events without delivery guarantee.
+	QosAtMostOnce Qos = "AT_MOST_ONCE"
+)
+
+// ContentMode is the CloudEvents HTTP content mode of the publish requests.
+type ContentMode string
+
+const (
+	// ContentModeStructured sends the whole event as JSON in the request body.
+	ContentModeStructured ContentMode = "structured"
+	// ContentModeBinary sends the event attributes as headers and the event data in the request body.
+	ContentModeBinary ContentMode = "binary"
 )
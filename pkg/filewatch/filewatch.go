@@ -0,0 +1,57 @@
+package filewatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+)
+
+// Watch checks the given files in the given interval until the context is done and calls onChange once one of them
+// changed. Files which cannot be read are reported to onError and checked again in the next interval, e.g. because
+// they are replaced in the middle of a rotation. Nothing is watched if no files or no interval are given.
+func Watch(ctx context.Context, files []string, interval time.Duration, onChange func(), onError func(error)) {
+	if len(files) == 0 || interval <= 0 {
+		return
+	}
+	checksums, err := Checksums(files)
+	if err != nil {
+		onError(err)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := Checksums(files)
+				if err != nil {
+					onError(err)
+					continue
+				}
+				if bytes.Equal(current, checksums) {
+					continue
+				}
+				checksums = current
+				onChange()
+			}
+		}
+	}()
+}
+
+// Checksums returns the concatenated checksums of the given files.
+func Checksums(files []string) ([]byte, error) {
+	checksums := make([]byte, 0, len(files)*sha256.Size)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		checksum := sha256.Sum256(content)
+		checksums = append(checksums, checksum[:]...)
+	}
+	return checksums, nil
+}
@@ -0,0 +1,68 @@
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, []byte("initial"), 0o600))
+	var changes, errs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Watch(ctx, []string{file}, 10*time.Millisecond, func() { changes.Add(1) }, func(error) { errs.Add(1) })
+
+	// when the file is unchanged
+	time.Sleep(50 * time.Millisecond)
+
+	// then
+	assert.Equal(t, int32(0), changes.Load())
+
+	// when the file is removed in the middle of a rotation
+	require.NoError(t, os.Remove(file))
+
+	// then
+	require.Eventually(t, func() bool { return errs.Load() > 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(0), changes.Load())
+
+	// when the file is rotated
+	require.NoError(t, os.WriteFile(file, []byte("rotated"), 0o600))
+
+	// then
+	require.Eventually(t, func() bool { return changes.Load() == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), changes.Load())
+}
+
+func TestChecksums(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	require.NoError(t, os.WriteFile(first, []byte("first"), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte("second"), 0o600))
+
+	// when
+	checksums, err := Checksums([]string{first, second})
+	swapped, swappedErr := Checksums([]string{second, first})
+	_, missingErr := Checksums([]string{first, filepath.Join(dir, "missing")})
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, swappedErr)
+	assert.Len(t, checksums, 64)
+	assert.NotEqual(t, checksums, swapped)
+	assert.ErrorIs(t, missingErr, os.ErrNotExist)
+}
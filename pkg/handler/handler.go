@@ -3,7 +3,9 @@ package handler
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,14 +14,20 @@ import (
 	ceevent "github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/gorilla/mux"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/alias"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/defaulter"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/enrich"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/rewrite"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy/api"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/policy"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/receiver"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/subscribed"
@@ -29,11 +37,20 @@ import (
 	"github.com/kyma-project/eventing-manager/pkg/logger"
 )
 
+// errDeniedByPolicy is returned for legacy events denied by a policy, whose response is already written.
+var errDeniedByPolicy = errors.New("event is denied by policy")
+
 // EventingHandler is responsible for receiving HTTP requests and dispatching them to the Backend.
 // It also assures that the messages received are compliant with the Cloud Events spec.
 type EventingHandler interface {
 	Start(ctx context.Context) error
 }
+
+// RouteRegistrar registers additional endpoints on the request router of the Handler.
+type RouteRegistrar interface {
+	RegisterRoutes(router *mux.Router)
+}
+
 type Handler struct {
 	Name string
 	// Receiver receives incoming HTTP requests
@@ -41,8 +58,16 @@ type Handler struct {
 	// Sender sends requests to the broker
 	Sender        sender.GenericSender
 	HealthChecker health.Checker
-	// Defaulter sets default values to incoming events
+	// Aliases map deprecated event types to their new types
+	Aliases *alias.Registry
+	// Enricher sets extensions on the built events
+	Enricher *enrich.Enricher
+	// Policies decide whether the built events are published
+	Policies *policy.Engine
+	// Defaulter sets default values to the events received by the CloudEvents endpoint before they are validated
 	Defaulter ceclient.EventDefaulter
+	// LegacyDefaulter sets default values to the events received by the legacy endpoint
+	LegacyDefaulter ceclient.EventDefaulter
 	// LegacyTransformer handles transformations needed to handle legacy events
 	LegacyTransformer legacy.RequestToCETransformer
 	// RequestTimeout timeout for outgoing requests
@@ -53,6 +78,8 @@ type Handler struct {
 	Logger *logger.Logger
 	// Options configures HTTP server
 	Options *options.Options
+	// RouteRegistrars register additional backend specific endpoints
+	RouteRegistrars []RouteRegistrar
 	// collector collects metrics
 	collector metrics.PublishingMetricsCollector
 	// eventTypeCleaner cleans the cloud event type
@@ -76,7 +103,11 @@ func New(receiver *receiver.HTTPMessageReceiver, sender sender.GenericSender, he
 		Receiver:            receiver,
 		Sender:              sender,
 		HealthChecker:       healthChecker,
+		Aliases:             nil,
+		Enricher:            nil,
+		Policies:            nil,
 		Defaulter:           nil,
+		LegacyDefaulter:     nil,
 		LegacyTransformer:   legacyTransformer,
 		RequestTimeout:      requestTimeout,
 		SubscribedProcessor: subscribedProcessor,
@@ -102,6 +133,9 @@ func (h *Handler) setupMux() {
 		h.maxBytes(h.SubscribedProcessor.ExtractEventsFromSubscriptions)).Methods(http.MethodGet)
 	router.HandleFunc(health.ReadinessURI, h.maxBytes(h.HealthChecker.ReadinessCheck))
 	router.HandleFunc(health.LivenessURI, h.maxBytes(h.HealthChecker.LivenessCheck))
+	for _, registrar := range h.RouteRegistrars {
+		registrar.RegisterRoutes(router)
+	}
 	h.router = router
 }
 
@@ -111,6 +145,14 @@ func (h *Handler) Start(ctx context.Context) error {
 	return h.Receiver.StartListen(ctx, h.router, h.Logger)
 }
 
+// Router returns the request router of the Handler without starting its receiver, so it can be served by another one.
+func (h *Handler) Router() http.Handler {
+	if h.router == nil {
+		h.setupMux()
+	}
+	return h.router
+}
+
 // maxBytes installs a MaxBytesReader onto the request, so that incoming request that is larger than a given size
 // will cause an error.
 func (h *Handler) maxBytes(f http.HandlerFunc) http.HandlerFunc {
@@ -127,20 +169,28 @@ func (h *Handler) handleSendEventAndRecordMetricsLegacy(
 	writer http.ResponseWriter, request *http.Request, event *ceevent.Event,
 ) error {
 	err := h.sendEventAndRecordMetrics(request.Context(), event, h.Sender.URL(), request.Header)
-	if err != nil {
+	// the legacy API has no response for accepted events, so they are reported as published
+	if err != nil && !isAccepted(err) {
 		h.namedLogger().Error(err)
 		httpStatus := http.StatusInternalServerError
 		var pubErr sender.PublishError
 		if errors.As(err, &pubErr) {
 			httpStatus = pubErr.Code()
 		}
+		setRetryAfterHeader(writer, err)
+		var typedErr sender.TypedError
+		if errors.As(err, &typedErr) && typedErr.Type() != "" {
+			legacy.WriteJSONResponse(writer, legacy.ErrorResponseBackend(httpStatus, typedErr.Type(), err.Error()))
+			return err
+		}
 		h.LegacyTransformer.WriteCEResponseAsLegacyResponse(writer, httpStatus, event, err.Error())
 		return err
 	}
 	return nil
 }
 
-// handlePublishLegacyEvent handles the publishing of events for Subscription v1alpha2 CRD.
+// handlePublishLegacyEvent handles the publishing of events for Subscription v1alpha2 CRD, and for Subscription
+// v1alpha1 CRD as well in case the active backend is JetStream.
 // It writes to the user request if any error occurs.
 // Otherwise, return the published event.
 func (h *Handler) handlePublishLegacyEvent(w http.ResponseWriter, r *http.Request,
@@ -152,46 +202,74 @@ func (h *Handler) handlePublishLegacyEvent(w http.ResponseWriter, r *http.Reques
 		//nolint:nilnil // this will be removed once subscription v1alpha1 is removed.
 		return nil, nil
 	}
+	applyDefaults(r.Context(), h.LegacyDefaulter, ceEvent)
 
-	// build a new cloud event instance as per specifications per backend
-	event, err := h.ceBuilder.Build(*ceEvent)
-	if err != nil {
-		legacy.WriteJSONResponse(w, legacy.ErrorResponseBadRequest(err.Error()))
-		return nil, err
+	// In case: the active backend is JetStream
+	// then we will publish event on both possible subjects
+	// i.e. with prefix (`sap.kyma.custom`) and without prefix
+	// this behaviour will be deprecated when we remove support for JetStream with Subscription `exact` typeMatching
+	builders := []builder.CloudEventBuilder{h.ceBuilder}
+	if h.activeBackend == env.JetStreamBackend {
+		builders = append(builders, h.v1alpha1Builder())
 	}
 
-	err = h.handleSendEventAndRecordMetricsLegacy(w, r, event)
-	if err != nil {
-		return nil, err
+	// build and enrich new cloud event instances as per specifications per backend and per Subscription version, for
+	// the new type of a deprecated type too, before any of them is published
+	source := ceEvent.Source()
+	received, deprecated := h.resolveAlias(ceEvent)
+	events := make([]*ceevent.Event, 0, len(builders)*len(received))
+	for _, ceBuilder := range builders {
+		for i := range received {
+			event, err := ceBuilder.Build(*received[i])
+			if errors.Is(err, legacy.ErrMissingEventTypeVersion) {
+				// a type without a version cannot be subscribed to by Subscription v1alpha1 CRD
+				h.namedLogger().Warnw("Skipping the event for Subscription v1alpha1", "id", received[i].ID(),
+					"error", err)
+				continue
+			}
+			if err != nil {
+				legacy.WriteJSONResponse(w, legacy.ErrorResponseBadRequest(err.Error()))
+				return nil, err
+			}
+			h.Enricher.Enrich(event, source, r.Header)
+			if decision := h.Policies.Evaluate(event, source, r.Header); decision.Denied() {
+				legacy.WriteJSONResponse(w, legacy.ErrorResponsePolicyViolation(decision.Message))
+				return nil, errDeniedByPolicy
+			}
+			events = append(events, event)
+		}
 	}
 
-	return event, err
-}
-
-// handlePublishLegacyEventV1alpha1 handles the publishing of events for Subscription v1alpha1 CRD.
-// It writes to the user request if any error occurs.
-// Otherwise, return the published event.
-func (h *Handler) handlePublishLegacyEventV1alpha1(w http.ResponseWriter, r *http.Request,
-	data *api.PublishRequestData,
-) (*ceevent.Event, error) {
-	event, _ := h.LegacyTransformer.WriteLegacyRequestsToCE(w, data)
-	if event == nil {
-		h.namedLogger().Error("Failed to transform legacy event to CloudEvent, event is nil")
-		//nolint:nilnil // this will be removed once subscription v1alpha1 is removed.
-		return nil, nil
+	for i, event := range events {
+		request := r
+		// the senders build the events for Subscription v1alpha2 again only, from the received events
+		if i < len(received) {
+			request = r.WithContext(builder.WithReceivedEvent(r.Context(), received[i]))
+		}
+		if err = h.handleSendEventAndRecordMetricsLegacy(w, request, event); err != nil {
+			return nil, err
+		}
 	}
+	h.warnDeprecated(w, deprecated, source)
 
-	err := h.handleSendEventAndRecordMetricsLegacy(w, r, event)
-	if err != nil {
-		return nil, err
-	}
+	return events[0], nil
+}
 
-	return event, err
+// v1alpha1Builder returns the builder of the events for Subscription v1alpha1 CRD, which applies the rewrite rules of
+// the cloud event builder (if any) as well.
+func (h *Handler) v1alpha1Builder() builder.CloudEventBuilder {
+	v1alpha1Builder := h.LegacyTransformer.V1alpha1Builder()
+	if rewriteBuilder, ok := h.ceBuilder.(*rewrite.Builder); ok {
+		return rewriteBuilder.Wrap(v1alpha1Builder)
+	}
+	return v1alpha1Builder
 }
 
 // publishLegacyEventsAsCE converts an incoming request in legacy event format to a cloudevent and dispatches it using
 // the configured GenericSender.
 func (h *Handler) publishLegacyEventsAsCE(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(defaulter.WithReceivedTime(r.Context(), time.Now()))
+
 	// extract publish data from request
 	publishRequestData, errResp, _ := h.LegacyTransformer.ExtractPublishRequestData(r)
 	if errResp != nil {
@@ -207,20 +285,6 @@ func (h *Handler) publishLegacyEventsAsCE(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// publish event for Subscription v1alpha1
-	// In case: the active backend is JetStream
-	// then we will publish event on both possible subjects
-	// i.e. with prefix (`sap.kyma.custom`) and without prefix
-	// this behaviour will be deprecated when we remove support for JetStream with Subscription `exact` typeMatching
-	if h.activeBackend == env.JetStreamBackend {
-		publishedEvent, err = h.handlePublishLegacyEventV1alpha1(w, r, publishRequestData)
-		// if publishedEvent is nil, then it means that the publishing failed
-		// and the response is already returned to the user
-		if err != nil {
-			return
-		}
-	}
-
 	// return success response to user
 	// change response as per old error codes
 	h.LegacyTransformer.WriteCEResponseAsLegacyResponse(w, http.StatusNoContent, publishedEvent, "")
@@ -229,9 +293,9 @@ func (h *Handler) publishLegacyEventsAsCE(w http.ResponseWriter, r *http.Request
 // publishCloudEvents validates an incoming cloudevent and dispatches it using
 // the configured GenericSender.
 func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := defaulter.WithReceivedTime(r.Context(), time.Now())
 
-	event, err := extractCloudEventFromRequest(r)
+	event, err := extractCloudEventFromRequest(ctx, r, h.Defaulter)
 	if err != nil {
 		h.namedLogger().With().Error(err)
 		e := writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
@@ -241,59 +305,112 @@ func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventTypeOriginal := event.Type()
-
-	//nolint:nestif // it will be improved when v1alpha1 is deprecated.
-	if !strings.HasPrefix(eventTypeOriginal, h.OldEventTypePrefix) {
-		// build a new cloud event instance as per specifications per backend
-		event, err = h.ceBuilder.Build(*event)
-		if err != nil {
+	// build and enrich the events as per specifications per backend, for the new type of a deprecated type too
+	source := event.Source()
+	received, deprecated := h.resolveAlias(event)
+	events := make([]*ceevent.Event, len(received))
+	for i := range received {
+		if events[i], err = h.buildCloudEvent(received[i]); err != nil {
 			e := writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
 			if e != nil {
 				h.namedLogger().Error(e)
 			}
 			return
 		}
-	} else {
-		eventTypeClean, err := h.eventTypeCleaner.Clean(eventTypeOriginal)
-		if err != nil {
-			h.namedLogger().Error(err)
-			e := writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+		h.Enricher.Enrich(events[i], source, r.Header)
+		if decision := h.Policies.Evaluate(events[i], source, r.Header); decision.Denied() {
+			e := writeResponse(w, http.StatusForbidden, []byte(decision.Message))
 			if e != nil {
 				h.namedLogger().Error(e)
 			}
 			return
 		}
-		event.SetType(eventTypeClean)
 	}
 
-	err = h.sendEventAndRecordMetrics(ctx, event, h.Sender.URL(), r.Header)
+	err = h.sendEventsAndRecordMetrics(ctx, received, events, h.Sender.URL(), r.Header)
+	if isAccepted(err) {
+		h.warnDeprecated(w, deprecated, source)
+		err = writeResponse(w, http.StatusAccepted, []byte(""))
+		if err != nil {
+			h.namedLogger().With().Error(err)
+		}
+		return
+	}
 	if err != nil {
 		httpStatus := http.StatusInternalServerError
 		var pubErr sender.PublishError
 		if errors.As(err, &pubErr) {
 			httpStatus = pubErr.Code()
 		}
+		setRetryAfterHeader(w, err)
 		w.WriteHeader(httpStatus)
 		h.namedLogger().With().Error(err)
 		return
 	}
+	h.warnDeprecated(w, deprecated, source)
 	err = writeResponse(w, http.StatusNoContent, []byte(""))
 	if err != nil {
 		h.namedLogger().With().Error(err)
 	}
 }
 
-// extractCloudEventFromRequest converts an incoming CloudEvent request to an Event.
-func extractCloudEventFromRequest(r *http.Request) (*ceevent.Event, error) {
+// buildCloudEvent builds a new cloud event instance as per specifications per backend. The types of the events with
+// the old event type prefix are cleaned only, they are not built, so the rewrite rules of the builder do not apply to
+// them.
+func (h *Handler) buildCloudEvent(event *ceevent.Event) (*ceevent.Event, error) {
+	if !strings.HasPrefix(event.Type(), h.OldEventTypePrefix) {
+		return h.ceBuilder.Build(*event)
+	}
+	eventTypeClean, err := h.eventTypeCleaner.Clean(event.Type())
+	if err != nil {
+		h.namedLogger().Error(err)
+		return nil, err
+	}
+	event.SetType(eventTypeClean)
+	return event, nil
+}
+
+// resolveAlias returns the events to publish for the given event, and its alias if the type of the event is deprecated.
+// An event with a deprecated type is published under its new type, and under the deprecated type as well until the end
+// of the deprecation window. Both events keep the ID of the given event, as they report the same occurrence, so
+// consumers which deduplicate events by their source and ID must take the type into account during the window.
+func (h *Handler) resolveAlias(event *ceevent.Event) ([]*ceevent.Event, *alias.Alias) {
+	deprecated, ok := h.Aliases.Lookup(event.Type())
+	if !ok {
+		return []*ceevent.Event{event}, nil
+	}
+	aliased := event.Clone()
+	aliased.SetType(deprecated.NewType)
+	if !deprecated.DualPublish(time.Now()) {
+		return []*ceevent.Event{&aliased}, &deprecated
+	}
+	return []*ceevent.Event{event, &aliased}, &deprecated
+}
+
+// warnDeprecated counts an event published with the deprecated type of the given alias (if any), and warns the client
+// about the deprecation in the Warning header of the response. It must be called once the event was published only.
+func (h *Handler) warnDeprecated(writer http.ResponseWriter, deprecated *alias.Alias, source string) {
+	if deprecated == nil {
+		return
+	}
+	h.collector.RecordDeprecatedEventType(deprecated.Type, source)
+	writer.Header().Add(internal.HeaderWarning, deprecated.Warning(time.Now()))
+}
+
+// extractCloudEventFromRequest converts an incoming CloudEvent request to an Event. The given defaulter is applied
+// before the Event is validated, so the attributes it sets are not required in the request.
+func extractCloudEventFromRequest(ctx context.Context, r *http.Request,
+	eventDefaulter ceclient.EventDefaulter,
+) (*ceevent.Event, error) {
 	message := cehttp.NewMessageFromHttpRequest(r)
 	defer func() { _ = message.Finish(nil) }()
 
-	event, err := binding.ToEvent(context.Background(), message)
+	event, err := binding.ToEvent(ctx, message)
 	if err != nil {
 		return nil, err
 	}
 
+	applyDefaults(ctx, eventDefaulter, event)
 	err = event.Validate()
 	if err != nil {
 		return nil, err
@@ -301,24 +418,48 @@ func extractCloudEventFromRequest(r *http.Request) (*ceevent.Event, error) {
 	return event, nil
 }
 
+// sendEventsAndRecordMetrics dispatches the built Events one after another and stops at the first error. The events are
+// reported as accepted if one of them was accepted only. The senders can build the events again from the received
+// ones, which are carried by the context of each event.
+func (h *Handler) sendEventsAndRecordMetrics(ctx context.Context, received, events []*ceevent.Event,
+	host string, header http.Header,
+) error {
+	var accepted error
+	for i, event := range events {
+		eventCtx := ctx
+		// the events with the old event type prefix are not built, so there is no received event to build again
+		if received[i] != event {
+			eventCtx = builder.WithReceivedEvent(ctx, received[i])
+		}
+		err := h.sendEventAndRecordMetrics(eventCtx, event, host, header)
+		if isAccepted(err) {
+			accepted = err
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return accepted
+}
+
 // sendEventAndRecordMetrics dispatches an Event and records metrics based on dispatch success.
 func (h *Handler) sendEventAndRecordMetrics(ctx context.Context, event *ceevent.Event,
 	host string, header http.Header,
 ) error {
 	ctx, cancel := context.WithTimeout(ctx, h.RequestTimeout)
 	defer cancel()
-	h.applyDefaults(ctx, event)
 	tracing.AddTracingContextToCEExtensions(header, event)
 	start := time.Now()
 	err := h.Sender.Send(ctx, event)
 	duration := time.Since(start)
-	if err != nil {
+	if err != nil && !isAccepted(err) {
 		var pubErr sender.PublishError
 		code := 500
 		if errors.As(err, &pubErr) {
 			code = pubErr.Code()
 		}
-		h.collector.RecordBackendLatency(duration, code, host)
+		h.recordBackendLatency(duration, code, host)
 		return err
 	}
 	originalEventType := event.Type()
@@ -334,9 +475,37 @@ func (h *Handler) sendEventAndRecordMetrics(ctx context.Context, event *ceevent.
 			originalEventType = event.Type()
 		}
 	}
-	h.collector.RecordEventType(originalEventType, event.Source(), http.StatusNoContent)
-	h.collector.RecordBackendLatency(duration, http.StatusNoContent, host)
-	return nil
+	code := http.StatusNoContent
+	if err != nil {
+		code = http.StatusAccepted
+	}
+	h.collector.RecordEventType(originalEventType, event.Source(), code)
+	h.recordBackendLatency(duration, code, host)
+	return err
+}
+
+// recordBackendLatency records the backend latency, unless the sender records it itself, e.g. per destination.
+func (h *Handler) recordBackendLatency(duration time.Duration, statusCode int, host string) {
+	if sender.RecordsLatency(h.Sender) {
+		return
+	}
+	h.collector.RecordBackendLatency(duration, statusCode, host)
+}
+
+// isAccepted returns true if the error signals that the event was accepted for delayed delivery.
+func isAccepted(err error) bool {
+	var pubErr sender.PublishError
+	return errors.As(err, &pubErr) && pubErr.Code() == http.StatusAccepted
+}
+
+// setRetryAfterHeader sets the Retry-After header in seconds if the error carries a retry hint.
+func setRetryAfterHeader(writer http.ResponseWriter, err error) {
+	var retryAfterErr sender.RetryAfterError
+	if !errors.As(err, &retryAfterErr) || retryAfterErr.RetryAfter() <= 0 {
+		return
+	}
+	seconds := int(math.Ceil(retryAfterErr.RetryAfter().Seconds()))
+	writer.Header().Set(internal.HeaderRetryAfter, strconv.Itoa(seconds))
 }
 
 // writeResponse writes the HTTP response given the status code and response body.
@@ -350,10 +519,10 @@ func writeResponse(writer http.ResponseWriter, statusCode int, respBody []byte)
 	return err
 }
 
-// applyDefaults applies the default values (if any) to the given Cloud Event.
-func (h *Handler) applyDefaults(ctx context.Context, event *ceevent.Event) {
-	if h.Defaulter != nil {
-		newEvent := h.Defaulter(ctx, *event)
+// applyDefaults applies the default values of the given defaulter (if any) to the given Cloud Event.
+func applyDefaults(ctx context.Context, eventDefaulter ceclient.EventDefaulter, event *ceevent.Event) {
+	if eventDefaulter != nil {
+		newEvent := eventDefaulter(ctx, *event)
 		*event = newEvent
 	}
 }
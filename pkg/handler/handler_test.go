@@ -6,14 +6,25 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/application/applicationtest"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/application/fake"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/alias"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/defaulter"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/enrich"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype/eventtypetest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/rewrite"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy/api"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy/legacytest"
@@ -21,6 +32,7 @@ import (
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/histogram/mocks"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/policy"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/jetstream"
@@ -178,6 +190,38 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 			wantStatus: 507,
 			wantTEF:    metricstest.MakeTEFBackendDuration(507, ""),
 		},
+		{
+			name: "Publish binary CloudEvent which is accepted for delayed delivery",
+			fields: fields{
+				Sender: &GenericSenderStub{
+					Err:        common.ErrAccepted,
+					BackendURL: "FOO",
+				},
+				collector:        metrics.NewCollector(latency),
+				eventTypeCleaner: &eventtypetest.CleanerStub{},
+			},
+			args: args{
+				request: CreateValidBinaryRequest(t),
+			},
+			wantStatus: 202,
+			wantTEF: metricstest.MakeTEFBackendDuration(202, "FOO") +
+				metricstest.MakeTEFEventTypePublished(202, "testapp1023", "order.created.v1"),
+		},
+		{
+			name: "Publish binary Cloudevent with a wrapped sender that records the backend latency itself",
+			fields: fields{
+				Sender: &wrappingSenderStub{
+					sender: &latencyRecordingSenderStub{GenericSenderStub: &GenericSenderStub{BackendURL: "FOO,BAR"}},
+				},
+				collector:        metrics.NewCollector(latency),
+				eventTypeCleaner: &eventtypetest.CleanerStub{},
+			},
+			args: args{
+				request: CreateValidBinaryRequest(t),
+			},
+			wantStatus: 204,
+			wantTEF:    metricstest.MakeTEFEventTypePublished(204, "testapp1023", "order.created.v1"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,6 +261,561 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 	}
 }
 
+const deprecatedEventTypesTEF = `
+# HELP eventing_epp_deprecated_event_types_total The total number of events published with a deprecated event type by publisher
+# TYPE eventing_epp_deprecated_event_types_total counter
+eventing_epp_deprecated_event_types_total{event_source="testapp1023",event_type="order.created.v1"} 1
+`
+
+func TestHandler_publishCloudEvents_Aliases(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                 string
+		givenDeprecatedUntil time.Time
+		givenErr             sender.PublishError
+		wantStatus           int
+		wantTypes            []string
+		wantWarning          string
+		wantDeprecated       string
+	}{
+		{
+			name:                 "should publish under both types during the deprecation window",
+			givenDeprecatedUntil: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantStatus:           http.StatusNoContent,
+			wantTypes:            []string{"prefix.testapp1023.order.created.v1", "prefix.testapp1023.order.placed.v1"},
+			wantWarning: `299 - "event type order.created.v1 is deprecated, publish order.placed.v1 instead, ` +
+				`it is published under both names until 2999-01-01T00:00:00Z"`,
+			wantDeprecated: deprecatedEventTypesTEF,
+		},
+		{
+			name:                 "should publish under the new type after the deprecation window",
+			givenDeprecatedUntil: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantStatus:           http.StatusNoContent,
+			wantTypes:            []string{"prefix.testapp1023.order.placed.v1"},
+			wantWarning:          `299 - "event type order.created.v1 is deprecated, publish order.placed.v1 instead"`,
+			wantDeprecated:       deprecatedEventTypesTEF,
+		},
+		{
+			name:                 "should neither warn nor count the deprecated type if the publish fails",
+			givenDeprecatedUntil: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC),
+			givenErr:             common.ErrInsufficientStorage,
+			wantStatus:           http.StatusInsufficientStorage,
+			wantTypes:            []string{"prefix.testapp1023.order.created.v1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			collector := metrics.NewCollector(latency)
+			messageSender := &recordingSenderStub{err: tc.givenErr}
+			h := &Handler{
+				Sender:    messageSender,
+				Logger:    logger,
+				collector: collector,
+				ceBuilder: builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), nil, logger),
+				Options:   &options.Options{},
+				Aliases: alias.NewRegistry(&alias.Aliases{Aliases: []alias.Alias{{
+					Type:            "order.created.v1",
+					NewType:         "order.placed.v1",
+					DeprecatedUntil: tc.givenDeprecatedUntil,
+				}}}, logger),
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+			}
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, CreateValidStructuredRequest(t))
+
+			// then
+			assert.Equal(t, tc.wantStatus, writer.Result().StatusCode)
+			assert.Equal(t, tc.wantTypes, messageSender.types)
+			for _, event := range messageSender.events {
+				assert.Equal(t, "8945ec08-256b-11eb-9928-acde48001122", event.ID(), "the aliased event must keep the ID")
+			}
+			assert.Equal(t, tc.wantWarning, writer.Result().Header.Get(internal.HeaderWarning))
+			metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, tc.wantDeprecated,
+				metrics.DeprecatedEventTypesKey)
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_Rewrite(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenType string
+		wantType  string
+	}{
+		{
+			name:      "should rewrite the type",
+			givenType: "order.created.v1",
+			wantType:  "prefix.testapp1023.order.placed.v1",
+		},
+		{
+			name:      "should not rewrite the type with the old event type prefix",
+			givenType: epptestingutils.OldEventTypePrefix + ".testapp1023.order.created.v1",
+			wantType:  epptestingutils.OldEventTypePrefix + ".testapp1023.order.created.v1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			file := filepath.Join(t.TempDir(), "rules.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(`rules:
+- name: order
+  type: (.*)order\.created(.*)
+  newType: ${1}order.placed${2}
+`), 0o600))
+			rules, err := rewrite.LoadRules(file)
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			collector := metrics.NewCollector(latency)
+			genericBuilder := builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), nil, logger)
+			messageSender := &recordingSenderStub{}
+			h := &Handler{
+				Sender:    messageSender,
+				Logger:    logger,
+				collector: collector,
+				ceBuilder: rewrite.NewBuilder(genericBuilder, rules, collector, logger),
+				eventTypeCleaner: eventtypetest.CleanerFunc(func(eventType string) (string, error) {
+					return eventType, nil
+				}),
+				Options:            &options.Options{},
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+			}
+			request := CreateValidBinaryRequest(t)
+			request.Header.Set("Ce-Type", tc.givenType)
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, request)
+
+			// then
+			assert.Equal(t, http.StatusNoContent, writer.Result().StatusCode)
+			assert.Equal(t, []string{tc.wantType}, messageSender.types)
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_Enrichment(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenHeader    string
+		wantStatusCode int
+		wantExtensions map[string]any
+	}{
+		{
+			name:           "should publish the enriched event",
+			givenHeader:    "t1",
+			wantStatusCode: http.StatusNoContent,
+			wantExtensions: map[string]any{"region": "eu10", "tenant": "t1"},
+		},
+		{
+			name:           "should check the enriched event against the policies",
+			givenHeader:    "blocked",
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			collector := metrics.NewCollector(latency)
+			rules := &enrich.Rules{Rules: []enrich.Rule{{Name: "tenant", Extensions: []enrich.Extension{
+				{Name: "region", Value: "eu10"},
+				{Name: "tenant", Header: "X-Tenant"},
+			}}}}
+			require.NoError(t, rules.Validate())
+			policies := &policy.Policies{Policies: []policy.Policy{{
+				Name:      "blocked-tenant",
+				Condition: `extensions.tenant == "blocked"`,
+				Effect:    policy.EffectDeny,
+			}}}
+			require.NoError(t, policies.Compile())
+			messageSender := &recordingSenderStub{}
+			h := &Handler{
+				Sender:             messageSender,
+				Logger:             logger,
+				collector:          collector,
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), nil, logger),
+				Options:            &options.Options{},
+				Enricher:           enrich.NewEnricher(rules, nil, collector, logger),
+				Policies:           policy.NewEngine(policies, nil, collector, logger),
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+			}
+			request := CreateValidStructuredRequest(t)
+			request.Header.Set("X-Tenant", tc.givenHeader)
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, request)
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Result().StatusCode)
+			if tc.wantExtensions == nil {
+				assert.Empty(t, messageSender.events)
+				return
+			}
+			require.Len(t, messageSender.events, 1)
+			for name, value := range tc.wantExtensions {
+				assert.Equal(t, value, messageSender.events[0].Extensions()[name])
+			}
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_Defaulter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenDefaulter bool
+		wantStatusCode int
+	}{
+		{
+			name:           "should reject events without id",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "should publish events without id once it is defaulted",
+			givenDefaulter: true,
+			wantStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			messageSender := &recordingSenderStub{}
+			h := &Handler{
+				Sender:             messageSender,
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), nil, logger),
+				Options:            &options.Options{},
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+			}
+			if tc.givenDefaulter {
+				h.Defaulter, err = defaulter.New([]string{defaulter.NameID, defaulter.NameTime,
+					defaulter.NameReceivedTime}, defaulter.UUIDv4, "")
+				require.NoError(t, err)
+			}
+			request := httptest.NewRequest(http.MethodPost, "http://localhost/publish", strings.NewReader(`{
+				"specversion":"1.0",
+				"type":"order.created.v1",
+				"source":"testapp1023",
+				"data":{"foo":"bar"}
+			}`))
+			request.Header.Add("Content-Type", "application/cloudevents+json")
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, request)
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Result().StatusCode)
+			if !tc.givenDefaulter {
+				assert.Empty(t, messageSender.events)
+				return
+			}
+			require.Len(t, messageSender.events, 1)
+			event := messageSender.events[0]
+			assert.NotEmpty(t, event.ID())
+			assert.False(t, event.Time().IsZero())
+			assert.Equal(t, cetypes.Timestamp{Time: event.Time()}, event.Extensions()[defaulter.ReceivedTimeExtensionName])
+		})
+	}
+}
+
+func TestHandler_Policies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenDryRun    bool
+		givenLegacy    bool
+		wantStatusCode int
+		wantErrorType  string
+		wantTypes      []string
+	}{
+		{
+			name:           "should reject denied cloud events with 403",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "should publish denied cloud events in dry run",
+			givenDryRun:    true,
+			wantStatusCode: http.StatusNoContent,
+			wantTypes:      []string{"prefix.testapp1023.order.created.v1"},
+		},
+		{
+			name:           "should reject denied legacy events with a policy violation",
+			givenLegacy:    true,
+			wantStatusCode: http.StatusForbidden,
+			wantErrorType:  legacy.ErrorTypePolicyViolation,
+		},
+		{
+			name:           "should publish denied legacy events in dry run",
+			givenDryRun:    true,
+			givenLegacy:    true,
+			wantStatusCode: http.StatusOK,
+			wantTypes:      []string{"prefix.testapp.object.created.v1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			collector := metrics.NewCollector(latency)
+			policies := &policy.Policies{DryRun: tc.givenDryRun, Policies: []policy.Policy{{
+				Name:      "deny-all",
+				Condition: "true",
+				Effect:    policy.EffectDeny,
+				Message:   "events are not allowed",
+			}}}
+			require.NoError(t, policies.Compile())
+			appLister := NewApplicationListerOrDie(context.Background(), "testapp")
+			messageSender := &recordingSenderStub{}
+			h := &Handler{
+				Sender:             messageSender,
+				Logger:             logger,
+				collector:          collector,
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), nil, logger),
+				LegacyTransformer:  legacy.NewTransformer("namespace", "im.a.prefix", appLister),
+				Options:            &options.Options{},
+				Policies:           policy.NewEngine(policies, nil, collector, logger),
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+			}
+			writer := httptest.NewRecorder()
+
+			// when
+			if tc.givenLegacy {
+				h.publishLegacyEventsAsCE(writer, legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"))
+			} else {
+				h.publishCloudEvents(writer, CreateValidStructuredRequest(t))
+			}
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Result().StatusCode)
+			assert.Equal(t, tc.wantTypes, messageSender.types)
+			if tc.wantStatusCode != http.StatusForbidden {
+				return
+			}
+			body, err := io.ReadAll(writer.Result().Body)
+			require.NoError(t, err)
+			if tc.givenLegacy {
+				nok := &api.Error{}
+				require.NoError(t, json.Unmarshal(body, nok))
+				assert.Equal(t, tc.wantErrorType, nok.Type)
+				assert.Equal(t, "events are not allowed", nok.Message)
+				return
+			}
+			assert.Equal(t, "events are not allowed", string(body))
+		})
+	}
+}
+
+func TestHandler_publishLegacyEventsAsCE_V1alpha1(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		givenRules      string
+		givenAliases    bool
+		givenCondition  string
+		wantStatusCode  int
+		wantTypes       []string
+		wantDeprecation bool
+	}{
+		{
+			name:           "should publish the enriched events for Subscription v1alpha1 as well",
+			wantStatusCode: http.StatusOK,
+			wantTypes: []string{
+				"prefix.testapp.object.created.v1",
+				epptestingutils.OldEventTypePrefix + ".testapp.object.created.v1",
+			},
+		},
+		{
+			name: "should rewrite the events for Subscription v1alpha1 as well",
+			givenRules: `rules:
+- name: object
+  type: (.*)object\.created(.*)
+  newType: ${1}object.placed${2}
+`,
+			wantStatusCode: http.StatusOK,
+			wantTypes: []string{
+				"prefix.testapp.object.placed.v1",
+				epptestingutils.OldEventTypePrefix + ".testapp.object.placed.v1",
+			},
+		},
+		{
+			name: "should skip the events for Subscription v1alpha1 if the rewritten type has no version",
+			givenRules: `rules:
+- name: object
+  type: .*object\.created.*
+  newType: objectcreated
+`,
+			wantStatusCode: http.StatusOK,
+			wantTypes:      []string{"prefix.testapp.objectcreated"},
+		},
+		{
+			name:           "should publish the events for Subscription v1alpha1 under both types of a deprecated type",
+			givenAliases:   true,
+			wantStatusCode: http.StatusOK,
+			wantTypes: []string{
+				"prefix.testapp.object.created.v1",
+				"prefix.testapp.object.placed.v1",
+				epptestingutils.OldEventTypePrefix + ".testapp.object.created.v1",
+				epptestingutils.OldEventTypePrefix + ".testapp.object.placed.v1",
+			},
+			wantDeprecation: true,
+		},
+		{
+			name:           "should check the events for Subscription v1alpha1 against the policies before publishing",
+			givenCondition: `event.type == "` + epptestingutils.OldEventTypePrefix + `.testapp.object.created.v1"`,
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			logger, err := emlogger.New("json", "info")
+			require.NoError(t, err)
+			latency := new(mocks.BucketsProvider)
+			latency.On("Buckets").Return(nil)
+			collector := metrics.NewCollector(latency)
+			rules := &enrich.Rules{Rules: []enrich.Rule{{Name: "region", Extensions: []enrich.Extension{
+				{Name: "region", Value: "eu10"},
+			}}}}
+			require.NoError(t, rules.Validate())
+			appLister := NewApplicationListerOrDie(context.Background(), "testapp")
+			var ceBuilder builder.CloudEventBuilder = builder.NewGenericBuilder("prefix",
+				cleaner.NewJetStreamCleaner(logger), appLister, logger)
+			if tc.givenRules != "" {
+				file := filepath.Join(t.TempDir(), "rules.yaml")
+				require.NoError(t, os.WriteFile(file, []byte(tc.givenRules), 0o600))
+				rules, err := rewrite.LoadRules(file)
+				require.NoError(t, err)
+				ceBuilder = rewrite.NewBuilder(ceBuilder, rules, collector, logger)
+			}
+			var aliases *alias.Registry
+			if tc.givenAliases {
+				aliases = alias.NewRegistry(&alias.Aliases{Aliases: []alias.Alias{{
+					Type:            "object.created.v1",
+					NewType:         "object.placed.v1",
+					DeprecatedUntil: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC),
+				}}}, logger)
+			}
+			var policies *policy.Engine
+			if tc.givenCondition != "" {
+				denied := &policy.Policies{Policies: []policy.Policy{{
+					Name:      "denied-type",
+					Condition: tc.givenCondition,
+					Effect:    policy.EffectDeny,
+				}}}
+				require.NoError(t, denied.Compile())
+				policies = policy.NewEngine(denied, nil, collector, logger)
+			}
+			messageSender := &recordingSenderStub{}
+			h := &Handler{
+				Sender:    messageSender,
+				Logger:    logger,
+				collector: collector,
+				ceBuilder: ceBuilder,
+				LegacyTransformer: legacy.NewTransformer("namespace", epptestingutils.OldEventTypePrefix,
+					appLister),
+				Options:            &options.Options{},
+				Aliases:            aliases,
+				Enricher:           enrich.NewEnricher(rules, nil, collector, logger),
+				Policies:           policies,
+				OldEventTypePrefix: epptestingutils.OldEventTypePrefix,
+				activeBackend:      env.JetStreamBackend,
+			}
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishLegacyEventsAsCE(writer, legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Result().StatusCode)
+			assert.Equal(t, tc.wantTypes, messageSender.types)
+			assert.Equal(t, tc.wantDeprecation, writer.Result().Header.Get(internal.HeaderWarning) != "")
+			// the request has no event-id, so all events share the ID generated for the v1alpha2 event
+			for _, event := range messageSender.events {
+				assert.Equal(t, "eu10", event.Extensions()["region"])
+				assert.Equal(t, messageSender.events[0].ID(), event.ID())
+			}
+			if tc.wantStatusCode == http.StatusOK {
+				resp := &api.PublishResponse{}
+				require.NoError(t, json.NewDecoder(writer.Result().Body).Decode(resp))
+				assert.NotEmpty(t, resp.EventID)
+				assert.Equal(t, resp.EventID, messageSender.events[0].ID())
+			}
+		})
+	}
+}
+
+// recordingSenderStub records all sent events and their types.
+type recordingSenderStub struct {
+	err    sender.PublishError
+	types  []string
+	events []*ceevent.Event
+}
+
+func (s *recordingSenderStub) Send(_ context.Context, event *ceevent.Event) sender.PublishError {
+	s.types = append(s.types, event.Type())
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *recordingSenderStub) URL() string {
+	return ""
+}
+
 func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 	// define common given variables
 	appLister := NewApplicationListerOrDie(context.Background(), "testapp")
@@ -232,6 +831,8 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 		givenCollector         metrics.PublishingMetricsCollector
 		givenRequest           *http.Request
 		wantHTTPStatus         int
+		wantErrorType          string
+		wantRetryAfter         string
 		wantTEF                string
 	}{
 		{
@@ -282,6 +883,23 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			wantHTTPStatus:         500,
 			wantTEF:                metricstest.MakeTEFBackendDuration(500, "FOO"),
 		},
+		{
+			name: "Send valid legacy event but the backend rejects it with a classified error",
+			givenSender: &GenericSenderStub{
+				Err: common.BackendPublishError{
+					HTTPCode: http.StatusTooManyRequests, Info: "quota exceeded", ErrorType: "quota_exceeded",
+					RetryHint: 5 * time.Second,
+				},
+				BackendURL: "FOO",
+			},
+			givenLegacyTransformer: legacy.NewTransformer("namespace", "im.a.prefix", appLister),
+			givenCollector:         metrics.NewCollector(latency),
+			givenRequest:           legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
+			wantHTTPStatus:         http.StatusTooManyRequests,
+			wantErrorType:          "quota_exceeded",
+			wantRetryAfter:         "5",
+			wantTEF:                metricstest.MakeTEFBackendDuration(http.StatusTooManyRequests, "FOO"),
+		},
 		{
 			name: "Send invalid legacy event",
 			givenSender: &GenericSenderStub{
@@ -291,6 +909,7 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			givenCollector:         metrics.NewCollector(latency),
 			givenRequest:           legacytest.InvalidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
 			wantHTTPStatus:         400,
+			wantErrorType:          legacy.ErrorTypeValidationViolation,
 			// this is a client error. We do record an error metric for requests that cannot even be decoded correctly.
 			wantTEF: "",
 		},
@@ -318,6 +937,7 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 
 			// then
 			require.Equal(t, tt.wantHTTPStatus, writer.Result().StatusCode)
+			require.Equal(t, tt.wantRetryAfter, writer.Result().Header.Get("Retry-After"))
 			body, err := io.ReadAll(writer.Result().Body)
 			require.NoError(t, err)
 
@@ -329,6 +949,7 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 				nok := &api.Error{}
 				err = json.Unmarshal(body, nok)
 				require.NoError(t, err)
+				require.Equal(t, tt.wantErrorType, nok.Type)
 			}
 
 			metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector, tt.wantTEF)
@@ -378,6 +999,80 @@ func CreateInvalidStructuredRequest(t *testing.T) *http.Request {
 	return req
 }
 
+func Test_setRetryAfterHeader(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenErr  error
+		wantValue string
+	}{
+		{
+			name:      "should not set the header for errors without retry hint",
+			givenErr:  common.ErrClientNoConnection,
+			wantValue: "",
+		},
+		{
+			name:      "should round the retry hint up to seconds",
+			givenErr:  &retryAfterErrorStub{retryAfter: 1500 * time.Millisecond},
+			wantValue: "2",
+		},
+		{
+			name: "should set the header for backend errors with retry hint",
+			givenErr: common.BackendPublishError{
+				HTTPCode: http.StatusTooManyRequests, RetryHint: 30 * time.Second,
+			},
+			wantValue: "30",
+		},
+		{
+			name:      "should not set the header for elapsed retry hints",
+			givenErr:  &retryAfterErrorStub{retryAfter: -time.Second},
+			wantValue: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			writer := httptest.NewRecorder()
+
+			// when
+			setRetryAfterHeader(writer, tc.givenErr)
+
+			// then
+			assert.Equal(t, tc.wantValue, writer.Header().Get("Retry-After"))
+		})
+	}
+}
+
+type latencyRecordingSenderStub struct {
+	*GenericSenderStub
+}
+
+func (s *latencyRecordingSenderStub) RecordsLatency() bool { return true }
+
+type wrappingSenderStub struct {
+	sender sender.GenericSender
+}
+
+func (s *wrappingSenderStub) Send(ctx context.Context, event *ceevent.Event) sender.PublishError {
+	return s.sender.Send(ctx, event)
+}
+func (s *wrappingSenderStub) URL() string                  { return s.sender.URL() }
+func (s *wrappingSenderStub) Unwrap() sender.GenericSender { return s.sender }
+
+type retryAfterErrorStub struct {
+	retryAfter time.Duration
+}
+
+func (e *retryAfterErrorStub) Error() string             { return e.Message() }
+func (e *retryAfterErrorStub) Code() int                 { return http.StatusServiceUnavailable }
+func (e *retryAfterErrorStub) Message() string           { return "unavailable" }
+func (e *retryAfterErrorStub) RetryAfter() time.Duration { return e.retryAfter }
+
 // CreateValidBinaryRequestV1Alpha2 creates a valid binary cloudevent as http request.
 func CreateValidBinaryRequest(t *testing.T) *http.Request {
 	t.Helper()
@@ -105,7 +105,7 @@ func Test_extractCloudEventFromRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotEvent, err := extractCloudEventFromRequest(tt.args.request)
+			gotEvent, err := extractCloudEventFromRequest(context.Background(), tt.args.request, nil)
 			if tt.wantType != "" {
 				tt.wants.event.SetType(tt.wantType)
 			}
@@ -82,3 +82,28 @@ func WithReadinessCheck(h http.HandlerFunc) CheckerOpt {
 		c.readinessCheck = h
 	}
 }
+
+// IsReady runs the readiness check of the given Checker and reports whether it wrote a 2XX status code.
+func IsReady(c Checker, r *http.Request) bool {
+	w := &statusRecorder{header: http.Header{}, statusCode: StatusCodeHealthy}
+	c.ReadinessCheck(w, r)
+	return w.statusCode >= http.StatusOK && w.statusCode < http.StatusMultipleChoices
+}
+
+// statusRecorder is a minimal http.ResponseWriter which only records the written status code.
+type statusRecorder struct {
+	header     http.Header
+	statusCode int
+}
+
+func (s *statusRecorder) Header() http.Header {
+	return s.header
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+}
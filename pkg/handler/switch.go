@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// drainPollInterval is the interval in which the in-flight requests of a replaced handler are checked.
+const drainPollInterval = 10 * time.Millisecond
+
+// Switch is an http.Handler which forwards each request to the currently active handler. The active handler can be
+// replaced at runtime without dropping requests, the requests in flight are completed by the replaced handler.
+type Switch struct {
+	target atomic.Pointer[switchTarget]
+}
+
+// switchTarget is a handler together with the number of its requests in flight.
+type switchTarget struct {
+	handler  http.Handler
+	inflight atomic.Int64
+}
+
+// ServeHTTP implements the http.Handler interface. It answers with 503 until the first handler is active.
+func (s *Switch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := s.acquire()
+	if target == nil {
+		http.Error(w, "no backend is active", http.StatusServiceUnavailable)
+		return
+	}
+	defer target.inflight.Add(-1)
+	target.handler.ServeHTTP(w, r)
+}
+
+// acquire returns the active target and counts the request as in flight. The target is loaded again if it was
+// replaced in the meantime, so a replaced target is never acquired once it is draining.
+func (s *Switch) acquire() *switchTarget {
+	for {
+		target := s.target.Load()
+		if target == nil {
+			return nil
+		}
+		target.inflight.Add(1)
+		if s.target.Load() == target {
+			return target
+		}
+		target.inflight.Add(-1)
+	}
+}
+
+// Swap activates the given handler and returns a function which waits until the requests in flight of the replaced
+// handler are completed or the given context is done.
+func (s *Switch) Swap(h http.Handler) (drain func(ctx context.Context) error) {
+	old := s.target.Swap(&switchTarget{handler: h})
+	return func(ctx context.Context) error {
+		if old == nil {
+			return nil
+		}
+		ticker := time.NewTicker(drainPollInterval)
+		defer ticker.Stop()
+		for old.inflight.Load() > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		return nil
+	}
+}
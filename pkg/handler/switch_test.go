@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitch_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	// given
+	s := &Switch{}
+
+	// when
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	// when
+	require.NoError(t, s.Swap(statusHandler(http.StatusOK))(context.Background()))
+	recorder = httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// when
+	require.NoError(t, s.Swap(statusHandler(http.StatusNoContent))(context.Background()))
+	recorder = httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// then
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestSwitch_Swap_Drain(t *testing.T) {
+	t.Parallel()
+
+	// given
+	s := &Switch{}
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Swap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	done := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- recorder.Code
+	}()
+	<-started
+
+	// when
+	drain := s.Swap(statusHandler(http.StatusNoContent))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// then the replaced handler is not drained while its request is in flight
+	require.ErrorIs(t, drain(ctx), context.DeadlineExceeded)
+
+	// then new requests are served by the new handler
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+	// then the request in flight is completed by the replaced handler
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+	require.NoError(t, drain(context.Background()))
+}
+
+func statusHandler(statusCode int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(statusCode)
+	})
+}
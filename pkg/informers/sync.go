@@ -20,15 +20,19 @@ type waitForCacheSyncFunc func(stopCh <-chan struct{}) map[schema.GroupVersionRe
 func WaitForCacheSyncOrDie(ctx context.Context, dc dynamicinformer.DynamicSharedInformerFactory,
 	logger *logger.Logger,
 ) {
+	if err := WaitForCacheSync(ctx, dc); err != nil {
+		logger.WithContext().Fatalw("Failed to sync informer caches", "error", err)
+	}
+}
+
+// WaitForCacheSync waits for the cache to sync and returns an error if the sync fails.
+func WaitForCacheSync(ctx context.Context, dc dynamicinformer.DynamicSharedInformerFactory) error {
 	dc.Start(ctx.Done())
 
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultResyncPeriod)
 	defer cancel()
 
-	err := hasSynced(ctx, dc.WaitForCacheSync)
-	if err != nil {
-		logger.WithContext().Fatalw("Failed to sync informer caches", "error", err)
-	}
+	return hasSynced(ctx, dc.WaitForCacheSync)
 }
 
 func hasSynced(ctx context.Context, fn waitForCacheSyncFunc) error {
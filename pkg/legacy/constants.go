@@ -21,6 +21,7 @@ const (
 	ErrorTypeMissingField        = "missing_field"
 	ErrorTypeValidationViolation = "validation_violation"
 	ErrorTypeInvalidField        = "invalid_field"
+	ErrorTypePolicyViolation     = "policy_violation"
 )
 
 // Field definitions.
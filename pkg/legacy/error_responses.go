@@ -79,6 +79,19 @@ func ErrorResponse(status int, err error) *api.PublishEventResponses {
 	return &api.PublishEventResponses{Error: &api.Error{Status: status, Message: err.Error()}}
 }
 
+// ErrorResponseBackend returns an error of type PublishEventResponses for an event the backend rejected
+// with the given status, error type and message.
+func ErrorResponseBackend(status int, errorType, message string) *api.PublishEventResponses {
+	return &api.PublishEventResponses{Error: &api.Error{Status: status, Type: errorType, Message: message}}
+}
+
+// ErrorResponsePolicyViolation returns an error of type PublishEventResponses for an event denied by a policy.
+func ErrorResponsePolicyViolation(message string) *api.PublishEventResponses {
+	return &api.PublishEventResponses{Error: &api.Error{
+		Status: http.StatusForbidden, Type: ErrorTypePolicyViolation, Message: message,
+	}}
+}
+
 // CreateMissingFieldError creates an error for a missing field.
 func CreateMissingFieldError(field any) *api.PublishEventResponses {
 	apiErrorDetail := api.ErrorDetail{
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyma-project/eventing-publisher-proxy/internal"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
 	eppapi "github.com/kyma-project/eventing-publisher-proxy/pkg/legacy/api"
 	"github.com/pkg/errors"
 )
@@ -19,6 +20,10 @@ import (
 var (
 	validEventTypeVersion = regexp.MustCompile(AllowedEventTypeVersionChars)
 	validEventID          = regexp.MustCompile(AllowedEventIDChars)
+
+	// ErrMissingEventTypeVersion is returned by the builder of the events for Subscriptions v1alpha1 if the event
+	// type has no version, e.g. once a rewrite rule replaced it with a type without a dot.
+	ErrMissingEventTypeVersion = errors.New("event type must consist of the event type and its version")
 )
 
 const (
@@ -29,10 +34,13 @@ const (
 type RequestToCETransformer interface {
 	ExtractPublishRequestData(*http.Request) (*eppapi.PublishRequestData, *eppapi.PublishEventResponses, error)
 	TransformPublishRequestToCloudEvent(*eppapi.PublishRequestData) (*ceevent.Event, error)
-	WriteLegacyRequestsToCE(http.ResponseWriter, *eppapi.PublishRequestData) (*ceevent.Event, string)
 	WriteCEResponseAsLegacyResponse(http.ResponseWriter, int, *ceevent.Event, string)
+	V1alpha1Builder() builder.CloudEventBuilder
 }
 
+// compile time check.
+var _ builder.CloudEventBuilder = &v1alpha1Builder{}
+
 type Transformer struct {
 	eventMeshNamespace string
 	eventTypePrefix    string
@@ -123,39 +131,6 @@ func (t *Transformer) ExtractPublishRequestData(request *http.Request) (*eppapi.
 	return publishRequestData, nil, nil
 }
 
-// WriteLegacyRequestsToCE transforms the legacy event to cloudevent from the given request.
-// It also returns the original event-type without cleanup as the second return type.
-func (t *Transformer) WriteLegacyRequestsToCE(writer http.ResponseWriter,
-	publishData *eppapi.PublishRequestData,
-) (*ceevent.Event, string) {
-	uncleanedAppName := publishData.ApplicationName
-
-	// clean the application name form non-alphanumeric characters
-	// handle non-existing applications
-	appName := application.GetCleanName(uncleanedAppName)
-	// check if we need to use name from application CR.
-	if t.isApplicationListerEnabled() {
-		if appObj, err := t.applicationLister.Get(uncleanedAppName); err == nil {
-			// handle existing applications
-			appName = application.GetCleanTypeOrName(appObj)
-		}
-	}
-
-	event, err := t.convertPublishRequestToCloudEvent(appName, publishData.PublishEventParameters)
-	if err != nil {
-		response := ErrorResponse(http.StatusInternalServerError, err)
-		WriteJSONResponse(writer, response)
-		return nil, ""
-	}
-
-	// prepare the original event-type without cleanup
-	eventType := formatEventType(t.eventTypePrefix, publishData.ApplicationName,
-		publishData.PublishEventParameters.PublishrequestV1.EventType,
-		publishData.PublishEventParameters.PublishrequestV1.EventTypeVersion)
-
-	return event, eventType
-}
-
 func (t *Transformer) WriteCEResponseAsLegacyResponse(writer http.ResponseWriter, statusCode int,
 	event *ceevent.Event, msg string,
 ) {
@@ -216,42 +191,58 @@ func (t *Transformer) TransformPublishRequestToCloudEvent(publishRequestData *ep
 	return &event, nil
 }
 
-// convertPublishRequestToCloudEvent converts the given publish request to a CloudEvent.
-func (t *Transformer) convertPublishRequestToCloudEvent(appName string,
-	publishRequest *eppapi.PublishEventParametersV1,
-) (*ceevent.Event, error) {
-	if !application.IsCleanName(appName) {
-		return nil, errors.New("application name should be cleaned from none-alphanumeric characters")
-	}
-
-	event := ceevent.New(ceevent.CloudEventsVersionV1)
+// V1alpha1Builder returns a builder.CloudEventBuilder which builds the events for Subscriptions v1alpha1 from the
+// CloudEvents transformed by TransformPublishRequestToCloudEvent, or from the events derived from them, e.g. under
+// the new type of a deprecated type.
+func (t *Transformer) V1alpha1Builder() builder.CloudEventBuilder {
+	return &v1alpha1Builder{transformer: t}
+}
 
-	evTime, err := time.Parse(time.RFC3339, publishRequest.PublishrequestV1.EventTime)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse time from the external publish request")
+// cleanApplicationName returns the application name cleaned from non-alphanumeric characters, or the clean type or
+// name of the application CR if the application lister is enabled and the application exists.
+func (t *Transformer) cleanApplicationName(uncleanedAppName string) string {
+	// clean the application name form non-alphanumeric characters
+	// handle non-existing applications
+	appName := application.GetCleanName(uncleanedAppName)
+	// check if we need to use name from application CR.
+	if t.isApplicationListerEnabled() {
+		if appObj, err := t.applicationLister.Get(uncleanedAppName); err == nil {
+			// handle existing applications
+			appName = application.GetCleanTypeOrName(appObj)
+		}
 	}
-	event.SetTime(evTime)
+	return appName
+}
 
-	if err := event.SetData(internal.ContentTypeApplicationJSON, publishRequest.PublishrequestV1.Data); err != nil {
-		return nil, errors.Wrap(err, "failed to set data to CloudEvent data field")
-	}
+// v1alpha1Builder builds the events for Subscriptions v1alpha1 from CloudEvents whose type is the event type and its
+// version, and whose source is the application name, as transformed by TransformPublishRequestToCloudEvent.
+type v1alpha1Builder struct {
+	transformer *Transformer
+}
 
-	// set the event id from the request if it is available
-	// otherwise generate a new one
-	if len(publishRequest.PublishrequestV1.EventID) > 0 {
-		event.SetID(publishRequest.PublishrequestV1.EventID)
-	} else {
-		event.SetID(uuid.New().String())
+// Build implements the builder.CloudEventBuilder interface.
+func (b *v1alpha1Builder) Build(event ceevent.Event) (*ceevent.Event, error) {
+	eventType, version, ok := cutLast(event.Type(), ".")
+	if !ok || eventType == "" || version == "" {
+		return nil, fmt.Errorf("%w: %q", ErrMissingEventTypeVersion, event.Type())
 	}
+	appName := b.transformer.cleanApplicationName(event.Source())
+	eventName := combineEventNameSegments(removeNonAlphanumeric(eventType))
+	prefix := removeNonAlphanumeric(b.transformer.eventTypePrefix)
+
+	built := event.Clone()
+	built.SetType(formatEventType(prefix, appName, eventName, version))
+	built.SetSource(b.transformer.eventMeshNamespace)
+	built.SetExtension(eventTypeVersionExtensionKey, version)
+	return &built, nil
+}
 
-	eventName := combineEventNameSegments(removeNonAlphanumeric(publishRequest.PublishrequestV1.EventType))
-	prefix := removeNonAlphanumeric(t.eventTypePrefix)
-	eventType := formatEventType(prefix, appName, eventName, publishRequest.PublishrequestV1.EventTypeVersion)
-	event.SetType(eventType)
-	event.SetSource(t.eventMeshNamespace)
-	event.SetExtension(eventTypeVersionExtensionKey, publishRequest.PublishrequestV1.EventTypeVersion)
-	event.SetDataContentType(internal.ContentTypeApplicationJSON)
-	return &event, nil
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
 }
 
 // combineEventNameSegments returns an eventName with exactly two segments separated by "." if the given event-type
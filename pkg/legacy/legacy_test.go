@@ -3,7 +3,6 @@ package legacy
 import (
 	"context"
 	"net/http"
-	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -114,7 +113,6 @@ func TestTransformLegacyRequestsToCE(t *testing.T) {
 			request, err := legacytest.ValidLegacyRequest(tc.wantVersion, tc.givenApplication, tc.givenEventName)
 			assert.NoError(t, err)
 
-			writer := httptest.NewRecorder()
 			app := applicationtest.NewApplication(tc.givenApplication, applicationTypeLabel(tc.givenTypeLabel))
 
 			var appLister *application.Lister
@@ -125,9 +123,10 @@ func TestTransformLegacyRequestsToCE(t *testing.T) {
 			transformer := NewTransformer("test", tc.givenPrefix, appLister)
 			publishData, errResp, _ := transformer.ExtractPublishRequestData(request)
 			assert.Nil(t, errResp)
-			gotEvent, gotEventType := transformer.WriteLegacyRequestsToCE(writer, publishData)
-			wantEventType := formatEventType(tc.givenPrefix, tc.givenApplication, tc.givenEventName, tc.wantVersion)
-			assert.Equal(t, wantEventType, gotEventType)
+			ceEvent, err := transformer.TransformPublishRequestToCloudEvent(publishData)
+			require.NoError(t, err)
+			gotEvent, err := transformer.V1alpha1Builder().Build(*ceEvent)
+			require.NoError(t, err)
 
 			// check eventType
 			gotType := gotEvent.Context.GetType()
@@ -141,6 +140,11 @@ func TestTransformLegacyRequestsToCE(t *testing.T) {
 			// check HTTP ContentType set properly
 			gotContentType := gotEvent.Context.GetDataContentType()
 			assert.Equal(t, internal.ContentTypeApplicationJSON, gotContentType)
+
+			// check the transformed CloudEvent is kept apart from the type and source
+			assert.Equal(t, "test", gotEvent.Source())
+			assert.Equal(t, ceEvent.ID(), gotEvent.ID())
+			assert.Equal(t, ceEvent.Data(), gotEvent.Data())
 		})
 	}
 }
@@ -152,20 +156,23 @@ func applicationTypeLabel(label string) map[string]string {
 	return nil
 }
 
-func TestConvertPublishRequestToCloudEvent(t *testing.T) {
+func TestV1alpha1Builder_Build(t *testing.T) {
 	givenEventID := epptestingutils.EventID
 	givenApplicationName := epptestingutils.ApplicationName
 	givenEventTypePrefix := epptestingutils.Prefix
 	givenTimeNow := time.Now().Format(time.RFC3339)
 	givenLegacyEventVersion := epptestingutils.EventVersion
-	givenPublishReqParams := &eppapi.PublishEventParametersV1{
-		PublishrequestV1: eppapi.PublishRequestV1{
-			EventID:          givenEventID,
-			EventType:        eventTypeMultiSegment,
-			EventTime:        givenTimeNow,
-			EventTypeVersion: givenLegacyEventVersion,
-			Data:             epptestingutils.EventData,
+	givenPublishRequestData := &eppapi.PublishRequestData{
+		PublishEventParameters: &eppapi.PublishEventParametersV1{
+			PublishrequestV1: eppapi.PublishRequestV1{
+				EventID:          givenEventID,
+				EventType:        eventTypeMultiSegment,
+				EventTime:        givenTimeNow,
+				EventTypeVersion: givenLegacyEventVersion,
+				Data:             epptestingutils.EventData,
+			},
 		},
+		ApplicationName: givenApplicationName,
 	}
 
 	wantEventMeshNamespace := epptestingutils.MessagingNamespace
@@ -176,7 +183,9 @@ func TestConvertPublishRequestToCloudEvent(t *testing.T) {
 	wantDataContentType := internal.ContentTypeApplicationJSON
 
 	legacyTransformer := NewTransformer(wantEventMeshNamespace, givenEventTypePrefix, nil)
-	gotEvent, err := legacyTransformer.convertPublishRequestToCloudEvent(givenApplicationName, givenPublishReqParams)
+	ceEvent, err := legacyTransformer.TransformPublishRequestToCloudEvent(givenPublishRequestData)
+	require.NoError(t, err)
+	gotEvent, err := legacyTransformer.V1alpha1Builder().Build(*ceEvent)
 	require.NoError(t, err)
 	assert.Equal(t, wantEventMeshNamespace, gotEvent.Context.GetSource())
 	assert.Equal(t, wantEventID, gotEvent.Context.GetID())
@@ -190,6 +199,18 @@ func TestConvertPublishRequestToCloudEvent(t *testing.T) {
 	assert.Equal(t, wantLegacyEventVersion, gotExtension)
 }
 
+func TestV1alpha1Builder_BuildWithoutVersion(t *testing.T) {
+	t.Parallel()
+
+	event := ceevent.New(ceevent.CloudEventsVersionV1)
+	event.SetType("objectcreated")
+	event.SetSource(epptestingutils.ApplicationName)
+
+	transformer := NewTransformer(epptestingutils.MessagingNamespace, epptestingutils.Prefix, nil)
+	_, err := transformer.V1alpha1Builder().Build(event)
+	assert.ErrorIs(t, err, ErrMissingEventTypeVersion)
+}
+
 func TestCombineEventTypeSegments(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
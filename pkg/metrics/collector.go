@@ -22,6 +22,11 @@ const (
 	// backendLatencyHelp help text for the backendLatency metric.
 	backendLatencyHelp = "The duration of sending events to the messaging server in milliseconds"
 
+	// BackendAttemptLatencyKey name of the backendAttemptLatency metric.
+	BackendAttemptLatencyKey = "eventing_epp_backend_attempt_duration_milliseconds"
+	// backendAttemptLatencyHelp help text for the backendAttemptLatency metric.
+	backendAttemptLatencyHelp = "The duration of a single attempt of sending events to the messaging server in milliseconds"
+
 	// durationKey name of the duration metric.
 	durationKey = "eventing_epp_requests_duration_seconds"
 	// durationHelp help text for the duration metric.
@@ -32,6 +37,101 @@ const (
 	// requestsHelp help text for event requests metric.
 	requestsHelp = "The total number of requests"
 
+	// ActiveBackendKey name of the activeBackend metric.
+	ActiveBackendKey = "eventing_epp_active_backend"
+	// activeBackendHelp help text for the activeBackend metric.
+	activeBackendHelp = "The backend used for publishing. `1` indicates the active backend"
+
+	// FailoverActiveDestinationKey name of the failoverActiveDestination metric.
+	FailoverActiveDestinationKey = "eventing_epp_failover_active_destination"
+	// failoverActiveDestinationHelp help text for the failoverActiveDestination metric.
+	failoverActiveDestinationHelp = "The destination of the failover used for publishing. `1` indicates the active destination"
+
+	// CircuitBreakerStateKey name of the circuitBreakerState metric.
+	CircuitBreakerStateKey = "eventing_epp_circuit_breaker_state"
+	// circuitBreakerStateHelp help text for the circuitBreakerState metric.
+	circuitBreakerStateHelp = "The state of the circuit breaker of a destination. `0` is closed, `1` is open and `2` is half-open"
+
+	// OutboxDepthKey name of the outboxDepth metric.
+	OutboxDepthKey = "eventing_epp_outbox_depth"
+	// outboxDepthHelp help text for the outboxDepth metric.
+	outboxDepthHelp = "The number of events in the outbox waiting to be replayed"
+
+	// OutboxOldestEntryAgeKey name of the outboxOldestEntryAge metric.
+	OutboxOldestEntryAgeKey = "eventing_epp_outbox_oldest_entry_age_seconds"
+	// outboxOldestEntryAgeHelp help text for the outboxOldestEntryAge metric.
+	outboxOldestEntryAgeHelp = "The age of the oldest event in the outbox in seconds"
+
+	// OutboxReplayedKey name of the outboxReplayed metric.
+	OutboxReplayedKey = "eventing_epp_outbox_replayed_total"
+	// outboxReplayedHelp help text for the outboxReplayed metric.
+	outboxReplayedHelp = "The total number of events replayed from the outbox"
+
+	// JetStreamStreamBytesKey name of the jetStreamStreamBytes metric.
+	JetStreamStreamBytesKey = "eventing_epp_jetstream_stream_bytes"
+	// jetStreamStreamBytesHelp help text for the jetStreamStreamBytes metric.
+	jetStreamStreamBytesHelp = "The number of bytes stored in the JetStream stream"
+
+	// JetStreamStreamMessagesKey name of the jetStreamStreamMessages metric.
+	JetStreamStreamMessagesKey = "eventing_epp_jetstream_stream_messages"
+	// jetStreamStreamMessagesHelp help text for the jetStreamStreamMessages metric.
+	jetStreamStreamMessagesHelp = "The number of messages stored in the JetStream stream"
+
+	// JetStreamStreamMaxBytesKey name of the jetStreamStreamMaxBytes metric.
+	JetStreamStreamMaxBytesKey = "eventing_epp_jetstream_stream_max_bytes"
+	// jetStreamStreamMaxBytesHelp help text for the jetStreamStreamMaxBytes metric.
+	jetStreamStreamMaxBytesHelp = "The maximum number of bytes of the JetStream stream. `-1` means unlimited"
+
+	// JetStreamStreamMaxMessagesKey name of the jetStreamStreamMaxMessages metric.
+	JetStreamStreamMaxMessagesKey = "eventing_epp_jetstream_stream_max_messages"
+	// jetStreamStreamMaxMessagesHelp help text for the jetStreamStreamMaxMessages metric.
+	jetStreamStreamMaxMessagesHelp = "The maximum number of messages of the JetStream stream. `-1` means unlimited"
+
+	// AdmissionSheddingKey name of the admissionShedding metric.
+	AdmissionSheddingKey = "eventing_epp_admission_shedding"
+	// admissionSheddingHelp help text for the admissionShedding metric.
+	admissionSheddingHelp = "The state of the admission control. `1` indicates that non-critical events are rejected"
+
+	// AdmissionRejectedKey name of the admissionRejected metric.
+	AdmissionRejectedKey = "eventing_epp_admission_rejected_total"
+	// admissionRejectedHelp help text for the admissionRejected metric.
+	admissionRejectedHelp = "The total number of events rejected by the admission control"
+
+	// TokenFetchLatencyKey name of the tokenFetchLatency metric.
+	TokenFetchLatencyKey = "eventing_epp_oauth_token_fetch_duration_milliseconds"
+	// tokenFetchLatencyHelp help text for the tokenFetchLatency metric.
+	tokenFetchLatencyHelp = "The duration of fetching an OAuth token from the token endpoint in milliseconds"
+
+	// TokenFetchFailuresKey name of the tokenFetchFailures metric.
+	TokenFetchFailuresKey = "eventing_epp_oauth_token_fetch_failures_total"
+	// tokenFetchFailuresHelp help text for the tokenFetchFailures metric.
+	tokenFetchFailuresHelp = "The total number of failed OAuth token fetches"
+
+	// EventMeshErrorsKey name of the eventMeshErrors metric.
+	EventMeshErrorsKey = "eventing_epp_eventmesh_errors_total"
+	// eventMeshErrorsHelp help text for the eventMeshErrors metric.
+	eventMeshErrorsHelp = "The total number of publish requests rejected by EventMesh by error class"
+
+	// RewriteRuleHitsKey name of the rewriteRuleHits metric.
+	RewriteRuleHitsKey = "eventing_epp_rewrite_rule_hits_total"
+	// rewriteRuleHitsHelp help text for the rewriteRuleHits metric.
+	rewriteRuleHitsHelp = "The total number of events rewritten by a rewrite rule"
+
+	// DeprecatedEventTypesKey name of the deprecatedEventTypes metric.
+	DeprecatedEventTypesKey = "eventing_epp_deprecated_event_types_total"
+	// deprecatedEventTypesHelp help text for the deprecatedEventTypes metric.
+	deprecatedEventTypesHelp = "The total number of events published with a deprecated event type by publisher"
+
+	// PolicyDecisionsKey name of the policyDecisions metric.
+	PolicyDecisionsKey = "eventing_epp_policy_decisions_total"
+	// policyDecisionsHelp help text for the policyDecisions metric.
+	policyDecisionsHelp = "The total number of policy decisions by policy and decision"
+
+	// EnrichedExtensionsKey name of the enrichedExtensions metric.
+	EnrichedExtensionsKey = "eventing_epp_enriched_extensions_total"
+	// enrichedExtensionsHelp help text for the enrichedExtensions metric.
+	enrichedExtensionsHelp = "The total number of extensions enriched by rule, extension and result"
+
 	// EventTypePublishedMetricKey name of the eventTypeLabel metric.
 	EventTypePublishedMetricKey = "eventing_epp_event_type_published_total"
 	// eventTypePublishedMetricHelp help text for the eventTypeLabel metric.
@@ -49,6 +149,28 @@ const (
 	eventTypeLabel = "event_type"
 	// eventSourceLabel name of the event source label used by metrics.
 	eventSourceLabel = "event_source"
+	// attemptLabel name of the retry attempt label used by metrics.
+	attemptLabel = "attempt"
+	// backendLabel name of the backend label used by metrics.
+	backendLabel = "backend"
+	// destinationLabel name of the failover destination label used by metrics.
+	destinationLabel = "destination"
+	// streamLabel name of the JetStream stream label used by metrics.
+	streamLabel = "stream"
+	// tokenEndpointLabel name of the OAuth token endpoint label used by metrics.
+	tokenEndpointLabel = "token_endpoint"
+	// errorClassLabel name of the error class label used by metrics.
+	errorClassLabel = "class"
+	// ruleLabel name of the rule label used by metrics.
+	ruleLabel = "rule"
+	// policyLabel name of the policy label used by metrics.
+	policyLabel = "policy"
+	// decisionLabel name of the decision label used by metrics.
+	decisionLabel = "decision"
+	// extensionLabel name of the CloudEvents extension label used by metrics.
+	extensionLabel = "extension"
+	// resultLabel name of the result label used by metrics.
+	resultLabel = "result"
 )
 
 // PublishingMetricsCollector interface provides a Prometheus compatible Collector with additional convenience methods
@@ -56,7 +178,23 @@ const (
 type PublishingMetricsCollector interface {
 	prometheus.Collector
 	RecordBackendLatency(duration time.Duration, statusCode int, destSvc string)
+	RecordBackendAttemptLatency(duration time.Duration, statusCode int, destSvc string, attempt int)
 	RecordEventType(eventType, eventSource string, statusCode int)
+	SetActiveBackend(backend string, active bool)
+	SetFailoverActiveDestination(destination string, active bool)
+	SetCircuitBreakerState(destSvc string, state int)
+	SetOutboxDepth(depth int)
+	SetOutboxOldestEntryAge(age time.Duration)
+	RecordOutboxReplay(statusCode int)
+	SetJetStreamStreamState(stream string, bytes, messages uint64, maxBytes, maxMessages int64)
+	SetAdmissionShedding(shedding bool)
+	RecordAdmissionRejected(statusCode int)
+	RecordTokenFetch(duration time.Duration, tokenEndpoint string, failed bool)
+	RecordEventMeshError(class, destSvc string)
+	RecordRewriteRuleHit(rule string)
+	RecordDeprecatedEventType(eventType, eventSource string)
+	RecordPolicyDecision(policy, decision string)
+	RecordEnrichedExtension(rule, extension, result string)
 	MetricsMiddleware() mux.MiddlewareFunc
 }
 
@@ -64,7 +202,8 @@ var _ PublishingMetricsCollector = &Collector{}
 
 // Collector implements the prometheus.Collector interface.
 type Collector struct {
-	backendLatency *prometheus.HistogramVec
+	backendLatency        *prometheus.HistogramVec
+	backendAttemptLatency *prometheus.HistogramVec
 
 	duration *prometheus.HistogramVec
 	requests *prometheus.CounterVec
@@ -72,6 +211,35 @@ type Collector struct {
 	eventType *prometheus.CounterVec
 
 	health *prometheus.GaugeVec
+
+	activeBackend             *prometheus.GaugeVec
+	failoverActiveDestination *prometheus.GaugeVec
+
+	circuitBreakerState *prometheus.GaugeVec
+
+	outboxDepth          *prometheus.GaugeVec
+	outboxOldestEntryAge *prometheus.GaugeVec
+	outboxReplayed       *prometheus.CounterVec
+
+	jetStreamStreamBytes       *prometheus.GaugeVec
+	jetStreamStreamMessages    *prometheus.GaugeVec
+	jetStreamStreamMaxBytes    *prometheus.GaugeVec
+	jetStreamStreamMaxMessages *prometheus.GaugeVec
+
+	admissionShedding *prometheus.GaugeVec
+	admissionRejected *prometheus.CounterVec
+
+	tokenFetchLatency  *prometheus.HistogramVec
+	tokenFetchFailures *prometheus.CounterVec
+
+	eventMeshErrors *prometheus.CounterVec
+
+	rewriteRuleHits *prometheus.CounterVec
+
+	deprecatedEventTypes *prometheus.CounterVec
+
+	policyDecisions    *prometheus.CounterVec
+	enrichedExtensions *prometheus.CounterVec
 }
 
 // NewCollector creates a new instance of Collector.
@@ -86,6 +254,15 @@ func NewCollector(latency histogram.BucketsProvider) *Collector {
 			},
 			[]string{responseCodeLabel, destSvcLabel},
 		),
+		//nolint:promlinter // we follow the same pattern as istio. so a millisecond unit if fine here
+		backendAttemptLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    BackendAttemptLatencyKey,
+				Help:    backendAttemptLatencyHelp,
+				Buckets: latency.Buckets(),
+			},
+			[]string{responseCodeLabel, destSvcLabel, attemptLabel},
+		),
 		eventType: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: EventTypePublishedMetricKey,
@@ -120,25 +297,200 @@ func NewCollector(latency histogram.BucketsProvider) *Collector {
 			},
 			nil,
 		),
+		activeBackend: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: ActiveBackendKey,
+				Help: activeBackendHelp,
+			},
+			[]string{backendLabel},
+		),
+		failoverActiveDestination: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: FailoverActiveDestinationKey,
+				Help: failoverActiveDestinationHelp,
+			},
+			[]string{destinationLabel},
+		),
+		circuitBreakerState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: CircuitBreakerStateKey,
+				Help: circuitBreakerStateHelp,
+			},
+			[]string{destSvcLabel},
+		),
+		outboxDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: OutboxDepthKey,
+				Help: outboxDepthHelp,
+			},
+			nil,
+		),
+		outboxOldestEntryAge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: OutboxOldestEntryAgeKey,
+				Help: outboxOldestEntryAgeHelp,
+			},
+			nil,
+		),
+		outboxReplayed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: OutboxReplayedKey,
+				Help: outboxReplayedHelp,
+			},
+			[]string{responseCodeLabel},
+		),
+		jetStreamStreamBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: JetStreamStreamBytesKey,
+				Help: jetStreamStreamBytesHelp,
+			},
+			[]string{streamLabel},
+		),
+		jetStreamStreamMessages: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: JetStreamStreamMessagesKey,
+				Help: jetStreamStreamMessagesHelp,
+			},
+			[]string{streamLabel},
+		),
+		jetStreamStreamMaxBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: JetStreamStreamMaxBytesKey,
+				Help: jetStreamStreamMaxBytesHelp,
+			},
+			[]string{streamLabel},
+		),
+		jetStreamStreamMaxMessages: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: JetStreamStreamMaxMessagesKey,
+				Help: jetStreamStreamMaxMessagesHelp,
+			},
+			[]string{streamLabel},
+		),
+		admissionShedding: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: AdmissionSheddingKey,
+				Help: admissionSheddingHelp,
+			},
+			nil,
+		),
+		admissionRejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: AdmissionRejectedKey,
+				Help: admissionRejectedHelp,
+			},
+			[]string{responseCodeLabel},
+		),
+		//nolint:promlinter // we follow the same pattern as istio. so a millisecond unit if fine here
+		tokenFetchLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    TokenFetchLatencyKey,
+				Help:    tokenFetchLatencyHelp,
+				Buckets: latency.Buckets(),
+			},
+			[]string{tokenEndpointLabel},
+		),
+		tokenFetchFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: TokenFetchFailuresKey,
+				Help: tokenFetchFailuresHelp,
+			},
+			[]string{tokenEndpointLabel},
+		),
+		eventMeshErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: EventMeshErrorsKey,
+				Help: eventMeshErrorsHelp,
+			},
+			[]string{errorClassLabel, destSvcLabel},
+		),
+		rewriteRuleHits: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: RewriteRuleHitsKey,
+				Help: rewriteRuleHitsHelp,
+			},
+			[]string{ruleLabel},
+		),
+		deprecatedEventTypes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: DeprecatedEventTypesKey,
+				Help: deprecatedEventTypesHelp,
+			},
+			[]string{eventTypeLabel, eventSourceLabel},
+		),
+		policyDecisions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: PolicyDecisionsKey,
+				Help: policyDecisionsHelp,
+			},
+			[]string{policyLabel, decisionLabel},
+		),
+		enrichedExtensions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: EnrichedExtensionsKey,
+				Help: enrichedExtensionsHelp,
+			},
+			[]string{ruleLabel, extensionLabel, resultLabel},
+		),
 	}
 }
 
 // Describe implements the prometheus.Collector interface Describe method.
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.backendLatency.Describe(ch)
+	c.backendAttemptLatency.Describe(ch)
 	c.eventType.Describe(ch)
 	c.requests.Describe(ch)
 	c.duration.Describe(ch)
 	c.health.Describe(ch)
+	c.activeBackend.Describe(ch)
+	c.failoverActiveDestination.Describe(ch)
+	c.circuitBreakerState.Describe(ch)
+	c.outboxDepth.Describe(ch)
+	c.outboxOldestEntryAge.Describe(ch)
+	c.outboxReplayed.Describe(ch)
+	c.jetStreamStreamBytes.Describe(ch)
+	c.jetStreamStreamMessages.Describe(ch)
+	c.jetStreamStreamMaxBytes.Describe(ch)
+	c.jetStreamStreamMaxMessages.Describe(ch)
+	c.admissionShedding.Describe(ch)
+	c.admissionRejected.Describe(ch)
+	c.tokenFetchLatency.Describe(ch)
+	c.tokenFetchFailures.Describe(ch)
+	c.eventMeshErrors.Describe(ch)
+	c.rewriteRuleHits.Describe(ch)
+	c.deprecatedEventTypes.Describe(ch)
+	c.policyDecisions.Describe(ch)
+	c.enrichedExtensions.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method.
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.backendLatency.Collect(ch)
+	c.backendAttemptLatency.Collect(ch)
 	c.eventType.Collect(ch)
 	c.requests.Collect(ch)
 	c.duration.Collect(ch)
 	c.health.Collect(ch)
+	c.activeBackend.Collect(ch)
+	c.failoverActiveDestination.Collect(ch)
+	c.circuitBreakerState.Collect(ch)
+	c.outboxDepth.Collect(ch)
+	c.outboxOldestEntryAge.Collect(ch)
+	c.outboxReplayed.Collect(ch)
+	c.jetStreamStreamBytes.Collect(ch)
+	c.jetStreamStreamMessages.Collect(ch)
+	c.jetStreamStreamMaxBytes.Collect(ch)
+	c.jetStreamStreamMaxMessages.Collect(ch)
+	c.admissionShedding.Collect(ch)
+	c.admissionRejected.Collect(ch)
+	c.tokenFetchLatency.Collect(ch)
+	c.tokenFetchFailures.Collect(ch)
+	c.eventMeshErrors.Collect(ch)
+	c.rewriteRuleHits.Collect(ch)
+	c.deprecatedEventTypes.Collect(ch)
+	c.policyDecisions.Collect(ch)
+	c.enrichedExtensions.Collect(ch)
 }
 
 // RecordLatency records a backendLatencyHelp metric.
@@ -146,6 +498,12 @@ func (c *Collector) RecordBackendLatency(duration time.Duration, statusCode int,
 	c.backendLatency.WithLabelValues(strconv.Itoa(statusCode), destSvc).Observe(float64(duration.Milliseconds()))
 }
 
+// RecordBackendAttemptLatency records a backendAttemptLatency metric for the given retry attempt.
+func (c *Collector) RecordBackendAttemptLatency(duration time.Duration, statusCode int, destSvc string, attempt int) {
+	c.backendAttemptLatency.WithLabelValues(strconv.Itoa(statusCode), destSvc, strconv.Itoa(attempt)).
+		Observe(float64(duration.Milliseconds()))
+}
+
 // SetHealthStatus updates the health metric.
 func (c *Collector) SetHealthStatus(healthy bool) {
 	var v float64
@@ -155,6 +513,99 @@ func (c *Collector) SetHealthStatus(healthy bool) {
 	c.health.WithLabelValues().Set(v)
 }
 
+// SetActiveBackend updates the activeBackend metric for the given backend.
+func (c *Collector) SetActiveBackend(backend string, active bool) {
+	var v float64
+	if active {
+		v = 1
+	}
+	c.activeBackend.WithLabelValues(backend).Set(v)
+}
+
+// SetFailoverActiveDestination updates the failoverActiveDestination metric for the given destination.
+func (c *Collector) SetFailoverActiveDestination(destination string, active bool) {
+	var v float64
+	if active {
+		v = 1
+	}
+	c.failoverActiveDestination.WithLabelValues(destination).Set(v)
+}
+
+// SetCircuitBreakerState updates the circuitBreakerState metric for the given destination.
+func (c *Collector) SetCircuitBreakerState(destSvc string, state int) {
+	c.circuitBreakerState.WithLabelValues(destSvc).Set(float64(state))
+}
+
+// SetOutboxDepth updates the outboxDepth metric.
+func (c *Collector) SetOutboxDepth(depth int) {
+	c.outboxDepth.WithLabelValues().Set(float64(depth))
+}
+
+// SetOutboxOldestEntryAge updates the outboxOldestEntryAge metric.
+func (c *Collector) SetOutboxOldestEntryAge(age time.Duration) {
+	c.outboxOldestEntryAge.WithLabelValues().Set(age.Seconds())
+}
+
+// RecordOutboxReplay records an outboxReplayed metric.
+func (c *Collector) RecordOutboxReplay(statusCode int) {
+	c.outboxReplayed.WithLabelValues(strconv.Itoa(statusCode)).Inc()
+}
+
+// SetJetStreamStreamState updates the jetStreamStream metrics for the given stream.
+func (c *Collector) SetJetStreamStreamState(stream string, bytes, messages uint64, maxBytes, maxMessages int64) {
+	c.jetStreamStreamBytes.WithLabelValues(stream).Set(float64(bytes))
+	c.jetStreamStreamMessages.WithLabelValues(stream).Set(float64(messages))
+	c.jetStreamStreamMaxBytes.WithLabelValues(stream).Set(float64(maxBytes))
+	c.jetStreamStreamMaxMessages.WithLabelValues(stream).Set(float64(maxMessages))
+}
+
+// SetAdmissionShedding updates the admissionShedding metric.
+func (c *Collector) SetAdmissionShedding(shedding bool) {
+	var v float64
+	if shedding {
+		v = 1
+	}
+	c.admissionShedding.WithLabelValues().Set(v)
+}
+
+// RecordAdmissionRejected records an admissionRejected metric.
+func (c *Collector) RecordAdmissionRejected(statusCode int) {
+	c.admissionRejected.WithLabelValues(strconv.Itoa(statusCode)).Inc()
+}
+
+// RecordTokenFetch records a tokenFetchLatency metric and a tokenFetchFailures metric if the fetch failed.
+func (c *Collector) RecordTokenFetch(duration time.Duration, tokenEndpoint string, failed bool) {
+	c.tokenFetchLatency.WithLabelValues(tokenEndpoint).Observe(float64(duration.Milliseconds()))
+	if failed {
+		c.tokenFetchFailures.WithLabelValues(tokenEndpoint).Inc()
+	}
+}
+
+// RecordEventMeshError records an eventMeshErrors metric for the given error class and destination.
+func (c *Collector) RecordEventMeshError(class, destSvc string) {
+	c.eventMeshErrors.WithLabelValues(class, destSvc).Inc()
+}
+
+// RecordRewriteRuleHit records a rewriteRuleHits metric for the given rule.
+func (c *Collector) RecordRewriteRuleHit(rule string) {
+	c.rewriteRuleHits.WithLabelValues(rule).Inc()
+}
+
+// RecordDeprecatedEventType records a deprecatedEventTypes metric for the given event type and publisher.
+func (c *Collector) RecordDeprecatedEventType(eventType, eventSource string) {
+	c.deprecatedEventTypes.WithLabelValues(eventType, eventSource).Inc()
+}
+
+// RecordPolicyDecision records a policyDecisions metric for the given policy and decision.
+func (c *Collector) RecordPolicyDecision(policy, decision string) {
+	c.policyDecisions.WithLabelValues(policy, decision).Inc()
+}
+
+// RecordEnrichedExtension records an enrichedExtensions metric for the given rule, extension and result.
+func (c *Collector) RecordEnrichedExtension(rule, extension, result string) {
+	c.enrichedExtensions.WithLabelValues(rule, extension, result).Inc()
+}
+
 // RecordEventType records an eventType metric.
 func (c *Collector) RecordEventType(eventType, eventSource string, statusCode int) {
 	c.eventType.WithLabelValues(eventType, eventSource, strconv.Itoa(statusCode)).Inc()
@@ -105,7 +105,7 @@ func MakeTEFBackendErrors() string {
 func MakeTEFEventTypePublished(code int, source, eventtype string) string {
 	tef := strings.ReplaceAll(`# HELP eventing_epp_event_type_published_total The total number of events published for a given eventTypeLabel
         # TYPE eventing_epp_event_type_published_total counter
-        eventing_epp_event_type_published_total{code="204",event_source="%%source%%",event_type="%%type%%"} 1
+        eventing_epp_event_type_published_total{code="%%code%%",event_source="%%source%%",event_type="%%type%%"} 1
 					`, "%%code%%", strconv.Itoa(code))
 	tef = strings.ReplaceAll(tef, "%%source%%", source)
 	return strings.ReplaceAll(tef, "%%type%%", eventtype)
@@ -0,0 +1,56 @@
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const rotationWatcherName = "nats-credentials-watcher"
+
+// WithAuth returns the options to authenticate with the configured auth method and to secure the connection with TLS.
+// The credentials and TLS files are read again on each reconnect, so rotated files are picked up.
+func WithAuth(cfg *env.NATSAuthConfig) ([]Opt, error) {
+	opts := make([]Opt, 0)
+	switch {
+	case cfg.NATSCredentialsFile != "":
+		opts = append(opts, nats.UserCredentials(cfg.NATSCredentialsFile))
+	case cfg.NATSNKeySeedFile != "":
+		opt, err := nats.NkeyOptionFromSeed(cfg.NATSNKeySeedFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	case cfg.NATSToken != "":
+		opts = append(opts, nats.Token(cfg.NATSToken))
+	case cfg.NATSUser != "":
+		opts = append(opts, nats.UserInfo(cfg.NATSUser, cfg.NATSPassword))
+	}
+	if cfg.NATSTLSCAFile != "" {
+		opts = append(opts, nats.RootCAs(cfg.NATSTLSCAFile))
+	}
+	if cfg.NATSTLSCertFile != "" {
+		opts = append(opts, nats.ClientCert(cfg.NATSTLSCertFile, cfg.NATSTLSKeyFile))
+	}
+	return opts, nil
+}
+
+// ReconnectOnRotation checks the given files in the given interval until the context is done and forces
+// the connection to reconnect if one of them changed, so the rotated credentials are used before the old ones expire.
+func ReconnectOnRotation(ctx context.Context, connection *nats.Conn, files []string, interval time.Duration,
+	logger *logger.Logger) {
+	namedLogger := logger.WithContext().Named(rotationWatcherName)
+	filewatch.Watch(ctx, files, interval, func() {
+		namedLogger.Infow("NATS credentials rotated, reconnecting", "files", files)
+		if err := connection.ForceReconnect(); err != nil {
+			namedLogger.Errorw("Failed to reconnect with rotated NATS credentials", "error", err)
+		}
+	}, func(err error) {
+		namedLogger.Warnw("Failed to read NATS credentials", "error", err)
+	})
+}
@@ -0,0 +1,218 @@
+package nats_test
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	eppnats "github.com/kyma-project/eventing-publisher-proxy/pkg/nats"
+	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats-server/v2/test"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestConnect_WithAuth(t *testing.T) {
+	testCases := []struct {
+		name       string
+		givenSetup func(t *testing.T, opts *server.Options) (valid, invalid env.NATSAuthConfig)
+	}{
+		{
+			name: "token",
+			givenSetup: func(t *testing.T, opts *server.Options) (env.NATSAuthConfig, env.NATSAuthConfig) {
+				t.Helper()
+				opts.Authorization = "secret-token"
+				return env.NATSAuthConfig{NATSToken: "secret-token"}, env.NATSAuthConfig{NATSToken: "wrong"}
+			},
+		},
+		{
+			name: "user and password",
+			givenSetup: func(t *testing.T, opts *server.Options) (env.NATSAuthConfig, env.NATSAuthConfig) {
+				t.Helper()
+				opts.Username, opts.Password = "publisher", "secret"
+				return env.NATSAuthConfig{NATSUser: "publisher", NATSPassword: "secret"},
+					env.NATSAuthConfig{NATSUser: "publisher", NATSPassword: "wrong"}
+			},
+		},
+		{
+			name: "nkey",
+			givenSetup: func(t *testing.T, opts *server.Options) (env.NATSAuthConfig, env.NATSAuthConfig) {
+				t.Helper()
+				valid, validPub := newUserNKeySeedFile(t)
+				invalid, _ := newUserNKeySeedFile(t)
+				opts.Nkeys = []*server.NkeyUser{{Nkey: validPub}}
+				return env.NATSAuthConfig{NATSNKeySeedFile: valid}, env.NATSAuthConfig{NATSNKeySeedFile: invalid}
+			},
+		},
+		{
+			name: "credentials file",
+			givenSetup: func(t *testing.T, opts *server.Options) (env.NATSAuthConfig, env.NATSAuthConfig) {
+				t.Helper()
+				operator := newOperator(t, opts)
+				return env.NATSAuthConfig{NATSCredentialsFile: operator.newCredentialsFile(t, "valid.creds")},
+					env.NATSAuthConfig{NATSCredentialsFile: newOperator(t, &server.Options{}).
+						newCredentialsFile(t, "invalid.creds")}
+			},
+		},
+		{
+			name: "TLS client certificate",
+			givenSetup: func(t *testing.T, opts *server.Options) (env.NATSAuthConfig, env.NATSAuthConfig) {
+				t.Helper()
+				ca := epptestingutils.NewCertificateAuthority(t)
+				serverCert, serverKey := ca.NewCertificate(t, "server")
+				clientCert, clientKey := ca.NewCertificate(t, "client")
+				otherCert, otherKey := epptestingutils.NewCertificateAuthority(t).NewCertificate(t, "other")
+				tlsCert, err := tls.LoadX509KeyPair(serverCert, serverKey)
+				require.NoError(t, err)
+				opts.TLS, opts.TLSVerify = true, true
+				opts.TLSConfig = &tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					Certificates: []tls.Certificate{tlsCert},
+					ClientCAs:    ca.Pool,
+					ClientAuth:   tls.RequireAndVerifyClientCert,
+				}
+				return env.NATSAuthConfig{NATSTLSCAFile: ca.File, NATSTLSCertFile: clientCert, NATSTLSKeyFile: clientKey},
+					env.NATSAuthConfig{NATSTLSCAFile: ca.File, NATSTLSCertFile: otherCert, NATSTLSKeyFile: otherKey}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			opts := test.DefaultTestOptions
+			opts.Port = server.RANDOM_PORT
+			valid, invalid := tc.givenSetup(t, &opts)
+			require.NoError(t, valid.Validate())
+			natsServer := test.RunServer(&opts)
+			defer natsServer.Shutdown()
+
+			// when
+			connection, err := connect(t, natsServer.ClientURL(), &valid)
+
+			// then
+			require.NoError(t, err)
+			defer connection.Close()
+			assert.Equal(t, natsgo.CONNECTED, connection.Status())
+
+			// when
+			_, err = connect(t, natsServer.ClientURL(), &invalid)
+
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReconnectOnRotation(t *testing.T) {
+	// given
+	opts := test.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	operator := newOperator(t, &opts)
+	credentialsFile := operator.newCredentialsFile(t, "user.creds")
+	natsServer := test.RunServer(&opts)
+	defer natsServer.Shutdown()
+
+	cfg := env.NATSAuthConfig{NATSCredentialsFile: credentialsFile}
+	connection, err := connect(t, natsServer.ClientURL(), &cfg)
+	require.NoError(t, err)
+	defer connection.Close()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eppnats.ReconnectOnRotation(ctx, connection, cfg.RotatedFiles(), 10*time.Millisecond, l)
+
+	// when
+	rotated := operator.newCredentialsFile(t, "rotated.creds")
+	content, err := os.ReadFile(rotated)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(credentialsFile, content, 0o600))
+
+	// then
+	require.Eventually(t, func() bool {
+		return connection.Stats().Reconnects > 0 && connection.Status() == natsgo.CONNECTED
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func connect(t *testing.T, url string, cfg *env.NATSAuthConfig) (*natsgo.Conn, error) {
+	t.Helper()
+	authOpts, err := eppnats.WithAuth(cfg)
+	require.NoError(t, err)
+	return eppnats.Connect(url, append([]eppnats.Opt{
+		eppnats.WithRetryOnFailedConnect(false),
+		eppnats.WithMaxReconnects(-1),
+		eppnats.WithReconnectWait(10 * time.Millisecond),
+	}, authOpts...)...)
+}
+
+func newUserNKeySeedFile(t *testing.T) (string, string) {
+	t.Helper()
+	user, err := nkeys.CreateUser()
+	require.NoError(t, err)
+	seed, err := user.Seed()
+	require.NoError(t, err)
+	pub, err := user.PublicKey()
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "user.nk")
+	require.NoError(t, os.WriteFile(file, seed, 0o600))
+	return file, pub
+}
+
+type operator struct {
+	account nkeys.KeyPair
+}
+
+// newOperator configures the server options to trust a new operator with a single account.
+func newOperator(t *testing.T, opts *server.Options) *operator {
+	t.Helper()
+	operatorKP, err := nkeys.CreateOperator()
+	require.NoError(t, err)
+	operatorPub, err := operatorKP.PublicKey()
+	require.NoError(t, err)
+	operatorJWT, err := jwt.NewOperatorClaims(operatorPub).Encode(operatorKP)
+	require.NoError(t, err)
+	operatorClaims, err := jwt.DecodeOperatorClaims(operatorJWT)
+	require.NoError(t, err)
+
+	accountKP, err := nkeys.CreateAccount()
+	require.NoError(t, err)
+	accountPub, err := accountKP.PublicKey()
+	require.NoError(t, err)
+	accountJWT, err := jwt.NewAccountClaims(accountPub).Encode(operatorKP)
+	require.NoError(t, err)
+
+	resolver := &server.MemAccResolver{}
+	require.NoError(t, resolver.Store(accountPub, accountJWT))
+	opts.TrustedOperators = []*jwt.OperatorClaims{operatorClaims}
+	opts.AccountResolver = resolver
+	return &operator{account: accountKP}
+}
+
+func (o *operator) newCredentialsFile(t *testing.T, name string) string {
+	t.Helper()
+	userKP, err := nkeys.CreateUser()
+	require.NoError(t, err)
+	userPub, err := userKP.PublicKey()
+	require.NoError(t, err)
+	userJWT, err := jwt.NewUserClaims(userPub).Encode(o.account)
+	require.NoError(t, err)
+	seed, err := userKP.Seed()
+	require.NoError(t, err)
+	content, err := jwt.FormatUserConfig(userJWT, seed)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+	return file
+}
@@ -0,0 +1,146 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientAssertionType is the type of the JWT client assertions (RFC 7523).
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// assertionLifetime is the validity of a client assertion, it is only used for a single token request.
+const assertionLifetime = 5 * time.Minute
+
+var ErrUnsupportedJWTKey = errors.New("unsupported JWT key, only RSA and ECDSA P-256 keys are supported")
+
+// assertionSigner signs the JWT client assertions of the private_key_jwt client authentication.
+type assertionSigner struct {
+	key      crypto.Signer
+	alg      string
+	keyID    string
+	clientID string
+	audience string
+	now      func() time.Time
+}
+
+// newAssertionSigner returns a signer with the PEM encoded private key of the given file.
+func newAssertionSigner(keyFile, keyID, clientID, audience string) (*assertionSigner, error) {
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT key: %w", err)
+	}
+	key, err := parsePrivateKey(content)
+	if err != nil {
+		return nil, err
+	}
+	signer := &assertionSigner{key: key, keyID: keyID, clientID: clientID, audience: audience, now: time.Now}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signer.alg = "RS256"
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedJWTKey
+		}
+		signer.alg = "ES256"
+	default:
+		return nil, ErrUnsupportedJWTKey
+	}
+	return signer, nil
+}
+
+func parsePrivateKey(content []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM encoded JWT key")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT key: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedJWTKey
+		}
+		return signer, nil
+	}
+}
+
+// sign returns a new client assertion which is issued by the client for the audience.
+func (s *assertionSigner) sign() (string, error) {
+	header := map[string]string{"alg": s.alg, "typ": "JWT"}
+	if s.keyID != "" {
+		header["kid"] = s.keyID
+	}
+	now := s.now()
+	claims := map[string]any{
+		"iss": s.clientID,
+		"sub": s.clientID,
+		"aud": s.audience,
+		"jti": uuid.NewString(),
+		"iat": now.Unix(),
+		"exp": now.Add(assertionLifetime).Unix(),
+	}
+	encodedHeader, err := encodeSegment(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodedHeader + "." + encodedClaims
+
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		signature, err = signES256(key, digest[:])
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign client assertion: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// signES256 returns the fixed size concatenation of r and s which JWS uses instead of ASN.1.
+func signES256(key *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+	const size = 32
+	signature := make([]byte, 2*size)
+	r.FillBytes(signature[:size])
+	s.FillBytes(signature[size:])
+	return signature, nil
+}
+
+func encodeSegment(v any) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(content), nil
+}
@@ -2,25 +2,52 @@ package oauth
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"sync/atomic"
 
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/tracing/propagation/tracecontextb3"
 	"go.opencensus.io/plugin/ochttp"
 	"golang.org/x/oauth2"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
 )
 
-// NewClient returns a new HTTP client which have nested transports for handling oauth2 security,
-// HTTP connection pooling, and tracing.
-func NewClient(ctx context.Context, cfg *env.EventMeshConfig) *http.Client {
-	// configure auth client
-	config := Config(cfg)
-	client := config.Client(ctx)
+const credentialsWatcherName = "oauth-credentials-watcher"
 
+// NewClient returns a new HTTP client which have nested transports for handling oauth2 security,
+// HTTP connection pooling, and tracing. The token requests use the same connection pool, so a configured
+// client certificate authenticates the client at the token endpoint and binds the tokens to it.
+// Tokens are refreshed OAuthTokenRefreshBefore their expiry. The collector is optional.
+// The credential files are checked for rotation until the given context is done. A rotation atomically swaps
+// the token source and the client certificate, so the cached token is dropped and the next request fetches
+// a new one with the rotated credentials.
+func NewClient(ctx context.Context, cfg *env.EventMeshConfig, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) (*http.Client, error) {
 	// configure connection transport
 	base := http.DefaultTransport.(*http.Transport).Clone()
 	cfg.ConfigureTransport(base)
-	client.Transport.(*oauth2.Transport).Base = base
+	cert, err := configureTLS(base, &cfg.OAuthConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// configure auth transport
+	tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base})
+	source, err := newTokenSource(tokenCtx, cfg, collector)
+	if err != nil {
+		return nil, err
+	}
+	swappable := newSwappableTokenSource(source)
+	client := &http.Client{Transport: &oauth2.Transport{Source: swappable, Base: base}}
 
 	// configure tracing transport
 	client.Transport = &ochttp.Transport{
@@ -28,5 +55,72 @@ func NewClient(ctx context.Context, cfg *env.EventMeshConfig) *http.Client {
 		Propagation: tracecontextb3.TraceContextEgress(),
 	}
 
-	return client
+	// watch the credentials
+	namedLogger := logger.WithContext().Named(credentialsWatcherName)
+	files := cfg.CredentialFiles()
+	filewatch.Watch(ctx, files, cfg.OAuthCredentialsReloadInterval, func() {
+		if err := reloadCredentials(tokenCtx, cfg, collector, base, swappable, cert); err != nil {
+			namedLogger.Errorw("Failed to reload rotated OAuth credentials", "error", err)
+			return
+		}
+		namedLogger.Infow("OAuth credentials rotated", "files", files)
+	}, func(err error) {
+		namedLogger.Warnw("Failed to read OAuth credentials", "error", err)
+	})
+
+	return client, nil
+}
+
+// reloadCredentials swaps the client certificate and the token source with the ones of the current files.
+// The idle connections are closed, so the next requests present the rotated certificate.
+func reloadCredentials(ctx context.Context, cfg *env.EventMeshConfig, collector metrics.PublishingMetricsCollector,
+	base *http.Transport, swappable *swappableTokenSource, cert *atomic.Pointer[tls.Certificate],
+) error {
+	source, err := newTokenSource(ctx, cfg, collector)
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		rotated, err := tls.LoadX509KeyPair(cfg.OAuthTLSCertFile, cfg.OAuthTLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load OAuth TLS certificate: %w", err)
+		}
+		cert.Store(&rotated)
+	}
+	swappable.swap(source)
+	base.CloseIdleConnections()
+	return nil
+}
+
+// configureTLS configures the client certificate and the CA of the given transport.
+// It returns the client certificate which is presented by the transport, or nil if none is configured.
+func configureTLS(transport *http.Transport, cfg *env.OAuthConfig) (*atomic.Pointer[tls.Certificate], error) {
+	if cfg.OAuthTLSCertFile == "" && cfg.OAuthTLSCAFile == "" {
+		return nil, nil //nolint:nilnil // no client certificate is configured.
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.OAuthTLSCAFile != "" {
+		content, err := os.ReadFile(cfg.OAuthTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OAuth TLS CA: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(content) {
+			return nil, errors.New("failed to parse OAuth TLS CA")
+		}
+	}
+	transport.TLSClientConfig = tlsConfig
+	if cfg.OAuthTLSCertFile == "" {
+		return nil, nil //nolint:nilnil // no client certificate is configured.
+	}
+	loaded, err := tls.LoadX509KeyPair(cfg.OAuthTLSCertFile, cfg.OAuthTLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OAuth TLS certificate: %w", err)
+	}
+	cert := &atomic.Pointer[tls.Certificate]{}
+	cert.Store(&loaded)
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return cert.Load(), nil
+	}
+	return cert, nil
 }
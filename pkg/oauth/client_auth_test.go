@@ -0,0 +1,338 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	tokenEndpoint         = "/token"
+	eventsEndpoint        = "/events"
+	eventsHTTP400Endpoint = "/events400"
+	clientID              = "client-id"
+)
+
+func TestNewClient_PrivateKeyJWT(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecKeyDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		givenKey  *pem.Block
+		publicKey crypto.PublicKey
+	}{
+		{
+			name:      "RSA key",
+			givenKey:  &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+			publicKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "ECDSA key",
+			givenKey:  &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecKeyDER},
+			publicKey: &ecKey.PublicKey,
+		},
+		{
+			name:      "PKCS8 key",
+			givenKey:  &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER},
+			publicKey: &ecKey.PublicKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var mockServer *epptestingutils.MockServer
+			mockServer = epptestingutils.NewMockServer(
+				epptestingutils.WithExpiresIn(60),
+				epptestingutils.WithTokenValidator(func(r *http.Request) error {
+					if err := r.ParseForm(); err != nil {
+						return err
+					}
+					if r.Header.Get("Authorization") != "" || r.PostForm.Get("client_secret") != "" {
+						return errors.New("unexpected client secret")
+					}
+					if r.PostForm.Get("client_id") != clientID ||
+						r.PostForm.Get("client_assertion_type") != ClientAssertionType {
+						return errors.New("invalid client")
+					}
+					return verifyAssertion(r.PostForm.Get("client_assertion"), tc.publicKey,
+						mockServer.URL()+tokenEndpoint)
+				}),
+			)
+			mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+			defer mockServer.Close()
+
+			keyFile := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(tc.givenKey), 0o600))
+			cfg := newEnvConfig(mockServer)
+			cfg.OAuthClientAuthMethod = env.OAuthClientAuthPrivateKeyJWT
+			cfg.OAuthJWTKeyFile = keyFile
+			cfg.OAuthJWTKeyID = "key-1"
+			require.NoError(t, cfg.Validate())
+
+			client, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+			require.NoError(t, err)
+			defer client.CloseIdleConnections()
+
+			// when
+			code := post(t, client, cfg.EventMeshPublishURL)
+
+			// then
+			assert.Equal(t, http.StatusNoContent, code)
+			assert.Equal(t, 1, mockServer.GeneratedTokensCount())
+		})
+	}
+}
+
+func TestNewClient_TLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	// given
+	ca := epptestingutils.NewCertificateAuthority(t)
+	certFile, keyFile := ca.NewCertificate(t, "publisher")
+	mockServer := epptestingutils.NewMockServer(
+		epptestingutils.WithExpiresIn(60),
+		epptestingutils.WithTLS(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientCAs:  ca.Pool,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}),
+		epptestingutils.WithTokenValidator(func(r *http.Request) error {
+			if err := r.ParseForm(); err != nil {
+				return err
+			}
+			if r.PostForm.Get("client_id") != clientID || r.Header.Get("Authorization") != "" {
+				return errors.New("invalid client")
+			}
+			if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "publisher" {
+				return errors.New("missing client certificate")
+			}
+			return nil
+		}),
+	)
+	mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+	defer mockServer.Close()
+
+	serverCAFile := filepath.Join(t.TempDir(), "server-ca.pem")
+	require.NoError(t, os.WriteFile(serverCAFile, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw,
+	}), 0o600))
+	cfg := newEnvConfig(mockServer)
+	cfg.OAuthClientAuthMethod = env.OAuthClientAuthTLS
+	cfg.OAuthTLSCAFile = serverCAFile
+	require.Error(t, cfg.Validate())
+
+	withoutCert, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+	require.NoError(t, err)
+	defer withoutCert.CloseIdleConnections()
+
+	cfg.OAuthTLSCertFile, cfg.OAuthTLSKeyFile = certFile, keyFile
+	require.NoError(t, cfg.Validate())
+	client, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
+
+	// when
+	code := post(t, client, cfg.EventMeshPublishURL)
+
+	// then
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, 1, mockServer.GeneratedTokensCount())
+
+	// when
+	_, err = withoutCert.Post(cfg.EventMeshPublishURL, "application/json", nil)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 1, mockServer.GeneratedTokensCount())
+}
+
+func TestNewClient_RefreshBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	// given
+	mockServer := epptestingutils.NewMockServer(epptestingutils.WithExpiresIn(2))
+	mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+	defer mockServer.Close()
+
+	cfg := newEnvConfig(mockServer)
+	cfg.OAuthTokenRefreshBefore = time.Second
+	client, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
+
+	// when the token is not about to expire
+	post(t, client, cfg.EventMeshPublishURL)
+	post(t, client, cfg.EventMeshPublishURL)
+
+	// then
+	assert.Equal(t, 1, mockServer.GeneratedTokensCount())
+
+	// when the token expires within the refresh period
+	time.Sleep(time.Second + 100*time.Millisecond)
+	post(t, client, cfg.EventMeshPublishURL)
+
+	// then
+	assert.Equal(t, 2, mockServer.GeneratedTokensCount())
+}
+
+func TestNewClient_TokenFetchMetrics(t *testing.T) {
+	t.Parallel()
+
+	// given
+	valid := false
+	mockServer := epptestingutils.NewMockServer(
+		epptestingutils.WithExpiresIn(60),
+		epptestingutils.WithTokenValidator(func(*http.Request) error {
+			if !valid {
+				return errors.New("invalid client")
+			}
+			return nil
+		}),
+	)
+	mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+	defer mockServer.Close()
+
+	cfg := newEnvConfig(mockServer)
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	client, err := NewClient(context.Background(), cfg, collector, newLogger(t))
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
+
+	// when the token endpoint rejects the client
+	_, err = client.Post(cfg.EventMeshPublishURL, "application/json", nil)
+
+	// then
+	require.Error(t, err)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, fmt.Sprintf(`
+		# HELP eventing_epp_oauth_token_fetch_failures_total The total number of failed OAuth token fetches
+		# TYPE eventing_epp_oauth_token_fetch_failures_total counter
+		eventing_epp_oauth_token_fetch_failures_total{token_endpoint="%s"} 1
+	`, cfg.TokenEndpoint), metrics.TokenFetchFailuresKey)
+
+	// when the token endpoint accepts the client
+	valid = true
+	code := post(t, client, cfg.EventMeshPublishURL)
+
+	// then
+	assert.Equal(t, http.StatusNoContent, code)
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	fetches := uint64(0)
+	for _, family := range families {
+		if family.GetName() == metrics.TokenFetchLatencyKey {
+			fetches = family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	assert.Equal(t, uint64(2), fetches)
+}
+
+func newEnvConfig(mockServer *epptestingutils.MockServer) *env.EventMeshConfig {
+	cfg := epptestingutils.NewEnvConfig(mockServer.URL()+eventsEndpoint, mockServer.URL()+tokenEndpoint)
+	cfg.ClientID, cfg.ClientSecret = clientID, "secret"
+	cfg.OAuthClientAuthMethod = env.OAuthClientAuthSecret
+	return cfg
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
+
+func post(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+	resp, err := client.Post(url, "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+// verifyAssertion returns an error if the signature or the claims of the given client assertion are invalid.
+func verifyAssertion(assertion string, publicKey crypto.PublicKey, audience string) error {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return errors.New("invalid assertion")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return err
+		}
+	case *ecdsa.PublicKey:
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return errors.New("invalid signature")
+		}
+	}
+
+	header, claims := map[string]any{}, map[string]any{}
+	for i, v := range []*map[string]any{&header, &claims} {
+		content, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(content, v); err != nil {
+			return err
+		}
+	}
+	if header["kid"] != "key-1" {
+		return errors.New("invalid key ID")
+	}
+	if claims["iss"] != clientID || claims["sub"] != clientID || claims["aud"] != audience || claims["jti"] == "" {
+		return fmt.Errorf("invalid claims: %v", claims)
+	}
+	if exp, ok := claims["exp"].(float64); !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
+		return errors.New("assertion expired")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_ReloadClientSecret(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var mutex sync.Mutex
+	wantSecret, issued := "initial-secret", 0
+	mockServer := epptestingutils.NewMockServer(
+		epptestingutils.WithExpiresIn(60),
+		epptestingutils.WithTokenValidator(func(r *http.Request) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			id, secret, ok := r.BasicAuth()
+			if !ok || id != clientID || secret != wantSecret {
+				return errors.New("invalid client")
+			}
+			issued++
+			return nil
+		}),
+	)
+	mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	cfg := newEnvConfig(mockServer)
+	cfg.ClientID, cfg.ClientSecret = "", ""
+	cfg.ClientIDFile, cfg.ClientSecretFile = filepath.Join(dir, "client-id"), filepath.Join(dir, "client-secret")
+	cfg.OAuthCredentialsReloadInterval = 10 * time.Millisecond
+	require.NoError(t, os.WriteFile(cfg.ClientIDFile, []byte(clientID+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(cfg.ClientSecretFile, []byte("initial-secret"), 0o600))
+	require.NoError(t, cfg.Validate())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, err := NewClient(ctx, cfg, nil, newLogger(t))
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
+	require.Equal(t, http.StatusNoContent, post(t, client, cfg.EventMeshPublishURL))
+
+	// when
+	mutex.Lock()
+	wantSecret = "rotated-secret"
+	mutex.Unlock()
+	require.NoError(t, os.WriteFile(cfg.ClientSecretFile, []byte("rotated-secret"), 0o600))
+
+	// then the cached token is dropped and a new one is fetched with the rotated secret
+	require.Eventually(t, func() bool {
+		if post(t, client, cfg.EventMeshPublishURL) != http.StatusNoContent {
+			return false
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		return issued == 2
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestNewClient_ReloadClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var mutex sync.Mutex
+	var presented []string
+	ca := epptestingutils.NewCertificateAuthority(t)
+	mockServer := epptestingutils.NewMockServer(
+		epptestingutils.WithExpiresIn(60),
+		epptestingutils.WithTLS(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientCAs:  ca.Pool,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}),
+		epptestingutils.WithTokenValidator(func(r *http.Request) error {
+			if len(r.TLS.PeerCertificates) == 0 {
+				return errors.New("missing client certificate")
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			presented = append(presented, r.TLS.PeerCertificates[0].Subject.CommonName)
+			return nil
+		}),
+	)
+	mockServer.Start(t, tokenEndpoint, eventsEndpoint, eventsHTTP400Endpoint)
+	defer mockServer.Close()
+
+	certFile, keyFile := ca.NewCertificate(t, "initial")
+	serverCAFile := filepath.Join(t.TempDir(), "server-ca.pem")
+	require.NoError(t, os.WriteFile(serverCAFile, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw,
+	}), 0o600))
+	cfg := newEnvConfig(mockServer)
+	cfg.ClientSecret = ""
+	cfg.OAuthClientAuthMethod = env.OAuthClientAuthTLS
+	cfg.OAuthTLSCertFile, cfg.OAuthTLSKeyFile, cfg.OAuthTLSCAFile = certFile, keyFile, serverCAFile
+	cfg.OAuthCredentialsReloadInterval = 10 * time.Millisecond
+	require.NoError(t, cfg.Validate())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, err := NewClient(ctx, cfg, nil, newLogger(t))
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
+	require.Equal(t, http.StatusNoContent, post(t, client, cfg.EventMeshPublishURL))
+
+	// when
+	rotatedCertFile, rotatedKeyFile := ca.NewCertificate(t, "rotated")
+	for source, target := range map[string]string{rotatedCertFile: certFile, rotatedKeyFile: keyFile} {
+		content, err := os.ReadFile(source)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(target, content, 0o600))
+	}
+
+	// then a new token is fetched with the rotated certificate
+	require.Eventually(t, func() bool {
+		post(t, client, cfg.EventMeshPublishURL)
+		mutex.Lock()
+		defer mutex.Unlock()
+		return presented[len(presented)-1] == "rotated"
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, "initial", presented[0])
+}
@@ -22,7 +22,10 @@ func TestNewClient(t *testing.T) {
 	)
 
 	cfg := &env.EventMeshConfig{MaxIdleConns: maxIdleConns, MaxIdleConnsPerHost: maxIdleConnsPerHost}
-	client := NewClient(context.Background(), cfg)
+	client, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to create client with error: %v", err)
+	}
 	defer client.CloseIdleConnections()
 
 	ocTransport, ok := client.Transport.(*ochttp.Transport)
@@ -94,7 +97,10 @@ func TestGetToken(t *testing.T) {
 			emsCEURL := fmt.Sprintf("%s%s", mockServer.URL(), eventsEndpoint)
 			authURL := fmt.Sprintf("%s%s", mockServer.URL(), tokenEndpoint)
 			cfg := epptestingutils.NewEnvConfig(emsCEURL, authURL)
-			client := NewClient(context.Background(), cfg)
+			client, err := NewClient(context.Background(), cfg, nil, newLogger(t))
+			if err != nil {
+				t.Fatalf("Failed to create client with error: %v", err)
+			}
 			defer client.CloseIdleConnections()
 
 			for i := 0; i < test.requestsCount; i++ {
@@ -2,14 +2,25 @@ package oauth
 
 import (
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 // Config returns a new oauth2 client credentials config instance.
+// Without a client secret, the client ID is sent in the token request parameters.
 func Config(cfg *env.EventMeshConfig) clientcredentials.Config {
-	return clientcredentials.Config{
-		ClientID:     cfg.ClientID,
-		ClientSecret: cfg.ClientSecret,
-		TokenURL:     cfg.TokenEndpoint,
+	switch cfg.OAuthClientAuthMethod {
+	case env.OAuthClientAuthTLS, env.OAuthClientAuthPrivateKeyJWT:
+		return clientcredentials.Config{
+			ClientID:  cfg.ClientID,
+			TokenURL:  cfg.TokenEndpoint,
+			AuthStyle: oauth2.AuthStyleInParams,
+		}
+	default:
+		return clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     cfg.TokenEndpoint,
+		}
 	}
 }
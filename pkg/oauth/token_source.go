@@ -0,0 +1,116 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// compile time check.
+var (
+	_ oauth2.TokenSource = &tokenSource{}
+	_ oauth2.TokenSource = &swappableTokenSource{}
+)
+
+// tokenSource fetches a new token from the token endpoint on each call and records the latency and the failures
+// of the fetches. It adds a new client assertion to each token request if a signer is set.
+type tokenSource struct {
+	ctx       context.Context
+	config    clientcredentials.Config
+	signer    *assertionSigner
+	collector metrics.PublishingMetricsCollector
+}
+
+// newTokenSource returns a token source with the current credentials of the given config, which caches the tokens
+// until OAuthTokenRefreshBefore their expiry. The given context is used for the token requests.
+func newTokenSource(ctx context.Context, cfg *env.EventMeshConfig, collector metrics.PublishingMetricsCollector,
+) (oauth2.TokenSource, error) {
+	cfg, err := resolveCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	source := &tokenSource{ctx: ctx, config: Config(cfg), collector: collector}
+	if cfg.OAuthClientAuthMethod == env.OAuthClientAuthPrivateKeyJWT {
+		audience := cfg.OAuthJWTAudience
+		if audience == "" {
+			audience = cfg.TokenEndpoint
+		}
+		signer, err := newAssertionSigner(cfg.OAuthJWTKeyFile, cfg.OAuthJWTKeyID, cfg.ClientID, audience)
+		if err != nil {
+			return nil, err
+		}
+		source.signer = signer
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, source, cfg.OAuthTokenRefreshBefore), nil
+}
+
+// resolveCredentials returns a copy of the given config with the client ID and secret read from the configured files.
+func resolveCredentials(cfg *env.EventMeshConfig) (*env.EventMeshConfig, error) {
+	resolved := *cfg
+	for _, credential := range []struct {
+		file  string
+		value *string
+	}{
+		{file: cfg.ClientIDFile, value: &resolved.ClientID},
+		{file: cfg.ClientSecretFile, value: &resolved.ClientSecret},
+	} {
+		if credential.file == "" {
+			continue
+		}
+		content, err := os.ReadFile(credential.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OAuth credentials: %w", err)
+		}
+		*credential.value = strings.TrimSpace(string(content))
+	}
+	return &resolved, nil
+}
+
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	config := s.config
+	if s.signer != nil {
+		assertion, err := s.signer.sign()
+		if err != nil {
+			return nil, err
+		}
+		config.EndpointParams = url.Values{
+			"client_assertion_type": []string{ClientAssertionType},
+			"client_assertion":      []string{assertion},
+		}
+	}
+	start := time.Now()
+	token, err := config.Token(s.ctx)
+	if s.collector != nil {
+		s.collector.RecordTokenFetch(time.Since(start), config.TokenURL, err != nil)
+	}
+	return token, err
+}
+
+// swappableTokenSource delegates to a token source which can be swapped atomically, e.g. with rotated credentials.
+type swappableTokenSource struct {
+	current atomic.Pointer[oauth2.TokenSource]
+}
+
+func newSwappableTokenSource(source oauth2.TokenSource) *swappableTokenSource {
+	s := &swappableTokenSource{}
+	s.swap(source)
+	return s
+}
+
+func (s *swappableTokenSource) Token() (*oauth2.Token, error) {
+	return (*s.current.Load()).Token()
+}
+
+// swap replaces the current token source, so its cached token is not used anymore.
+func (s *swappableTokenSource) swap(source oauth2.TokenSource) {
+	s.current.Store(&source)
+}
@@ -0,0 +1,226 @@
+package policy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/cel-go/common/types"
+	"github.com/kyma-project/eventing-publisher-proxy/internal/sanitize"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/application"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/filewatch"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	engineName = "policy-engine"
+
+	// the variables of the conditions.
+	varEvent       = "event"
+	varExtensions  = "extensions"
+	varHeaders     = "headers"
+	varApplication = "application"
+	varNow         = "now"
+
+	// defaultPolicy is the policy name of the decisions by the default effect.
+	defaultPolicy = "default"
+
+	// the decisions recorded by the metrics.
+	decisionAudit = "audit"
+	decisionError = "error"
+
+	// errorMessage is returned to the clients of events whose policies could not be evaluated.
+	errorMessage = "event could not be checked against the policies"
+	// denyMessage is returned to the clients of denied events if the deciding policy has no message.
+	denyMessage = "event is denied by policy"
+)
+
+// Decision is the result of evaluating the policies for an event.
+type Decision struct {
+	// Policy is the name of the deciding policy, "default" if no policy matched.
+	Policy string
+	// Effect is the decision of the policy.
+	Effect Effect
+	// Message is returned to the client if the event is denied.
+	Message string
+	// DryRun is true if a deny decision is not enforced.
+	DryRun bool
+}
+
+// Denied returns true if the event must be rejected.
+func (d Decision) Denied() bool {
+	return d.Effect == EffectDeny && !d.DryRun
+}
+
+// Engine evaluates the policies for the events and reloads them once their file changes.
+// The events are denied if a condition cannot be evaluated, e.g. because it accesses a missing extension.
+type Engine struct {
+	policies          atomic.Pointer[Policies]
+	applicationLister *application.Lister
+	collector         metrics.PublishingMetricsCollector
+	logger            *logger.Logger
+}
+
+// NewEngine returns a new Engine with the given policies. The application of the events is resolved with the given
+// lister, if it is enabled.
+func NewEngine(policies *Policies, applicationLister *application.Lister, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Engine {
+	e := &Engine{applicationLister: applicationLister, collector: collector, logger: logger}
+	e.policies.Store(policies)
+	return e
+}
+
+// Evaluate returns the decision of the policies for the given built event. The given source is the source the event
+// was received with, which identifies its application. It can be called on a nil Engine, which allows all events.
+func (e *Engine) Evaluate(event *ceevent.Event, source string, header http.Header) Decision {
+	if e == nil {
+		return Decision{Policy: defaultPolicy, Effect: EffectAllow}
+	}
+	policies := e.policies.Load()
+	decision := Decision{Policy: defaultPolicy, Effect: policies.DefaultEffect, DryRun: policies.DryRun}
+	variables := map[string]any{
+		varEvent:       eventVariable(event),
+		varExtensions:  extensionsVariable(event),
+		varHeaders:     headersVariable(header),
+		varApplication: e.applicationVariable(source),
+		varNow:         time.Now(),
+	}
+	decisionLabel := ""
+	for _, policy := range policies.Policies {
+		result, _, err := policy.program.Eval(variables)
+		if err != nil {
+			e.namedLogger().Warnw("Failed to evaluate policy", "policy", policy.Name, "error", err,
+				"type", sanitize.LogValue(event.Type()), "source", sanitize.LogValue(source))
+			decision = Decision{Policy: policy.Name, Effect: EffectDeny, Message: errorMessage,
+				DryRun: policies.DryRun || policy.DryRun}
+			decisionLabel = decisionError
+			break
+		}
+		if result == types.True {
+			decision = Decision{Policy: policy.Name, Effect: policy.Effect, Message: policy.Message,
+				DryRun: policies.DryRun || policy.DryRun}
+			break
+		}
+	}
+	if decision.Effect == EffectDeny && decision.Message == "" {
+		decision.Message = denyMessage
+	}
+	switch {
+	case decision.Effect == EffectDeny && decision.DryRun:
+		decisionLabel = decisionAudit
+		e.namedLogger().Infow("Event would be denied by policy in dry run", "policy", decision.Policy,
+			"type", sanitize.LogValue(event.Type()), "source", sanitize.LogValue(source))
+	case decision.Effect == EffectDeny:
+		e.namedLogger().Infow("Event is denied by policy", "policy", decision.Policy,
+			"type", sanitize.LogValue(event.Type()), "source", sanitize.LogValue(source))
+	}
+	if decisionLabel == "" {
+		decisionLabel = string(decision.Effect)
+	}
+	e.collector.RecordPolicyDecision(decision.Policy, decisionLabel)
+	return decision
+}
+
+// Watch reloads the policies from the given file once it changes, until the given context is done.
+// The current policies are kept if the changed file is invalid.
+func (e *Engine) Watch(ctx context.Context, file string, interval time.Duration) {
+	filewatch.Watch(ctx, []string{file}, interval, func() {
+		policies, err := LoadPolicies(file)
+		if err != nil {
+			e.namedLogger().Errorw("Failed to reload policies, keeping the current policies", "error", err)
+			return
+		}
+		e.policies.Store(policies)
+		e.namedLogger().Infow("Reloaded policies", "policies", len(policies.Policies), "dryRun", policies.DryRun)
+	}, func(err error) {
+		e.namedLogger().Warnw("Failed to read policies", "error", err)
+	})
+}
+
+// eventVariable returns the context attributes of the given event. The optional attributes are only set if present.
+func eventVariable(event *ceevent.Event) map[string]any {
+	variable := map[string]any{
+		"id":          event.ID(),
+		"source":      event.Source(),
+		"type":        event.Type(),
+		"specversion": event.SpecVersion(),
+	}
+	optional := map[string]string{
+		"subject":         event.Subject(),
+		"datacontenttype": event.DataContentType(),
+		"dataschema":      event.DataSchema(),
+	}
+	for name, value := range optional {
+		if value != "" {
+			variable[name] = value
+		}
+	}
+	if !event.Time().IsZero() {
+		variable["time"] = event.Time()
+	}
+	return variable
+}
+
+// extensionsVariable returns the extensions of the given event. Values of types CEL does not support are formatted
+// as strings.
+func extensionsVariable(event *ceevent.Event) map[string]any {
+	variable := make(map[string]any, len(event.Extensions()))
+	for name, value := range event.Extensions() {
+		switch value.(type) {
+		case string, bool, int32:
+			variable[name] = value
+		default:
+			if formatted, err := cetypes.Format(value); err == nil {
+				variable[name] = formatted
+			}
+		}
+	}
+	return variable
+}
+
+// headersVariable returns the given request headers with lower case names and comma separated values.
+func headersVariable(header http.Header) map[string]string {
+	variable := make(map[string]string, len(header))
+	for name, values := range header {
+		variable[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	return variable
+}
+
+// applicationVariable returns the application of the given source. Its fields are empty if the application lister is
+// disabled or the application does not exist.
+func (e *Engine) applicationVariable(source string) map[string]any {
+	variable := map[string]any{
+		"name":        "",
+		"type":        "",
+		"labels":      map[string]string{},
+		"annotations": map[string]string{},
+	}
+	if e.applicationLister == nil {
+		return variable
+	}
+	app, err := e.applicationLister.Get(source)
+	if err != nil || app == nil {
+		return variable
+	}
+	variable["name"], variable["type"] = app.Name, application.GetTypeOrName(app)
+	if app.Labels != nil {
+		variable["labels"] = app.Labels
+	}
+	if app.Annotations != nil {
+		variable["annotations"] = app.Annotations
+	}
+	return variable
+}
+
+func (e *Engine) namedLogger() *zap.SugaredLogger {
+	return e.logger.WithContext().Named(engineName)
+}
@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const testPolicies = `
+policies:
+- name: future-time
+  condition: 'has(event.time) && event.time > now + duration("5m")'
+  effect: deny
+  message: event time is too far in the future
+- name: tenant-header
+  condition: '"x-tenant" in headers && extensions.tenant != headers["x-tenant"]'
+  effect: deny
+- name: orders
+  condition: 'event.type.startsWith("prefix.shop.order.")'
+  effect: allow
+- name: shop
+  condition: 'event.source == "shop"'
+  effect: deny
+  message: shop may only publish orders
+  dryRun: true
+defaultEffect: deny
+`
+
+func TestEngine_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		givenType    string
+		givenSource  string
+		givenTime    time.Time
+		givenHeader  http.Header
+		wantDecision Decision
+		wantDenied   bool
+	}{
+		{
+			name:         "should allow by policy",
+			givenType:    "prefix.shop.order.created.v1",
+			givenSource:  "erp",
+			wantDecision: Decision{Policy: "orders", Effect: EffectAllow},
+		},
+		{
+			name:        "should deny events in the future",
+			givenType:   "prefix.shop.order.created.v1",
+			givenSource: "erp",
+			givenTime:   time.Now().Add(time.Hour),
+			wantDecision: Decision{Policy: "future-time", Effect: EffectDeny,
+				Message: "event time is too far in the future"},
+			wantDenied: true,
+		},
+		{
+			name:         "should deny by request header",
+			givenType:    "prefix.shop.order.created.v1",
+			givenSource:  "erp",
+			givenHeader:  http.Header{"X-Tenant": []string{"other"}},
+			wantDecision: Decision{Policy: "tenant-header", Effect: EffectDeny, Message: denyMessage},
+			wantDenied:   true,
+		},
+		{
+			name:        "should not enforce deny in dry run",
+			givenType:   "prefix.shop.customer.created.v1",
+			givenSource: "shop",
+			wantDecision: Decision{Policy: "shop", Effect: EffectDeny, Message: "shop may only publish orders",
+				DryRun: true},
+		},
+		{
+			name:         "should apply the default effect",
+			givenType:    "prefix.crm.customer.created.v1",
+			givenSource:  "crm",
+			wantDecision: Decision{Policy: defaultPolicy, Effect: EffectDeny, Message: denyMessage},
+			wantDenied:   true,
+		},
+	}
+
+	log, err := logger.New("json", "info")
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testPolicies), 0o600))
+	policies, err := LoadPolicies(file)
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			engine := NewEngine(policies, nil, metrics.NewCollector(latency.NewBucketsProvider()), log)
+			event := ceevent.New()
+			event.SetID("id")
+			event.SetType(tc.givenType)
+			event.SetSource(tc.givenSource)
+			event.SetExtension("tenant", "tenant1")
+			if !tc.givenTime.IsZero() {
+				event.SetTime(tc.givenTime)
+			}
+
+			// when
+			decision := engine.Evaluate(&event, tc.givenSource, tc.givenHeader)
+
+			// then
+			assert.Equal(t, tc.wantDecision, decision)
+			assert.Equal(t, tc.wantDenied, decision.Denied())
+		})
+	}
+}
+
+func TestEngine_Evaluate_Error(t *testing.T) {
+	t.Parallel()
+
+	// given
+	log, err := logger.New("json", "info")
+	require.NoError(t, err)
+	policies := &Policies{Policies: []Policy{{
+		Name:      "missing-extension",
+		Condition: `extensions.tenant == "tenant1"`,
+		Effect:    EffectAllow,
+	}}}
+	require.NoError(t, policies.Compile())
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	engine := NewEngine(policies, nil, collector, log)
+	event := ceevent.New()
+	event.SetID("id")
+	event.SetType("order.created.v1")
+	event.SetSource("shop")
+
+	// when
+	decision := engine.Evaluate(&event, "shop", http.Header{})
+
+	// then
+	assert.True(t, decision.Denied())
+	assert.Equal(t, errorMessage, decision.Message)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+# HELP eventing_epp_policy_decisions_total The total number of policy decisions by policy and decision
+# TYPE eventing_epp_policy_decisions_total counter
+eventing_epp_policy_decisions_total{decision="error",policy="missing-extension"} 1
+`, metrics.PolicyDecisionsKey)
+}
+
+func TestEngine_Evaluate_Nil(t *testing.T) {
+	t.Parallel()
+
+	event := ceevent.New()
+	assert.False(t, (*Engine)(nil).Evaluate(&event, "", nil).Denied())
+}
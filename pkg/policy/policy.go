@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/cel-go/cel"
+	"sigs.k8s.io/yaml"
+)
+
+// Effect is the decision of a policy.
+type Effect string
+
+const (
+	// EffectAllow publishes the event.
+	EffectAllow Effect = "allow"
+	// EffectDeny rejects the event.
+	EffectDeny Effect = "deny"
+)
+
+var (
+	ErrPolicyWithoutName      = errors.New("policy has no name")
+	ErrDuplicatePolicyName    = errors.New("policy name is not unique")
+	ErrPolicyWithoutCondition = errors.New("policy has no condition")
+	ErrInvalidEffect          = errors.New("invalid effect")
+	ErrInvalidCondition       = errors.New("invalid condition")
+	ErrConditionNotBool       = errors.New("condition does not evaluate to a bool")
+)
+
+// Policies decide whether events are published. The first policy whose condition is true decides, the default effect
+// applies to the events no policy matches.
+type Policies struct {
+	// DryRun evaluates the policies without rejecting the denied events, the decisions are logged and counted only.
+	DryRun bool `json:"dryRun,omitempty"`
+	// DefaultEffect applies to the events no policy matches, allow if empty.
+	DefaultEffect Effect `json:"defaultEffect,omitempty"`
+	// Policies are evaluated in order.
+	Policies []Policy `json:"policies"`
+}
+
+// Policy allows or denies the events its condition is true for.
+type Policy struct {
+	// Name identifies the policy in the logs and metrics.
+	Name string `json:"name"`
+	// Condition is a CEL expression over the variables event, extensions, headers, application and now.
+	Condition string `json:"condition"`
+	// Effect is the decision for the events the condition is true for.
+	Effect Effect `json:"effect"`
+	// Message is returned to the clients of denied events.
+	Message string `json:"message,omitempty"`
+	// DryRun evaluates the policy without rejecting the denied events.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	program cel.Program
+}
+
+// LoadPolicies reads the policies from the given YAML or JSON file, validates and compiles them.
+func LoadPolicies(file string) (*Policies, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+	policies := &Policies{}
+	if err := yaml.UnmarshalStrict(content, policies); err != nil {
+		return nil, fmt.Errorf("failed to parse policies: %w", err)
+	}
+	if err := policies.Compile(); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// Compile validates the policies and compiles their conditions.
+func (p *Policies) Compile() error {
+	if p.DefaultEffect == "" {
+		p.DefaultEffect = EffectAllow
+	}
+	if !p.DefaultEffect.IsValid() {
+		return fmt.Errorf("default effect: %w: %s", ErrInvalidEffect, p.DefaultEffect)
+	}
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+	names := make(map[string]struct{}, len(p.Policies))
+	for i := range p.Policies {
+		policy := &p.Policies[i]
+		if policy.Name == "" {
+			return fmt.Errorf("policy %d: %w", i, ErrPolicyWithoutName)
+		}
+		if _, ok := names[policy.Name]; ok {
+			return fmt.Errorf("policy %s: %w", policy.Name, ErrDuplicatePolicyName)
+		}
+		names[policy.Name] = struct{}{}
+		if policy.Condition == "" {
+			return fmt.Errorf("policy %s: %w", policy.Name, ErrPolicyWithoutCondition)
+		}
+		if !policy.Effect.IsValid() {
+			return fmt.Errorf("policy %s: %w: %s", policy.Name, ErrInvalidEffect, policy.Effect)
+		}
+		ast, issues := env.Compile(policy.Condition)
+		if issues.Err() != nil {
+			return fmt.Errorf("policy %s: %w: %w", policy.Name, ErrInvalidCondition, issues.Err())
+		}
+		if !ast.OutputType().IsExactType(cel.BoolType) {
+			return fmt.Errorf("policy %s: %w", policy.Name, ErrConditionNotBool)
+		}
+		if policy.program, err = env.Program(ast); err != nil {
+			return fmt.Errorf("policy %s: %w: %w", policy.Name, ErrInvalidCondition, err)
+		}
+	}
+	return nil
+}
+
+// IsValid returns true if the effect is supported.
+func (e Effect) IsValid() bool {
+	return e == EffectAllow || e == EffectDeny
+}
+
+// newEnv returns the CEL environment of the conditions.
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(varEvent, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varExtensions, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varHeaders, cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable(varApplication, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varNow, cel.TimestampType),
+	)
+}
@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies_Compile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		givenPolicies Policies
+		wantError     error
+	}{
+		{
+			name:          "should fail with invalid default effect",
+			givenPolicies: Policies{DefaultEffect: "reject"},
+			wantError:     ErrInvalidEffect,
+		},
+		{
+			name:          "should fail without name",
+			givenPolicies: Policies{Policies: []Policy{{Condition: "true", Effect: EffectDeny}}},
+			wantError:     ErrPolicyWithoutName,
+		},
+		{
+			name: "should fail with duplicate names",
+			givenPolicies: Policies{Policies: []Policy{
+				{Name: "policy", Condition: "true", Effect: EffectDeny},
+				{Name: "policy", Condition: "false", Effect: EffectAllow},
+			}},
+			wantError: ErrDuplicatePolicyName,
+		},
+		{
+			name:          "should fail without condition",
+			givenPolicies: Policies{Policies: []Policy{{Name: "policy", Effect: EffectDeny}}},
+			wantError:     ErrPolicyWithoutCondition,
+		},
+		{
+			name:          "should fail with invalid effect",
+			givenPolicies: Policies{Policies: []Policy{{Name: "policy", Condition: "true", Effect: "reject"}}},
+			wantError:     ErrInvalidEffect,
+		},
+		{
+			name:          "should fail with invalid condition",
+			givenPolicies: Policies{Policies: []Policy{{Name: "policy", Condition: "event.type ==", Effect: EffectDeny}}},
+			wantError:     ErrInvalidCondition,
+		},
+		{
+			name:          "should fail with unknown variable",
+			givenPolicies: Policies{Policies: []Policy{{Name: "policy", Condition: "data.size > 1", Effect: EffectDeny}}},
+			wantError:     ErrInvalidCondition,
+		},
+		{
+			name:          "should fail with condition which is no bool",
+			givenPolicies: Policies{Policies: []Policy{{Name: "policy", Condition: "event.type", Effect: EffectDeny}}},
+			wantError:     ErrConditionNotBool,
+		},
+		{
+			name: "should accept valid policies",
+			givenPolicies: Policies{Policies: []Policy{{
+				Name:      "policy",
+				Condition: `has(event.time) && event.time > now + duration("5m")`,
+				Effect:    EffectDeny,
+			}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, tc.givenPolicies.Compile(), tc.wantError)
+		})
+	}
+}
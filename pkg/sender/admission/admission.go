@@ -0,0 +1,242 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/jetstream"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const handlerName = "admission-handler"
+
+// compile time check.
+var (
+	_ sender.GenericSender   = &Sender{}
+	_ sender.Wrapper         = &Sender{}
+	_ health.Checker         = &Sender{}
+	_ sender.RetryAfterError = &RejectedError{}
+	_ UsageProvider          = &jetstream.Sender{}
+)
+
+// UsageProvider provides the storage usage of the backend as the ratio of the stored data to its limits.
+type UsageProvider interface {
+	StorageUsage() (float64, error)
+}
+
+// RejectedError is returned for events which are rejected while shedding load.
+// Its code is 507 if the storage of the backend is full, otherwise 503.
+type RejectedError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *RejectedError) Error() string {
+	return e.Message()
+}
+
+func (e *RejectedError) Code() int {
+	return e.code
+}
+
+func (e *RejectedError) Message() string {
+	if e.code == http.StatusInsufficientStorage {
+		return "event rejected because the storage of the backend is full"
+	}
+	return "event rejected because the storage of the backend is under pressure"
+}
+
+// RetryAfter returns the time until the storage usage of the backend is checked again.
+func (e *RejectedError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	Shedding     bool `json:"shedding"`
+	BackendReady bool `json:"backendReady"`
+}
+
+// Sender decorates a sender.GenericSender with admission control based on the storage usage of the backend.
+// Once the usage reaches the shed threshold or the backend reports that its storage is full, only events of
+// the critical types are admitted and all others are rejected without touching the backend.
+// All events are admitted again once the usage drops below the recover threshold.
+type Sender struct {
+	sender    sender.GenericSender
+	usage     UsageProvider
+	cfg       env.AdmissionConfig
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+
+	mutex    sync.RWMutex
+	shedding bool
+	full     bool
+}
+
+// NewSender returns a new Sender instance which admits all events until Start checks the storage usage.
+func NewSender(s sender.GenericSender, usage UsageProvider, cfg env.AdmissionConfig,
+	collector metrics.PublishingMetricsCollector, logger *logger.Logger,
+) *Sender {
+	a := &Sender{
+		sender:    s,
+		usage:     usage,
+		cfg:       cfg,
+		collector: collector,
+		logger:    logger,
+	}
+	a.collector.SetAdmissionShedding(false)
+	return a
+}
+
+func (s *Sender) URL() string {
+	return s.sender.URL()
+}
+
+// Unwrap returns the wrapped sender.
+func (s *Sender) Unwrap() sender.GenericSender {
+	return s.sender
+}
+
+// Start checks the storage usage of the backend in the configured interval until the given context is done.
+func (s *Sender) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.AdmissionCheckInterval)
+		defer ticker.Stop()
+		for {
+			s.check()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Shedding returns true if non-critical events are rejected.
+func (s *Sender) Shedding() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.shedding
+}
+
+// Send dispatches the event using the decorated sender if it is admitted.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	if err := s.admit(event.Type()); err != nil {
+		s.namedLogger().Debugw("Rejected event", "id", event.ID(), "type", event.Type(), "code", err.Code())
+		s.collector.RecordAdmissionRejected(err.Code())
+		return err
+	}
+	err := s.sender.Send(ctx, event)
+	// shed on the storage of the backend only, e.g. not if the events cannot be stored in the outbox either
+	if err != nil && errors.Is(err, jetstream.ErrNoSpaceLeftOnDevice) {
+		s.mutex.Lock()
+		s.shedLocked(true, "backend storage is full")
+		s.mutex.Unlock()
+	}
+	return err
+}
+
+// admit returns a RejectedError if events of the given type are not admitted.
+func (s *Sender) admit(eventType string) *RejectedError {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if !s.shedding || s.critical(eventType) {
+		return nil
+	}
+	code := http.StatusServiceUnavailable
+	if s.full {
+		code = http.StatusInsufficientStorage
+	}
+	return &RejectedError{code: code, retryAfter: s.cfg.AdmissionCheckInterval}
+}
+
+func (s *Sender) critical(eventType string) bool {
+	return slices.ContainsFunc(s.cfg.AdmissionCriticalTypes, func(pattern string) bool {
+		return eventtype.Matches(pattern, eventType)
+	})
+}
+
+// check updates the admission with the current storage usage of the backend.
+// The admission is kept if the usage cannot be determined.
+func (s *Sender) check() {
+	usage, err := s.usage.StorageUsage()
+	if err != nil {
+		s.namedLogger().Warnw("Failed to check storage usage", "error", err)
+		return
+	}
+	s.update(usage)
+}
+
+func (s *Sender) update(usage float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case usage >= 1:
+		s.shedLocked(true, "storage usage reached the limit")
+	case usage >= s.cfg.AdmissionShedThreshold:
+		s.shedLocked(false, "storage usage reached the shed threshold")
+	case usage < s.cfg.AdmissionRecoverThreshold:
+		if s.shedding {
+			s.shedding, s.full = false, false
+			s.collector.SetAdmissionShedding(false)
+			s.namedLogger().Infow("Stopped shedding load", "usage", usage)
+		}
+	default:
+		// between the thresholds the load is still shed, but the storage is not full anymore
+		s.full = false
+	}
+}
+
+func (s *Sender) shedLocked(full bool, reason string) {
+	if !s.shedding {
+		s.namedLogger().Warnw("Started shedding load", "reason", reason, "criticalTypes", s.cfg.AdmissionCriticalTypes)
+	}
+	s.shedding, s.full = true, full
+	s.collector.SetAdmissionShedding(true)
+}
+
+// ReadinessCheck reports the readiness of the decorated sender, because critical events are admitted
+// while shedding load. The response body contains the admission state and the readiness of the decorated sender.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	backendReady := true
+	if checker, ok := s.sender.(health.Checker); ok {
+		backendReady = health.IsReady(checker, r)
+	}
+	resp := ReadinessResponse{Shedding: s.Shedding(), BackendReady: backendReady}
+
+	statusCode := health.StatusCodeHealthy
+	if !backendReady {
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Sender) LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	if checker, ok := s.sender.(health.Checker); ok {
+		checker.LivenessCheck(w, r)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
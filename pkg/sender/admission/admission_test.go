@@ -0,0 +1,260 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/jetstream"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	criticalType = "kyma.payment.captured.v1"
+	otherType    = "kyma.telemetry.sampled.v1"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	// given
+	stub := &senderStub{url: "stub"}
+	usage := &usageStub{}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := newSender(t, stub, usage, collector)
+
+	// when the usage is low
+	s.check()
+
+	// then all events are admitted
+	assert.Nil(t, s.Send(context.Background(), newEvent(t, otherType)))
+	assert.False(t, s.Shedding())
+
+	// when the usage reaches the shed threshold
+	usage.usage = 0.95
+	s.check()
+
+	// then only critical events are admitted
+	assert.Nil(t, s.Send(context.Background(), newEvent(t, criticalType)))
+	err := s.Send(context.Background(), newEvent(t, otherType))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, err.Code())
+	var retryAfterErr sender.RetryAfterError
+	require.True(t, errors.As(err, &retryAfterErr))
+	assert.Equal(t, 5*time.Second, retryAfterErr.RetryAfter())
+	assert.Equal(t, []string{otherType, criticalType}, stub.sent)
+
+	// when the usage drops between the thresholds
+	usage.usage = 0.85
+	s.check()
+
+	// then the load is still shed
+	assert.True(t, s.Shedding())
+
+	// when the usage drops below the recover threshold
+	usage.usage = 0.5
+	s.check()
+
+	// then all events are admitted again
+	assert.False(t, s.Shedding())
+	assert.Nil(t, s.Send(context.Background(), newEvent(t, otherType)))
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_admission_rejected_total The total number of events rejected by the admission control
+		# TYPE eventing_epp_admission_rejected_total counter
+		eventing_epp_admission_rejected_total{code="503"} 1
+	`, metrics.AdmissionRejectedKey)
+}
+
+func TestSender_Send_StorageFull(t *testing.T) {
+	t.Parallel()
+
+	// given
+	stub := &senderStub{url: "stub", err: jetstream.ErrNoSpaceLeftOnDevice}
+	usage := &usageStub{}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := newSender(t, stub, usage, collector)
+
+	// when the backend reports that its storage is full
+	err := s.Send(context.Background(), newEvent(t, otherType))
+
+	// then the load is shed with 507 without touching the backend
+	require.NotNil(t, err)
+	assert.True(t, s.Shedding())
+	err = s.Send(context.Background(), newEvent(t, otherType))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusInsufficientStorage, err.Code())
+	assert.Len(t, stub.sent, 1)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_admission_shedding The state of the admission control. `+
+		"`1` indicates that non-critical events are rejected"+`
+		# TYPE eventing_epp_admission_shedding gauge
+		eventing_epp_admission_shedding 1
+	`, metrics.AdmissionSheddingKey)
+
+	// when the usage cannot be determined
+	usage.err = errors.New("stream not found")
+	s.check()
+
+	// then the load is still shed
+	assert.True(t, s.Shedding())
+
+	// when the usage is low
+	usage.err = nil
+	s.check()
+
+	// then the load is not shed anymore
+	assert.False(t, s.Shedding())
+}
+
+func TestSender_Send_OutboxFull(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		givenErr sender.PublishError
+	}{
+		{
+			name:     "outbox is full",
+			givenErr: outbox.ErrOutboxFull,
+		},
+		{
+			name:     "outbox cannot store the event",
+			givenErr: common.ErrInsufficientStorage,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			stub := &senderStub{url: "stub", err: tc.givenErr}
+			collector := metrics.NewCollector(latency.NewBucketsProvider())
+			s := newSender(t, stub, &usageStub{}, collector)
+
+			// when the storage of the backend is fine, but the events cannot be stored in the outbox
+			err := s.Send(context.Background(), newEvent(t, otherType))
+
+			// then the error is returned without shedding the load
+			require.NotNil(t, err)
+			assert.Equal(t, http.StatusInsufficientStorage, err.Code())
+			assert.False(t, s.Shedding())
+			_ = s.Send(context.Background(), newEvent(t, otherType))
+			assert.Len(t, stub.sent, 2)
+		})
+	}
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenReady     bool
+		wantStatusCode int
+	}{
+		{
+			name:           "should be ready if the backend is ready",
+			givenReady:     true,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "should not be ready if the backend is not ready",
+			givenReady:     false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := newSender(t, &senderStub{ready: tc.givenReady}, &usageStub{usage: 1},
+				metrics.NewCollector(latency.NewBucketsProvider()))
+			s.check()
+
+			// when
+			ready := health.IsReady(s, nil)
+
+			// then
+			assert.Equal(t, tc.wantStatusCode == health.StatusCodeHealthy, ready)
+		})
+	}
+}
+
+type senderStub struct {
+	err   sender.PublishError
+	url   string
+	ready bool
+	sent  []string
+}
+
+func (s *senderStub) Send(_ context.Context, event *ceevent.Event) sender.PublishError {
+	s.sent = append(s.sent, event.Type())
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+type usageStub struct {
+	usage float64
+	err   error
+}
+
+func (u *usageStub) StorageUsage() (float64, error) {
+	return u.usage, u.err
+}
+
+func newSender(t *testing.T, s sender.GenericSender, usage UsageProvider, collector *metrics.Collector) *Sender {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return NewSender(s, usage, env.AdmissionConfig{
+		AdmissionControlEnabled:   true,
+		AdmissionShedThreshold:    0.9,
+		AdmissionRecoverThreshold: 0.8,
+		AdmissionCheckInterval:    5 * time.Second,
+		AdmissionCriticalTypes:    []string{"kyma.payment.>"},
+	}, collector, l)
+}
+
+func newEvent(t *testing.T, eventType string) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType(eventType)
+	event.SetSource("source")
+	return &event
+}
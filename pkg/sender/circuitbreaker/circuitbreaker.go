@@ -0,0 +1,273 @@
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	handlerName = "circuit-breaker-handler"
+
+	// halfOpenRetryAfter is the retry hint for sends rejected because all trial sends are in flight.
+	halfOpenRetryAfter = time.Second
+)
+
+// compile time check.
+var (
+	_ sender.GenericSender   = &Sender{}
+	_ sender.Wrapper         = &Sender{}
+	_ health.Checker         = &Sender{}
+	_ sender.RetryAfterError = &OpenError{}
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets all sends through and counts their failures.
+	Closed State = iota
+	// Open rejects all sends until the open timeout elapsed.
+	Open
+	// HalfOpen lets a limited number of trial sends through to decide whether to close or to open again.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// OpenError is returned for sends which are rejected by an open circuit.
+type OpenError struct {
+	Destination string
+	retryAfter  time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return e.Message()
+}
+
+func (e *OpenError) Code() int {
+	return http.StatusServiceUnavailable
+}
+
+func (e *OpenError) Message() string {
+	return fmt.Sprintf("circuit breaker is open for destination %s", e.Destination)
+}
+
+// RetryAfter returns the time until the circuit breaker lets sends through again.
+func (e *OpenError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	CircuitBreaker string `json:"circuitBreaker"`
+	BackendReady   bool   `json:"backendReady"`
+}
+
+// Sender decorates the sender.GenericSender of a single destination with a circuit breaker.
+// The circuit opens if the rate of server errors within a window exceeds the configured threshold,
+// provided that the window contains at least the configured minimum number of sends.
+// While the circuit is open, sends fail fast with an OpenError instead of waiting for the backend.
+type Sender struct {
+	sender    sender.GenericSender
+	cfg       env.CircuitBreakerConfig
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+
+	mutex       sync.Mutex
+	state       State
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	inFlight    int
+	successes   int
+	now         func() time.Time
+}
+
+// NewSender returns a new Sender instance with a closed circuit.
+func NewSender(s sender.GenericSender, cfg env.CircuitBreakerConfig, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Sender {
+	cb := &Sender{
+		sender:    s,
+		cfg:       cfg,
+		collector: collector,
+		logger:    logger,
+		now:       time.Now,
+	}
+	cb.windowStart = cb.now()
+	cb.recordState()
+	return cb
+}
+
+func (s *Sender) URL() string {
+	return s.sender.URL()
+}
+
+// Unwrap returns the wrapped sender.
+func (s *Sender) Unwrap() sender.GenericSender {
+	return s.sender
+}
+
+// State returns the current state of the circuit breaker.
+func (s *Sender) State() State {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.halfOpenIfTimedOutLocked()
+	return s.state
+}
+
+// Send dispatches the event using the decorated sender if the circuit lets it through.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	if err := s.allow(); err != nil {
+		s.namedLogger().Debugw("Rejected event", "id", event.ID(), "destination", s.sender.URL())
+		return err
+	}
+	err := s.sender.Send(ctx, event)
+	s.record(err)
+	return err
+}
+
+// allow returns an OpenError if the send is rejected, otherwise it counts the send as in flight.
+func (s *Sender) allow() *OpenError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.halfOpenIfTimedOutLocked()
+	switch s.state {
+	case Open:
+		retryAfter := s.openedAt.Add(s.cfg.CircuitBreakerOpenTimeout).Sub(s.now())
+		return &OpenError{Destination: s.sender.URL(), retryAfter: retryAfter}
+	case HalfOpen:
+		if s.inFlight >= s.cfg.CircuitBreakerHalfOpenRequests {
+			return &OpenError{Destination: s.sender.URL(), retryAfter: halfOpenRetryAfter}
+		}
+	case Closed:
+		if s.now().Sub(s.windowStart) >= s.cfg.CircuitBreakerWindow {
+			s.resetWindowLocked()
+		}
+	}
+	s.inFlight++
+	return nil
+}
+
+// record updates the circuit with the result of a send which was let through.
+func (s *Sender) record(err sender.PublishError) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.inFlight--
+	failed := err != nil && err.Code() >= http.StatusInternalServerError
+	switch s.state {
+	case Closed:
+		s.requests++
+		if failed {
+			s.failures++
+		}
+		if s.requests >= s.cfg.CircuitBreakerMinRequests &&
+			float64(s.failures)/float64(s.requests) >= s.cfg.CircuitBreakerFailureRate {
+			s.openLocked("failure rate exceeded")
+		}
+	case HalfOpen:
+		if failed {
+			s.openLocked("trial send failed")
+			return
+		}
+		s.successes++
+		if s.successes >= s.cfg.CircuitBreakerHalfOpenRequests {
+			s.state = Closed
+			s.resetWindowLocked()
+			s.recordState()
+			s.namedLogger().Infow("Closed circuit breaker", "destination", s.sender.URL())
+		}
+	case Open:
+		// the send was let through before the circuit opened, its result is not relevant anymore.
+	}
+}
+
+func (s *Sender) halfOpenIfTimedOutLocked() {
+	if s.state != Open || s.now().Sub(s.openedAt) < s.cfg.CircuitBreakerOpenTimeout {
+		return
+	}
+	s.state = HalfOpen
+	s.successes = 0
+	s.recordState()
+	s.namedLogger().Infow("Half-opened circuit breaker", "destination", s.sender.URL())
+}
+
+func (s *Sender) openLocked(reason string) {
+	s.state = Open
+	s.openedAt = s.now()
+	s.recordState()
+	s.namedLogger().Warnw("Opened circuit breaker", "reason", reason, "destination", s.sender.URL(),
+		"requests", s.requests, "failures", s.failures)
+}
+
+func (s *Sender) resetWindowLocked() {
+	s.windowStart = s.now()
+	s.requests = 0
+	s.failures = 0
+}
+
+func (s *Sender) recordState() {
+	s.collector.SetCircuitBreakerState(s.sender.URL(), int(s.state))
+}
+
+// ReadinessCheck reports 2XX if the circuit is not open and the decorated sender is ready, otherwise reports 5XX.
+// The response body contains the state of the circuit breaker and the readiness of the decorated sender.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	backendReady := true
+	if checker, ok := s.sender.(health.Checker); ok {
+		backendReady = health.IsReady(checker, r)
+	}
+	state := s.State()
+	resp := ReadinessResponse{CircuitBreaker: state.String(), BackendReady: backendReady}
+
+	statusCode := health.StatusCodeHealthy
+	if state == Open || !backendReady {
+		s.namedLogger().Errorw("Readiness check failed", "destination", s.sender.URL(),
+			"circuitBreaker", resp.CircuitBreaker, "backendReady", backendReady)
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessCheck delegates to the decorated sender if it implements health.Checker, otherwise reports 2XX.
+func (s *Sender) LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	if checker, ok := s.sender.(health.Checker); ok {
+		checker.LivenessCheck(w, r)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
@@ -0,0 +1,266 @@
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	// given
+	now := time.Now()
+	stub := &senderStub{url: "stub", ready: true, err: common.ErrClientNoConnection}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := NewSender(stub, newConfig(), collector, newLogger(t))
+	s.now = func() time.Time { return now }
+
+	// when the failures are below the minimum volume
+	for range 3 {
+		assert.Equal(t, common.ErrClientNoConnection, s.Send(context.Background(), newEvent(t)))
+	}
+
+	// then
+	assert.Equal(t, Closed, s.State())
+
+	// when the failures reach the minimum volume
+	err := s.Send(context.Background(), newEvent(t))
+
+	// then the circuit opens
+	assert.Equal(t, common.ErrClientNoConnection, err)
+	assert.Equal(t, Open, s.State())
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_circuit_breaker_state The state of the circuit breaker of a destination. `+"`0`"+` is closed, `+"`1`"+` is open and `+"`2`"+` is half-open
+		# TYPE eventing_epp_circuit_breaker_state gauge
+		eventing_epp_circuit_breaker_state{destination_service="stub"} 1
+	`, metrics.CircuitBreakerStateKey)
+
+	// when the circuit is open
+	now = now.Add(10 * time.Second)
+	err = s.Send(context.Background(), newEvent(t))
+
+	// then the send fails fast
+	var openErr sender.RetryAfterError
+	require.ErrorAs(t, err, &openErr)
+	assert.Equal(t, http.StatusServiceUnavailable, openErr.Code())
+	assert.Equal(t, 20*time.Second, openErr.RetryAfter())
+	assert.Equal(t, 4, stub.sent)
+
+	// when the open timeout elapsed and the trial send fails
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, HalfOpen, s.State())
+	err = s.Send(context.Background(), newEvent(t))
+
+	// then the circuit opens again
+	assert.Equal(t, common.ErrClientNoConnection, err)
+	assert.Equal(t, Open, s.State())
+
+	// when the open timeout elapsed and the trial send succeeds
+	now = now.Add(30 * time.Second)
+	stub.err = nil
+	err = s.Send(context.Background(), newEvent(t))
+
+	// then the circuit closes
+	assert.Nil(t, err)
+	assert.Equal(t, Closed, s.State())
+}
+
+func TestSender_Send_FailureRate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenErrs []sender.PublishError
+		wantState State
+	}{
+		{
+			name:      "should stay closed if the failure rate is below the threshold",
+			givenErrs: []sender.PublishError{nil, nil, nil, common.ErrClientNoConnection},
+			wantState: Closed,
+		},
+		{
+			name:      "should open if the failure rate reaches the threshold",
+			givenErrs: []sender.PublishError{nil, common.ErrInternalBackendError, nil, common.ErrClientNoConnection},
+			wantState: Open,
+		},
+		{
+			name: "should not count client errors as failures",
+			givenErrs: []sender.PublishError{
+				common.ErrClientConversionFailed, common.ErrClientConversionFailed,
+				common.ErrClientConversionFailed, common.ErrClientConversionFailed,
+			},
+			wantState: Closed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			stub := &senderStub{url: "stub"}
+			s := NewSender(stub, newConfig(), metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+
+			// when
+			for _, err := range tc.givenErrs {
+				stub.err = err
+				_ = s.Send(context.Background(), newEvent(t))
+			}
+
+			// then
+			assert.Equal(t, tc.wantState, s.State())
+		})
+	}
+}
+
+func TestSender_Send_WindowReset(t *testing.T) {
+	t.Parallel()
+
+	// given
+	now := time.Now()
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	s := NewSender(stub, newConfig(), metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+	s.now = func() time.Time { return now }
+
+	// when the failures are spread over two windows
+	for range 3 {
+		_ = s.Send(context.Background(), newEvent(t))
+	}
+	now = now.Add(2 * time.Minute)
+	_ = s.Send(context.Background(), newEvent(t))
+
+	// then
+	assert.Equal(t, Closed, s.State())
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenState     State
+		givenReady     bool
+		wantStatusCode int
+		wantResponse   ReadinessResponse
+	}{
+		{
+			name:           "should be ready if the circuit is closed and the backend is ready",
+			givenState:     Closed,
+			givenReady:     true,
+			wantStatusCode: health.StatusCodeHealthy,
+			wantResponse:   ReadinessResponse{CircuitBreaker: "closed", BackendReady: true},
+		},
+		{
+			name:           "should not be ready if the circuit is open",
+			givenState:     Open,
+			givenReady:     true,
+			wantStatusCode: health.StatusCodeNotHealthy,
+			wantResponse:   ReadinessResponse{CircuitBreaker: "open", BackendReady: true},
+		},
+		{
+			name:           "should not be ready if the backend is not ready",
+			givenState:     HalfOpen,
+			givenReady:     false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+			wantResponse:   ReadinessResponse{CircuitBreaker: "half-open", BackendReady: false},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := NewSender(&senderStub{ready: tc.givenReady}, newConfig(),
+				metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+			s.state = tc.givenState
+			s.openedAt = time.Now()
+			writer := httptest.NewRecorder()
+
+			// when
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+			var gotResponse ReadinessResponse
+			require.NoError(t, json.NewDecoder(strings.NewReader(writer.Body.String())).Decode(&gotResponse))
+			assert.Equal(t, tc.wantResponse, gotResponse)
+		})
+	}
+}
+
+type senderStub struct {
+	err   sender.PublishError
+	url   string
+	ready bool
+	sent  int
+}
+
+func (s *senderStub) Send(_ context.Context, _ *ceevent.Event) sender.PublishError {
+	s.sent++
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newConfig() env.CircuitBreakerConfig {
+	return env.CircuitBreakerConfig{
+		CircuitBreakerEnabled:          true,
+		CircuitBreakerFailureRate:      0.5,
+		CircuitBreakerMinRequests:      4,
+		CircuitBreakerWindow:           time.Minute,
+		CircuitBreakerOpenTimeout:      30 * time.Second,
+		CircuitBreakerHalfOpenRequests: 1,
+	}
+}
+
+func newEvent(t *testing.T) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType("order.created.v1")
+	event.SetSource("source")
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
@@ -2,11 +2,16 @@ package common
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 )
 
-var _ sender.PublishError = &BackendPublishError{}
+var (
+	_ sender.PublishError    = &BackendPublishError{}
+	_ sender.TypedError      = &BackendPublishError{}
+	_ sender.RetryAfterError = &BackendPublishError{}
+)
 
 //nolint:lll //reads better this way
 var (
@@ -15,12 +20,18 @@ var (
 	ErrClientNoConnection     = BackendPublishError{HTTPCode: http.StatusBadGateway, Info: "no connection to backend"}
 	ErrInternalBackendError   = BackendPublishError{HTTPCode: http.StatusInternalServerError, Info: "internal error on backend"}
 	ErrClientConversionFailed = BackendPublishError{HTTPCode: http.StatusBadRequest, Info: "conversion to target format failed"}
+	ErrAccepted               = BackendPublishError{HTTPCode: http.StatusAccepted, Info: "event accepted for delayed delivery"}
 )
 
 type BackendPublishError struct {
 	HTTPCode int
 	Info     string
-	err      error
+	// ErrorType classifies the error for the clients, e.g. in legacy error responses. It is optional.
+	ErrorType string
+	// RetryHint is the time the client should wait before retrying, e.g. because the backend throttles.
+	// It is optional.
+	RetryHint time.Duration
+	err       error
 }
 
 func (e BackendPublishError) Error() string {
@@ -46,6 +57,14 @@ func (e BackendPublishError) Message() string {
 	return e.Info
 }
 
+func (e BackendPublishError) Type() string {
+	return e.ErrorType
+}
+
+func (e BackendPublishError) RetryAfter() time.Duration {
+	return e.RetryHint
+}
+
 func (e *BackendPublishError) Is(target error) bool {
 	t, ok := target.(*BackendPublishError)
 	if !ok {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	ceevent "github.com/cloudevents/sdk-go/v2/event"
@@ -11,6 +13,7 @@ import (
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
 	"go.uber.org/zap"
@@ -20,13 +23,29 @@ import (
 
 var _ sender.GenericSender = &Sender{}
 
-// additionalHeaders returns the required headers by EMS for publish requests.
-// Any alteration or removal of those headers might cause publish requests to fail.
-func additionalHeaders() http.Header {
-	return http.Header{
-		"qos":    []string{string(eventmesh.QosAtLeastOnce)},
+// additionalHeaders returns the required headers by EMS for publish requests with the qos and the extra headers
+// of the given rule. Any alteration or removal of the required headers might cause publish requests to fail.
+func additionalHeaders(rule eventmesh.Rule) http.Header {
+	headers := http.Header{
+		"qos":    []string{string(rule.Qos)},
 		"Accept": []string{internal.ContentTypeApplicationJSON},
 	}
+	for name, value := range rule.Headers {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+// withContentMode returns a context which forces the content mode of the given rule when writing the request.
+func withContentMode(ctx context.Context, rule eventmesh.Rule) context.Context {
+	switch rule.ContentMode {
+	case eventmesh.ContentModeStructured:
+		return binding.WithForceStructured(ctx)
+	case eventmesh.ContentModeBinary:
+		return binding.WithForceBinary(ctx)
+	default:
+		return ctx
+	}
 }
 
 const (
@@ -38,7 +57,13 @@ const (
 type Sender struct {
 	Client *http.Client
 	Target string
-	logger *logger.Logger
+	// Rules configure the qos, content mode and extra headers per event type, the defaults are used if nil.
+	Rules *eventmesh.Rules
+	// Source overrides the source of the events if not empty, e.g. with the namespace of the target instance.
+	Source string
+	// Collector counts the errors of EventMesh by class if set.
+	Collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
 }
 
 func (s *Sender) URL() string {
@@ -57,10 +82,17 @@ func (s *Sender) Send(ctx context.Context, event *ceevent.Event) sender.PublishE
 		return e
 	}
 
+	if s.Source != "" && event.Source() != s.Source {
+		e := event.Clone()
+		e.SetSource(s.Source)
+		event = &e
+	}
+
 	message := binding.ToMessage(event)
 	defer func() { _ = message.Finish(nil) }()
 
-	err = cloudevents.WriteRequestWithHeaders(ctx, message, request, additionalHeaders())
+	rule := s.Rules.Match(event.Type())
+	err = cloudevents.WriteRequestWithHeaders(withContentMode(ctx, rule), message, request, additionalHeaders(rule))
 	if err != nil {
 		s.namedLogger().Error("error", err)
 		e := common.ErrInternalBackendError
@@ -84,8 +116,43 @@ func (s *Sender) Send(ctx context.Context, event *ceevent.Event) sender.PublishE
 		s.namedLogger().Error("error", err)
 		return common.ErrInternalBackendError
 	}
-	s.namedLogger().Error("error", string(body), "code", resp.StatusCode)
-	return common.BackendPublishError{HTTPCode: resp.StatusCode, Info: string(body)}
+	response := eventmesh.ParseErrorResponse(resp.StatusCode, body)
+	s.namedLogger().Errorw("Failed to publish event", "id", event.ID(), "type", event.Type(),
+		"code", response.StatusCode, "class", response.Class, "errorCode", response.Code,
+		"errorMessage", response.Message, "topic", response.Topic)
+	if s.Collector != nil {
+		s.Collector.RecordEventMeshError(string(response.Class), s.Target)
+	}
+	e := publishError(response)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		e.RetryHint = retryAfter(resp.Header.Get(internal.HeaderRetryAfter), time.Now())
+	}
+	return e
+}
+
+// publishError returns the error the clients are answered with for the given error response of EventMesh.
+// Errors which are not classified keep the status code of EventMesh and have no error type.
+func publishError(response eventmesh.ErrorResponse) common.BackendPublishError {
+	e := common.BackendPublishError{HTTPCode: response.Status(), Info: response.Description()}
+	if response.Class != eventmesh.ErrorClassOther {
+		e.ErrorType = string(response.Class)
+	}
+	return e
+}
+
+// retryAfter returns the duration of the given Retry-After header value, which is either a number of seconds
+// or an HTTP date. It returns zero if the value is empty, invalid or in the past.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
 }
 
 // NewSender returns a new Sender instance with the given target and client.
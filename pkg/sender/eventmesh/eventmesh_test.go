@@ -9,6 +9,10 @@ import (
 	"time"
 
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/oauth"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
@@ -32,10 +36,11 @@ const (
 func TestNewHttpMessageSender(t *testing.T) {
 	t.Parallel()
 
-	client := oauth.NewClient(context.Background(), &env.EventMeshConfig{})
-	defer client.CloseIdleConnections()
 	mockedLogger, err := logger.New("json", "info")
 	require.NoError(t, err)
+	client, err := oauth.NewClient(context.Background(), &env.EventMeshConfig{}, nil, mockedLogger)
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
 	msgSender := NewSender(eventsEndpoint, client, mockedLogger)
 	if msgSender.Target != eventsEndpoint {
 		t.Errorf("Message sender target is misconfigured want: %s but got: %s", eventsEndpoint, msgSender.Target)
@@ -49,11 +54,11 @@ func TestNewRequestWithTarget(t *testing.T) {
 	t.Parallel()
 
 	cfg := &env.EventMeshConfig{MaxIdleConns: maxIdleConns, MaxIdleConnsPerHost: maxIdleConnsPerHost}
-	client := oauth.NewClient(context.Background(), cfg)
-	defer client.CloseIdleConnections()
-
 	mockedLogger, err := logger.New("json", "info")
 	require.NoError(t, err)
+	client, err := oauth.NewClient(context.Background(), cfg, nil, mockedLogger)
+	require.NoError(t, err)
+	defer client.CloseIdleConnections()
 	msgSender := NewSender(eventsEndpoint, client, mockedLogger)
 
 	type ctxKey struct{}
@@ -167,6 +172,7 @@ func TestSender_Send(t *testing.T) {
 			},
 			wantErr: common.BackendPublishError{
 				HTTPCode: 400,
+				Info:     "publishing to EventMesh failed",
 			},
 		},
 		{
@@ -200,12 +206,203 @@ func TestSender_Send(t *testing.T) {
 	}
 }
 
+func TestSender_Send_Rules(t *testing.T) {
+	rules := &eventmesh.Rules{Rules: []eventmesh.Rule{
+		{
+			Types:       []string{"prefix.app.telemetry.>"},
+			Qos:         eventmesh.QosAtMostOnce,
+			ContentMode: eventmesh.ContentModeStructured,
+			Headers:     map[string]string{"x-priority": "low"},
+		},
+		{Types: []string{"prefix.app.order.>"}, ContentMode: eventmesh.ContentModeBinary},
+	}}
+
+	testCases := []struct {
+		name            string
+		givenRules      *eventmesh.Rules
+		givenType       string
+		wantQos         string
+		wantContentType string
+		wantHeaders     map[string]string
+	}{
+		{
+			name:            "without rules",
+			givenType:       "prefix.app.telemetry.cpu.v1",
+			wantQos:         string(eventmesh.QosAtLeastOnce),
+			wantContentType: "application/json",
+			wantHeaders:     map[string]string{"Ce-Type": "prefix.app.telemetry.cpu.v1"},
+		},
+		{
+			name:            "structured with qos and extra header",
+			givenRules:      rules,
+			givenType:       "prefix.app.telemetry.cpu.v1",
+			wantQos:         string(eventmesh.QosAtMostOnce),
+			wantContentType: "application/cloudevents+json",
+			wantHeaders:     map[string]string{"Ce-Type": "", "X-Priority": "low"},
+		},
+		{
+			name:            "binary with default qos",
+			givenRules:      rules,
+			givenType:       "prefix.app.order.created.v1",
+			wantQos:         string(eventmesh.QosAtLeastOnce),
+			wantContentType: "application/json",
+			wantHeaders:     map[string]string{"Ce-Type": "prefix.app.order.created.v1", "X-Priority": ""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			handler := &HandlerStub{ResponseStatus: http.StatusNoContent}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			mockedLogger, err := logger.New("json", "info")
+			require.NoError(t, err)
+			s := NewSender(server.URL, server.Client(), mockedLogger)
+			s.Rules = tc.givenRules
+			event := epptestingutils.NewCloudEventBuilder(epptestingutils.WithCloudEventType(tc.givenType)).Build(t)
+
+			// when
+			err = s.Send(context.Background(), event)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantQos, handler.Request.Header.Get("qos"))
+			assert.Equal(t, "application/json", handler.Request.Header.Get("Accept"))
+			assert.Equal(t, tc.wantContentType, handler.Request.Header.Get("Content-Type"))
+			for name, value := range tc.wantHeaders {
+				assert.Equal(t, value, handler.Request.Header.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestSender_Send_Source(t *testing.T) {
+	// given
+	handler := &HandlerStub{ResponseStatus: http.StatusNoContent}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	mockedLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	s := NewSender(server.URL, server.Client(), mockedLogger)
+	s.Source = "/target/namespace"
+	event := epptestingutils.NewCloudEventBuilder(epptestingutils.WithCloudEventSource("/default/namespace")).Build(t)
+
+	// when
+	err = s.Send(context.Background(), event)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "/target/namespace", handler.Request.Header.Get("Ce-Source"))
+	assert.Equal(t, "/default/namespace", event.Source())
+}
+
+func TestSender_Send_ErrorResponse(t *testing.T) {
+	// given
+	handler := &HandlerStub{
+		ResponseStatus: http.StatusBadRequest,
+		ResponseBody:   `{"code":400,"message":"Topic not found","topic":"namespace/app/order/created/v1"}`,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	mockedLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := NewSender(server.URL, server.Client(), mockedLogger)
+	s.Collector = collector
+
+	// when
+	err = s.Send(context.Background(), epptestingutils.NewCloudEventBuilder().Build(t))
+
+	// then the EventMesh error message is not returned to the client
+	assert.Equal(t, common.BackendPublishError{
+		HTTPCode:  http.StatusNotFound,
+		Info:      "event type is not known to EventMesh",
+		ErrorType: string(eventmesh.ErrorClassUnknownTopic),
+	}, err)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_eventmesh_errors_total The total number of publish requests rejected by EventMesh by error class
+		# TYPE eventing_epp_eventmesh_errors_total counter
+		eventing_epp_eventmesh_errors_total{class="unknown_topic",destination_service="`+server.URL+`"} 1
+	`, metrics.EventMeshErrorsKey)
+}
+
+func TestSender_Send_RetryAfter(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenStatus     int
+		givenRetryAfter string
+		wantCode        int
+		wantRetryAfter  time.Duration
+	}{
+		{
+			name:            "throttled",
+			givenStatus:     http.StatusTooManyRequests,
+			givenRetryAfter: "5",
+			wantCode:        http.StatusTooManyRequests,
+			wantRetryAfter:  5 * time.Second,
+		},
+		{
+			name:            "unavailable without retry hint",
+			givenStatus:     http.StatusServiceUnavailable,
+			givenRetryAfter: "",
+			wantCode:        http.StatusServiceUnavailable,
+			wantRetryAfter:  0,
+		},
+		{
+			name:            "retry hint is ignored for other status codes",
+			givenStatus:     http.StatusBadRequest,
+			givenRetryAfter: "5",
+			wantCode:        http.StatusBadRequest,
+			wantRetryAfter:  0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			handler := &HandlerStub{
+				ResponseStatus:  tc.givenStatus,
+				ResponseHeaders: http.Header{"Retry-After": []string{tc.givenRetryAfter}},
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			mockedLogger, err := logger.New("json", "info")
+			require.NoError(t, err)
+			s := NewSender(server.URL, server.Client(), mockedLogger)
+
+			// when
+			err = s.Send(context.Background(), epptestingutils.NewCloudEventBuilder().Build(t))
+
+			// then
+			var retryAfterErr sender.RetryAfterError
+			require.ErrorAs(t, err, &retryAfterErr)
+			assert.Equal(t, tc.wantCode, retryAfterErr.Code())
+			assert.Equal(t, tc.wantRetryAfter, retryAfterErr.RetryAfter())
+		})
+	}
+}
+
+func Test_retryAfter(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2*time.Second, retryAfter("2", now))
+	assert.Equal(t, 30*time.Second, retryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), retryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), retryAfter("-1", now))
+	assert.Equal(t, time.Duration(0), retryAfter("soon", now))
+}
+
 type HandlerStub struct {
-	Request        http.Request
-	ResponseStatus int
+	Request         http.Request
+	ResponseStatus  int
+	ResponseBody    string
+	ResponseHeaders http.Header
 }
 
 func (h *HandlerStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	h.Request = *request
+	for name, values := range h.ResponseHeaders {
+		writer.Header()[name] = values
+	}
 	writer.WriteHeader(h.ResponseStatus)
+	_, _ = writer.Write([]byte(h.ResponseBody))
 }
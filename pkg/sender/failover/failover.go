@@ -0,0 +1,226 @@
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	handlerName = "failover-handler"
+
+	// Primary is the name of the primary backend.
+	Primary = "primary"
+	// Secondary is the name of the secondary backend.
+	Secondary = "secondary"
+)
+
+// compile time check.
+var (
+	_ sender.GenericSender = &Sender{}
+	_ health.Checker       = &Sender{}
+)
+
+// Config configures when the Sender fails over to the secondary backend and when it fails back.
+type Config struct {
+	// ErrorThreshold is the number of consecutive server errors of the primary backend which cause a failover.
+	ErrorThreshold int
+	// CheckInterval is the interval of the readiness checks of the primary backend.
+	CheckInterval time.Duration
+	// FailbackAfter is the period the primary backend has to be healthy before failing back to it.
+	FailbackAfter time.Duration
+}
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	ActiveBackend  string `json:"activeBackend"`
+	PrimaryReady   bool   `json:"primaryReady"`
+	SecondaryReady bool   `json:"secondaryReady"`
+}
+
+// Sender publishes events to the primary backend and fails over to the secondary backend
+// if the primary backend is not ready or keeps failing.
+type Sender struct {
+	primary   sender.GenericSender
+	secondary sender.GenericSender
+	cfg       Config
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+
+	mutex               sync.Mutex
+	failedOver          bool
+	consecutiveErrors   int
+	primaryHealthySince time.Time
+	now                 func() time.Time
+}
+
+// NewSender returns a new Sender instance which uses the primary backend initially.
+func NewSender(primary, secondary sender.GenericSender, cfg Config, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Sender {
+	s := &Sender{
+		primary:   primary,
+		secondary: secondary,
+		cfg:       cfg,
+		collector: collector,
+		logger:    logger,
+		now:       time.Now,
+	}
+	s.recordActiveBackend()
+	return s
+}
+
+// Start starts checking the readiness of the primary backend periodically until the given context is done.
+func (s *Sender) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.check(ctx)
+			}
+		}
+	}()
+}
+
+// URL returns the URL of the active backend.
+func (s *Sender) URL() string {
+	if s.isFailedOver() {
+		return s.secondary.URL()
+	}
+	return s.primary.URL()
+}
+
+// Send dispatches the event to the active backend. If this causes the primary backend to exceed
+// the error threshold, the event is dispatched to the secondary backend instead.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	if s.isFailedOver() {
+		return s.secondary.Send(ctx, event)
+	}
+
+	err := s.primary.Send(ctx, event)
+	if !s.recordPrimaryResult(err) {
+		return err
+	}
+	return s.secondary.Send(ctx, event)
+}
+
+// recordPrimaryResult counts the consecutive server errors of the primary backend
+// and returns true if the Sender failed over because of them.
+func (s *Sender) recordPrimaryResult(err sender.PublishError) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err == nil || err.Code() < http.StatusInternalServerError {
+		s.consecutiveErrors = 0
+		return false
+	}
+	s.consecutiveErrors++
+	if s.failedOver || s.consecutiveErrors < s.cfg.ErrorThreshold {
+		return false
+	}
+	s.failOverLocked("error threshold exceeded")
+	return true
+}
+
+// check fails over if the primary backend is not ready, and fails back once it was ready for the configured period.
+func (s *Sender) check(ctx context.Context) {
+	ready := isReady(ctx, s.primary)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case !s.failedOver && !ready:
+		s.failOverLocked("readiness check failed")
+	case s.failedOver && !ready:
+		s.primaryHealthySince = time.Time{}
+	case s.failedOver && ready:
+		if s.primaryHealthySince.IsZero() {
+			s.primaryHealthySince = s.now()
+		}
+		if s.now().Sub(s.primaryHealthySince) >= s.cfg.FailbackAfter {
+			s.failedOver = false
+			s.consecutiveErrors = 0
+			s.recordActiveBackend()
+			s.namedLogger().Infow("Failed back to primary backend", "url", s.primary.URL())
+		}
+	}
+}
+
+func (s *Sender) failOverLocked(reason string) {
+	s.failedOver = true
+	s.primaryHealthySince = time.Time{}
+	s.recordActiveBackend()
+	s.namedLogger().Warnw("Failed over to secondary backend", "reason", reason, "url", s.secondary.URL())
+}
+
+func (s *Sender) isFailedOver() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.failedOver
+}
+
+func (s *Sender) recordActiveBackend() {
+	s.collector.SetFailoverActiveDestination(Primary, !s.failedOver)
+	s.collector.SetFailoverActiveDestination(Secondary, s.failedOver)
+}
+
+// ReadinessCheck reports 2XX if the active backend is ready, otherwise reports 5XX.
+// The response body lists the active backend and the readiness of both backends.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	resp := ReadinessResponse{
+		ActiveBackend:  Primary,
+		PrimaryReady:   isReady(r.Context(), s.primary),
+		SecondaryReady: isReady(r.Context(), s.secondary),
+	}
+	ready := resp.PrimaryReady
+	if s.isFailedOver() {
+		resp.ActiveBackend = Secondary
+		ready = resp.SecondaryReady
+	}
+
+	statusCode := health.StatusCodeHealthy
+	if !ready {
+		s.namedLogger().Errorw("Readiness check failed: active backend is not ready", "active", resp.ActiveBackend)
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
+
+// isReady returns true if the given sender is ready. Senders which do not implement health.Checker are always ready.
+func isReady(ctx context.Context, s sender.GenericSender) bool {
+	checker, ok := s.(health.Checker)
+	if !ok {
+		return true
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, health.ReadinessURI, nil)
+	if err != nil {
+		return false
+	}
+	return health.IsReady(checker, r)
+}
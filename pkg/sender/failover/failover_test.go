@@ -0,0 +1,221 @@
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	// given
+	primary := &senderStub{url: "primary", ready: true, err: common.ErrInternalBackendError}
+	secondary := &senderStub{url: "secondary", ready: true}
+	s := NewSender(primary, secondary, Config{ErrorThreshold: 2, FailbackAfter: time.Minute},
+		metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+
+	// when the primary backend fails below the threshold
+	err := s.Send(context.Background(), newEvent(t))
+
+	// then
+	assert.Equal(t, common.ErrInternalBackendError, err)
+	assert.Equal(t, 1, primary.sent)
+	assert.Equal(t, 0, secondary.sent)
+
+	// when the primary backend reaches the threshold
+	err = s.Send(context.Background(), newEvent(t))
+
+	// then the event is sent to the secondary backend
+	assert.Nil(t, err)
+	assert.Equal(t, 2, primary.sent)
+	assert.Equal(t, 1, secondary.sent)
+	assert.Equal(t, "secondary", s.URL())
+
+	// when failed over
+	err = s.Send(context.Background(), newEvent(t))
+
+	// then the primary backend is not used anymore
+	assert.Nil(t, err)
+	assert.Equal(t, 2, primary.sent)
+	assert.Equal(t, 2, secondary.sent)
+}
+
+func TestSender_Send_ClientErrorsDoNotCount(t *testing.T) {
+	t.Parallel()
+
+	// given
+	primary := &senderStub{url: "primary", ready: true, err: common.ErrClientConversionFailed}
+	secondary := &senderStub{url: "secondary", ready: true}
+	s := NewSender(primary, secondary, Config{ErrorThreshold: 1},
+		metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+
+	// when
+	err := s.Send(context.Background(), newEvent(t))
+
+	// then
+	assert.Equal(t, common.ErrClientConversionFailed, err)
+	assert.Equal(t, 0, secondary.sent)
+	assert.Equal(t, "primary", s.URL())
+}
+
+func TestSender_check(t *testing.T) {
+	t.Parallel()
+
+	// given
+	now := time.Now()
+	primary := &senderStub{url: "primary", ready: false}
+	secondary := &senderStub{url: "secondary", ready: true}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := NewSender(primary, secondary, Config{FailbackAfter: time.Minute}, collector, newLogger(t))
+	s.now = func() time.Time { return now }
+
+	// when the primary backend is not ready
+	s.check(context.Background())
+
+	// then
+	assert.Equal(t, "secondary", s.URL())
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_failover_active_destination The destination of the failover used for publishing. `+"`1`"+` indicates the active destination
+		# TYPE eventing_epp_failover_active_destination gauge
+		eventing_epp_failover_active_destination{destination="primary"} 0
+		eventing_epp_failover_active_destination{destination="secondary"} 1
+	`, metrics.FailoverActiveDestinationKey)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, "", metrics.ActiveBackendKey)
+
+	// when the primary backend is ready again but not for long enough
+	primary.ready = true
+	s.check(context.Background())
+	now = now.Add(30 * time.Second)
+	s.check(context.Background())
+
+	// then
+	assert.Equal(t, "secondary", s.URL())
+
+	// when the primary backend is healthy for the configured period
+	now = now.Add(30 * time.Second)
+	s.check(context.Background())
+
+	// then
+	assert.Equal(t, "primary", s.URL())
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenFailover  bool
+		givenPrimary   bool
+		givenSecondary bool
+		wantStatusCode int
+		wantResponse   ReadinessResponse
+	}{
+		{
+			name:           "should be ready if the primary backend is active and ready",
+			givenPrimary:   true,
+			givenSecondary: false,
+			wantStatusCode: health.StatusCodeHealthy,
+			wantResponse:   ReadinessResponse{ActiveBackend: Primary, PrimaryReady: true},
+		},
+		{
+			name:           "should be ready if the secondary backend is active and ready",
+			givenFailover:  true,
+			givenPrimary:   false,
+			givenSecondary: true,
+			wantStatusCode: health.StatusCodeHealthy,
+			wantResponse:   ReadinessResponse{ActiveBackend: Secondary, SecondaryReady: true},
+		},
+		{
+			name:           "should not be ready if the active backend is not ready",
+			givenFailover:  true,
+			givenPrimary:   true,
+			givenSecondary: false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+			wantResponse:   ReadinessResponse{ActiveBackend: Secondary, PrimaryReady: true},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := NewSender(&senderStub{ready: tc.givenPrimary}, &senderStub{ready: tc.givenSecondary}, Config{},
+				metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+			s.failedOver = tc.givenFailover
+			writer := httptest.NewRecorder()
+
+			// when
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+			var gotResponse ReadinessResponse
+			require.NoError(t, json.NewDecoder(strings.NewReader(writer.Body.String())).Decode(&gotResponse))
+			assert.Equal(t, tc.wantResponse, gotResponse)
+		})
+	}
+}
+
+type senderStub struct {
+	err   sender.PublishError
+	url   string
+	ready bool
+	sent  int
+}
+
+func (s *senderStub) Send(_ context.Context, _ *ceevent.Event) sender.PublishError {
+	s.sent++
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newEvent(t *testing.T) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType("order.created.v1")
+	event.SetSource("source")
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
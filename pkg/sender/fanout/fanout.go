@@ -0,0 +1,245 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	handlerName = "fanout-handler"
+)
+
+// Policy decides if a fan-out publish is successful based on the results of the single destinations.
+type Policy string
+
+const (
+	// PolicyAll requires all destinations to succeed.
+	PolicyAll Policy = "all"
+	// PolicyPrimary requires the primary (first) destination to succeed.
+	PolicyPrimary Policy = "primary"
+	// PolicyAny requires at least one destination to succeed.
+	PolicyAny Policy = "any"
+)
+
+// IsValid returns true if the Policy is one of the supported policies.
+func (p Policy) IsValid() bool {
+	return p == PolicyAll || p == PolicyPrimary || p == PolicyAny
+}
+
+// compile time check.
+var (
+	_ sender.GenericSender   = &Sender{}
+	_ sender.LatencyRecorder = &Sender{}
+	_ health.Checker         = &Sender{}
+	_ sender.PublishError    = &PublishError{}
+)
+
+// Destination is a named backend the Sender publishes to.
+type Destination struct {
+	Name   string
+	Sender sender.GenericSender
+	// Builder builds the events as per specifications of the backend. It is optional, the events are sent as they are
+	// built by the handler if it is nil or if the event as it was received is unknown.
+	Builder builder.CloudEventBuilder
+}
+
+// Sender publishes every event to all destinations in parallel.
+// The first destination is the primary one.
+type Sender struct {
+	destinations []Destination
+	policy       Policy
+	collector    metrics.PublishingMetricsCollector
+	logger       *logger.Logger
+}
+
+// NewSender returns a new Sender instance for the given destinations and policy.
+func NewSender(destinations []Destination, policy Policy, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Sender {
+	return &Sender{destinations: destinations, policy: policy, collector: collector, logger: logger}
+}
+
+// RecordsLatency returns true, since the backend latency is recorded per destination.
+func (s *Sender) RecordsLatency() bool {
+	return true
+}
+
+// URL returns the comma separated URLs of all destinations.
+func (s *Sender) URL() string {
+	urls := make([]string, 0, len(s.destinations))
+	for _, d := range s.destinations {
+		urls = append(urls, d.Sender.URL())
+	}
+	return strings.Join(urls, ",")
+}
+
+// Send dispatches the event to all destinations in parallel and applies the configured Policy on the results.
+// Each destination receives its own copy of the event, built by the builder of the destination.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	errs := make([]sender.PublishError, len(s.destinations))
+
+	var wg sync.WaitGroup
+	for i, d := range s.destinations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e, err := s.build(ctx, d, event)
+			if err != nil {
+				errs[i] = err
+				s.namedLogger().Errorw("Failed to build event for destination",
+					"destination", d.Name, "id", event.ID(), "error", err)
+				return
+			}
+			start := time.Now()
+			errs[i] = d.Sender.Send(ctx, e)
+			code := http.StatusNoContent
+			if errs[i] != nil {
+				code = errs[i].Code()
+				s.namedLogger().Errorw("Failed to send event to destination",
+					"destination", d.Name, "id", event.ID(), "error", errs[i])
+			}
+			s.collector.RecordBackendLatency(time.Since(start), code, d.Sender.URL())
+		}()
+	}
+	wg.Wait()
+
+	return s.evaluate(errs)
+}
+
+// build returns a copy of the given event with the type and source the builder of the given destination builds from
+// the event as it was received. The other attributes and the extensions, e.g. the enriched ones, are kept.
+func (s *Sender) build(ctx context.Context, d Destination, built *event.Event) (*event.Event, sender.PublishError) {
+	e := built.Clone()
+	received, ok := builder.ReceivedEvent(ctx)
+	if d.Builder == nil || !ok {
+		return &e, nil
+	}
+	rebuilt, err := d.Builder.Build(*received)
+	if err != nil {
+		publishErr := common.ErrClientConversionFailed
+		publishErr.Wrap(err)
+		return nil, &publishErr
+	}
+	e.SetType(rebuilt.Type())
+	e.SetSource(rebuilt.Source())
+	return &e, nil
+}
+
+// evaluate applies the Policy on the results of the destinations.
+func (s *Sender) evaluate(errs []sender.PublishError) sender.PublishError {
+	failed := &PublishError{}
+	for i, err := range errs {
+		if err != nil {
+			failed.Errors = append(failed.Errors, DestinationError{Destination: s.destinations[i].Name, Err: err})
+		}
+	}
+	if len(failed.Errors) == 0 {
+		return nil
+	}
+
+	switch s.policy {
+	case PolicyPrimary:
+		if errs[0] == nil {
+			return nil
+		}
+	case PolicyAny:
+		if len(failed.Errors) < len(errs) {
+			return nil
+		}
+	case PolicyAll:
+	}
+	return failed
+}
+
+// ReadinessCheck reports 2XX if the destinations required by the Policy are ready, otherwise reports 5XX.
+// Destinations which do not implement health.Checker are considered ready.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	ready := 0
+	primaryReady := false
+	for i, d := range s.destinations {
+		checker, ok := d.Sender.(health.Checker)
+		if ok && !health.IsReady(checker, r) {
+			s.namedLogger().Errorw("Readiness check failed for destination", "destination", d.Name)
+			continue
+		}
+		ready++
+		primaryReady = primaryReady || i == 0
+	}
+
+	healthy := false
+	switch s.policy {
+	case PolicyAll:
+		healthy = ready == len(s.destinations)
+	case PolicyPrimary:
+		healthy = primaryReady
+	case PolicyAny:
+		healthy = ready > 0
+	}
+	if !healthy {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName).With("policy", s.policy)
+}
+
+// DestinationError is the error returned by a single destination.
+type DestinationError struct {
+	Destination string
+	Err         sender.PublishError
+}
+
+// PublishError aggregates the errors of all failed destinations.
+// Its code is the code of the first failed destination, i.e. the primary one if it failed.
+type PublishError struct {
+	Errors []DestinationError
+}
+
+func (e *PublishError) Error() string {
+	return e.Message()
+}
+
+func (e *PublishError) Code() int {
+	if len(e.Errors) == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Errors[0].Err.Code()
+}
+
+func (e *PublishError) Message() string {
+	details := make([]string, 0, len(e.Errors))
+	for _, d := range e.Errors {
+		details = append(details, fmt.Sprintf("%s: %d %s", d.Destination, d.Err.Code(), d.Err.Message()))
+	}
+	return "failed to publish to destinations: " + strings.Join(details, "; ")
+}
+
+// Unwrap returns the errors of all failed destinations.
+func (e *PublishError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, d := range e.Errors {
+		errs = append(errs, d.Err)
+	}
+	return errs
+}
@@ -0,0 +1,332 @@
+package fanout
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/builder"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/backend/cleaner"
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenPolicy    Policy
+		givenPrimary   sender.PublishError
+		givenSecondary sender.PublishError
+		wantErr        bool
+		wantCode       int
+	}{
+		{
+			name:        "policy all should succeed if all destinations succeed",
+			givenPolicy: PolicyAll,
+			wantErr:     false,
+		},
+		{
+			name:           "policy all should fail if the secondary destination fails",
+			givenPolicy:    PolicyAll,
+			givenSecondary: common.ErrInsufficientStorage,
+			wantErr:        true,
+			wantCode:       http.StatusInsufficientStorage,
+		},
+		{
+			name:           "policy all should report the code of the primary destination if all destinations fail",
+			givenPolicy:    PolicyAll,
+			givenPrimary:   common.ErrClientNoConnection,
+			givenSecondary: common.ErrInsufficientStorage,
+			wantErr:        true,
+			wantCode:       http.StatusBadGateway,
+		},
+		{
+			name:           "policy primary should succeed if only the secondary destination fails",
+			givenPolicy:    PolicyPrimary,
+			givenSecondary: common.ErrInsufficientStorage,
+			wantErr:        false,
+		},
+		{
+			name:         "policy primary should fail if the primary destination fails",
+			givenPolicy:  PolicyPrimary,
+			givenPrimary: common.ErrClientNoConnection,
+			wantErr:      true,
+			wantCode:     http.StatusBadGateway,
+		},
+		{
+			name:         "policy any should succeed if one destination succeeds",
+			givenPolicy:  PolicyAny,
+			givenPrimary: common.ErrClientNoConnection,
+			wantErr:      false,
+		},
+		{
+			name:           "policy any should fail if all destinations fail",
+			givenPolicy:    PolicyAny,
+			givenPrimary:   common.ErrClientNoConnection,
+			givenSecondary: common.ErrInsufficientStorage,
+			wantErr:        true,
+			wantCode:       http.StatusBadGateway,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			primary := &senderStub{err: tc.givenPrimary, url: "primary"}
+			secondary := &senderStub{err: tc.givenSecondary, url: "secondary"}
+			collector := metrics.NewCollector(latency.NewBucketsProvider())
+			s := NewSender([]Destination{
+				{Name: "nats", Sender: primary},
+				{Name: "beb", Sender: secondary},
+			}, tc.givenPolicy, collector, newLogger(t))
+
+			// when
+			err := s.Send(context.Background(), newEvent(t))
+
+			// then
+			require.NotNil(t, primary.received)
+			require.NotNil(t, secondary.received)
+			assert.NotSame(t, primary.received, secondary.received)
+			metricstest.EnsureMetricLatency(t, collector, 2)
+			if !tc.wantErr {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tc.wantCode, err.Code())
+			if tc.givenPrimary != nil {
+				assert.ErrorIs(t, err, tc.givenPrimary)
+				assert.Contains(t, err.Message(), "nats: ")
+			}
+			if tc.givenSecondary != nil {
+				assert.ErrorIs(t, err, tc.givenSecondary)
+				assert.Contains(t, err.Message(), "beb: ")
+			}
+		})
+	}
+}
+
+func TestSender_Send_BuildPerDestination(t *testing.T) {
+	t.Parallel()
+
+	log := newLogger(t)
+	natsBuilder := builder.NewGenericBuilder("kyma", cleaner.NewJetStreamCleaner(log), nil, log)
+	eventMeshBuilder := builder.NewEventMeshBuilder("sap.kyma.custom", "/default/kyma/id",
+		cleaner.NewEventMeshCleaner(log), nil, log)
+
+	testCases := []struct {
+		name           string
+		givenPrimary   builder.CloudEventBuilder
+		givenSecondary builder.CloudEventBuilder
+		wantNATSIndex  int
+		wantMeshIndex  int
+	}{
+		{
+			name:           "should build the event for EventMesh if NATS is the primary destination",
+			givenPrimary:   natsBuilder,
+			givenSecondary: eventMeshBuilder,
+			wantNATSIndex:  0,
+			wantMeshIndex:  1,
+		},
+		{
+			name:           "should build the event for NATS if EventMesh is the primary destination",
+			givenPrimary:   eventMeshBuilder,
+			givenSecondary: natsBuilder,
+			wantNATSIndex:  1,
+			wantMeshIndex:  0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			received := newEvent(t)
+			received.SetSource("shop")
+			built, err := tc.givenPrimary.Build(*received)
+			require.NoError(t, err)
+			built.SetExtension("region", "eu10")
+			senders := []*senderStub{{url: "primary"}, {url: "secondary"}}
+			s := NewSender([]Destination{
+				{Name: "primary", Sender: senders[0]},
+				{Name: "secondary", Sender: senders[1], Builder: tc.givenSecondary},
+			}, PolicyAll, metrics.NewCollector(latency.NewBucketsProvider()), log)
+
+			// when
+			err = s.Send(builder.WithReceivedEvent(context.Background(), received), built)
+
+			// then
+			require.Nil(t, err)
+			natsEvent, meshEvent := senders[tc.wantNATSIndex].received, senders[tc.wantMeshIndex].received
+			assert.Equal(t, "kyma.shop.order.created.v1", natsEvent.Type())
+			assert.Equal(t, "shop", natsEvent.Source())
+			assert.Equal(t, "sap.kyma.custom.shop.order.created.v1", meshEvent.Type())
+			assert.Equal(t, "/default/kyma/id", meshEvent.Source())
+			for _, event := range []*ceevent.Event{natsEvent, meshEvent} {
+				assert.Equal(t, "id", event.ID())
+				assert.Equal(t, "eu10", event.Extensions()["region"])
+				assert.Equal(t, "order.created.v1", event.Extensions()[builder.OriginalTypeHeaderName])
+			}
+		})
+	}
+}
+
+func TestSender_Send_BuildFailure(t *testing.T) {
+	t.Parallel()
+
+	// given
+	log := newLogger(t)
+	primary, secondary := &senderStub{}, &senderStub{}
+	s := NewSender([]Destination{
+		{Name: "nats", Sender: primary},
+		{Name: "beb", Sender: secondary, Builder: builder.NewEventMeshBuilder("sap.kyma.custom", "/default/kyma/id",
+			cleaner.NewEventMeshCleaner(log), nil, log)},
+	}, PolicyPrimary, metrics.NewCollector(latency.NewBucketsProvider()), log)
+	received := newEvent(t)
+	received.SetType("order..v1")
+
+	// when
+	err := s.Send(builder.WithReceivedEvent(context.Background(), received), newEvent(t))
+
+	// then
+	assert.Nil(t, err)
+	assert.NotNil(t, primary.received)
+	assert.Nil(t, secondary.received)
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenPolicy    Policy
+		givenPrimary   bool
+		givenSecondary bool
+		wantStatusCode int
+	}{
+		{
+			name:           "policy all should be ready if all destinations are ready",
+			givenPolicy:    PolicyAll,
+			givenPrimary:   true,
+			givenSecondary: true,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "policy all should not be ready if one destination is not ready",
+			givenPolicy:    PolicyAll,
+			givenPrimary:   true,
+			givenSecondary: false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+		},
+		{
+			name:           "policy primary should be ready if the primary destination is ready",
+			givenPolicy:    PolicyPrimary,
+			givenPrimary:   true,
+			givenSecondary: false,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "policy primary should not be ready if the primary destination is not ready",
+			givenPolicy:    PolicyPrimary,
+			givenPrimary:   false,
+			givenSecondary: true,
+			wantStatusCode: health.StatusCodeNotHealthy,
+		},
+		{
+			name:           "policy any should be ready if one destination is ready",
+			givenPolicy:    PolicyAny,
+			givenPrimary:   false,
+			givenSecondary: true,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "policy any should not be ready if no destination is ready",
+			givenPolicy:    PolicyAny,
+			givenPrimary:   false,
+			givenSecondary: false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := NewSender([]Destination{
+				{Name: "nats", Sender: &senderStub{ready: tc.givenPrimary}},
+				{Name: "beb", Sender: &senderStub{ready: tc.givenSecondary}},
+			}, tc.givenPolicy, metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+			writer := httptest.NewRecorder()
+
+			// when
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+		})
+	}
+}
+
+type senderStub struct {
+	err      sender.PublishError
+	url      string
+	ready    bool
+	received *ceevent.Event
+}
+
+func (s *senderStub) Send(_ context.Context, event *ceevent.Event) sender.PublishError {
+	s.received = event
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newEvent(t *testing.T) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType("order.created.v1")
+	event.SetSource("source")
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
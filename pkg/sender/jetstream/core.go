@@ -0,0 +1,41 @@
+package jetstream
+
+import (
+	"slices"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/nats-io/nats.go"
+)
+
+// publishesWithCore returns true if events of the given type are published with core NATS instead of JetStream,
+// either because of the global publish mode or because the type matches one of the core publish type patterns.
+func (s *Sender) publishesWithCore(eventType string) bool {
+	if s.envCfg.PublishMode == env.PublishModeCore {
+		return true
+	}
+	return slices.ContainsFunc(s.envCfg.CorePublishTypes, func(pattern string) bool {
+		return eventtype.Matches(pattern, eventType)
+	})
+}
+
+// publishCore publishes the message with core NATS without waiting for an ack of the stream.
+// If CorePublishFlush is enabled, it waits until the server received the message.
+func (s *Sender) publishCore(msg *nats.Msg) sender.PublishError {
+	// a stream capturing the subject must not silently drop the message because of a mismatch
+	msg.Header.Del(nats.ExpectedStreamHdr)
+
+	if err := s.connection.PublishMsg(msg); err != nil {
+		s.namedLogger().Errorw("Cannot send event to backend", "error", err, "mode", env.PublishModeCore)
+		return natsErrorToPublishError(err)
+	}
+	if !s.envCfg.CorePublishFlush {
+		return nil
+	}
+	if err := s.connection.FlushTimeout(s.envCfg.RequestTimeout); err != nil {
+		s.namedLogger().Errorw("Cannot flush event to backend", "error", err, "mode", env.PublishModeCore)
+		return natsErrorToPublishError(err)
+	}
+	return nil
+}
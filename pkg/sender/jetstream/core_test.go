@@ -0,0 +1,80 @@
+package jetstream
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_Send_Core(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		givenPublishMode      string
+		givenCorePublishTypes []string
+		givenFlush            bool
+		wantCore              bool
+	}{
+		{
+			name:             "should publish with core NATS in core publish mode",
+			givenPublishMode: env.PublishModeCore,
+			givenFlush:       true,
+			wantCore:         true,
+		},
+		{
+			name:                  "should publish with core NATS if the event type matches a pattern",
+			givenPublishMode:      env.PublishModeJetStream,
+			givenCorePublishTypes: []string{"sap.>", epptestingutils.StreamName + "." + epptestingutils.Prefix + ".>"},
+			wantCore:              true,
+		},
+		{
+			name:                  "should publish with JetStream if the event type does not match a pattern",
+			givenPublishMode:      env.PublishModeJetStream,
+			givenCorePublishTypes: []string{"sap.>", epptestingutils.StreamName + ".*"},
+			wantCore:              false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			testEnv := setupTestEnvironment(t)
+			defer func() {
+				testEnv.Connection.Close()
+				testEnv.Server.Shutdown()
+			}()
+			// without a stream, only core NATS publishes succeed
+			testEnv.Config.PublishMode = tc.givenPublishMode
+			testEnv.Config.CorePublishTypes = tc.givenCorePublishTypes
+			testEnv.Config.CorePublishFlush = tc.givenFlush
+			testEnv.Config.RequestTimeout = time.Second
+			s := NewSender(context.Background(), testEnv.Connection, testEnv.Config, &options.Options{}, nil,
+				testEnv.Logger)
+			event := createCloudEvent(t)
+			subscription, err := testEnv.Connection.SubscribeSync(s.getJsSubjectToPublish(event.Type()))
+			require.NoError(t, err)
+
+			// when
+			sendErr := s.Send(context.Background(), event)
+
+			// then
+			if !tc.wantCore {
+				require.NotNil(t, sendErr)
+				assert.Equal(t, http.StatusGatewayTimeout, sendErr.Code())
+				return
+			}
+			assert.Nil(t, sendErr)
+			msg, err := subscription.NextMsg(time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, event.ID(), msg.Header.Get("ce-id"))
+			assert.Empty(t, msg.Header.Get("Nats-Expected-Stream"))
+		})
+	}
+}
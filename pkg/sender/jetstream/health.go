@@ -1,24 +1,135 @@
 package jetstream
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
 	"github.com/nats-io/nats.go"
 )
 
+// The names of the readiness checks.
+const (
+	CheckConnection = "connection"
+	CheckStream     = "stream"
+	CheckLeader     = "leader"
+	CheckStorage    = "storage"
+)
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	Ready  bool          `json:"ready"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// CheckResult is the result of a single readiness check.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Ready  bool   `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+}
+
 // ReadinessCheck returns an instance of http.HandlerFunc that checks the readiness of the given NATS Handler.
-// It checks the NATS server connection status and reports 2XX if connected, otherwise reports 5XX.
+// It checks the NATS server connection status and the configured stream. If JSReadinessStreamCheck is enabled,
+// it also checks that the stream has a leader and that its storage usage is below JSReadinessMaxStorageUsage.
+// The results of the stream checks are cached for JSReadinessCacheTTL.
+// It reports 2XX if all checks pass, otherwise reports 5XX, and lists the checks in a JSON body.
 // It panics if the given NATS Handler is nil.
 func (s *Sender) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
-	if status := s.ConnectionStatus(); status != nats.CONNECTED {
-		s.namedLogger().Error("Readiness check failed: not connected to nats server")
-		w.WriteHeader(health.StatusCodeNotHealthy)
-		return
+	resp := ReadinessResponse{Ready: true, Checks: []CheckResult{s.checkConnection()}}
+	if resp.Checks[0].Ready {
+		resp.Checks = append(resp.Checks, s.cachedStreamChecks()...)
 	}
-	w.WriteHeader(health.StatusCodeHealthy)
+
+	statusCode := health.StatusCodeHealthy
+	for _, check := range resp.Checks {
+		if !check.Ready {
+			s.namedLogger().Errorw("Readiness check failed", "check", check.Name, "reason", check.Reason)
+			resp.Ready = false
+			statusCode = health.StatusCodeNotHealthy
+		}
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(health.StatusCodeHealthy)
 }
+
+func (s *Sender) checkConnection() CheckResult {
+	if status := s.ConnectionStatus(); status != nats.CONNECTED {
+		return CheckResult{Name: CheckConnection, Reason: "not connected to nats server"}
+	}
+	return CheckResult{Name: CheckConnection, Ready: true}
+}
+
+// cachedStreamChecks returns the results of the stream checks and runs them again once the cached ones expired.
+func (s *Sender) cachedStreamChecks() []CheckResult {
+	s.streamChecksMutex.Lock()
+	defer s.streamChecksMutex.Unlock()
+
+	now := s.now()
+	if s.streamChecks != nil && now.Before(s.streamChecksExpiry) {
+		return s.streamChecks
+	}
+	s.streamChecks = s.runStreamChecks()
+	s.streamChecksExpiry = now.Add(s.envCfg.JSReadinessCacheTTL)
+	return s.streamChecks
+}
+
+func (s *Sender) runStreamChecks() []CheckResult {
+	info, err := s.verifyStream()
+	if err != nil {
+		return []CheckResult{{Name: CheckStream, Reason: err.Error()}}
+	}
+	s.recordStreamState(info)
+
+	checks := []CheckResult{{Name: CheckStream, Ready: true}}
+	if !s.envCfg.JSReadinessStreamCheck {
+		return checks
+	}
+	return append(checks, checkLeader(info), s.checkStorage(info))
+}
+
+func (s *Sender) recordStreamState(info *nats.StreamInfo) {
+	if s.collector == nil {
+		return
+	}
+	s.collector.SetJetStreamStreamState(info.Config.Name, info.State.Bytes, info.State.Msgs,
+		info.Config.MaxBytes, info.Config.MaxMsgs)
+}
+
+// checkLeader fails if the stream is replicated in a cluster without a leader.
+func checkLeader(info *nats.StreamInfo) CheckResult {
+	if info.Cluster != nil && info.Cluster.Leader == "" {
+		return CheckResult{Name: CheckLeader, Reason: "stream has no leader"}
+	}
+	return CheckResult{Name: CheckLeader, Ready: true}
+}
+
+// checkStorage fails if the bytes or messages stored in the stream exceed the configured ratio of its limits.
+func (s *Sender) checkStorage(info *nats.StreamInfo) CheckResult {
+	usage := storageUsage(info)
+	if usage >= s.envCfg.JSReadinessMaxStorageUsage {
+		return CheckResult{Name: CheckStorage, Reason: fmt.Sprintf("stream storage usage %.2f exceeds %.2f",
+			usage, s.envCfg.JSReadinessMaxStorageUsage)}
+	}
+	return CheckResult{Name: CheckStorage, Ready: true}
+}
+
+// storageUsage returns the highest ratio of the stored bytes and messages to the limits of the stream.
+// Unlimited streams have a usage of 0.
+func storageUsage(info *nats.StreamInfo) float64 {
+	usage := 0.0
+	if info.Config.MaxBytes > 0 {
+		usage = max(usage, float64(info.State.Bytes)/float64(info.Config.MaxBytes))
+	}
+	if info.Config.MaxMsgs > 0 {
+		usage = max(usage, float64(info.State.Msgs)/float64(info.Config.MaxMsgs))
+	}
+	return usage
+}
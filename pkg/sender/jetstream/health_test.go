@@ -0,0 +1,159 @@
+package jetstream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_ReadinessCheck_StreamChecks(t *testing.T) {
+	// given
+	testEnv := setupTestEnvironment(t)
+	defer func() {
+		testEnv.Connection.Close()
+		testEnv.Server.Shutdown()
+	}()
+	sc := getStreamConfig(-1)
+	sc.MaxMsgs = 2
+	sc.Retention = natsgo.LimitsPolicy
+	addStream(t, testEnv.Connection, sc)
+
+	testEnv.Config.JSReadinessStreamCheck = true
+	testEnv.Config.JSReadinessMaxStorageUsage = 0.9
+	testEnv.Config.JSReadinessCacheTTL = time.Minute
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := NewSender(context.Background(), testEnv.Connection, testEnv.Config, &options.Options{}, collector,
+		testEnv.Logger)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	// when
+	statusCode, resp := readiness(t, s)
+
+	// then
+	assert.Equal(t, health.StatusCodeHealthy, statusCode)
+	assert.Equal(t, ReadinessResponse{Ready: true, Checks: []CheckResult{
+		{Name: CheckConnection, Ready: true},
+		{Name: CheckStream, Ready: true},
+		{Name: CheckLeader, Ready: true},
+		{Name: CheckStorage, Ready: true},
+	}}, resp)
+
+	// when the stream is full
+	for range 2 {
+		require.Nil(t, s.Send(context.Background(), createCloudEvent(t)))
+	}
+	statusCode, _ = readiness(t, s)
+
+	// then the cached results are reported until they expire
+	assert.Equal(t, health.StatusCodeHealthy, statusCode)
+
+	// when
+	now = now.Add(time.Minute)
+	statusCode, resp = readiness(t, s)
+
+	// then
+	assert.Equal(t, health.StatusCodeNotHealthy, statusCode)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, CheckResult{Name: CheckStorage, Reason: "stream storage usage 1.00 exceeds 0.90"}, resp.Checks[3])
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_jetstream_stream_messages The number of messages stored in the JetStream stream
+		# TYPE eventing_epp_jetstream_stream_messages gauge
+		eventing_epp_jetstream_stream_messages{stream="kyma"} 2
+	`, metrics.JetStreamStreamMessagesKey)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_jetstream_stream_max_messages The maximum number of messages of the JetStream stream. `+
+		"`-1` means unlimited"+`
+		# TYPE eventing_epp_jetstream_stream_max_messages gauge
+		eventing_epp_jetstream_stream_max_messages{stream="kyma"} 2
+	`, metrics.JetStreamStreamMaxMessagesKey)
+}
+
+func TestSender_ReadinessCheck_NotConnected(t *testing.T) {
+	// given
+	testEnv := setupTestEnvironment(t)
+	testEnv.Server.Shutdown()
+	testEnv.Connection.Close()
+	s := NewSender(context.Background(), testEnv.Connection, testEnv.Config, &options.Options{}, nil, testEnv.Logger)
+
+	// when
+	statusCode, resp := readiness(t, s)
+
+	// then
+	assert.Equal(t, health.StatusCodeNotHealthy, statusCode)
+	assert.Equal(t, ReadinessResponse{Ready: false, Checks: []CheckResult{
+		{Name: CheckConnection, Reason: "not connected to nats server"},
+	}}, resp)
+}
+
+func Test_storageUsage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		givenInfo *natsgo.StreamInfo
+		want      float64
+	}{
+		{
+			name: "unlimited stream",
+			givenInfo: &natsgo.StreamInfo{
+				Config: natsgo.StreamConfig{MaxBytes: -1, MaxMsgs: -1},
+				State:  natsgo.StreamState{Bytes: 100, Msgs: 10},
+			},
+			want: 0,
+		},
+		{
+			name: "bytes limit",
+			givenInfo: &natsgo.StreamInfo{
+				Config: natsgo.StreamConfig{MaxBytes: 400, MaxMsgs: -1},
+				State:  natsgo.StreamState{Bytes: 100, Msgs: 10},
+			},
+			want: 0.25,
+		},
+		{
+			name: "highest of both limits",
+			givenInfo: &natsgo.StreamInfo{
+				Config: natsgo.StreamConfig{MaxBytes: 400, MaxMsgs: 20},
+				State:  natsgo.StreamState{Bytes: 100, Msgs: 10},
+			},
+			want: 0.5,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tc.want, storageUsage(tc.givenInfo), 0.001)
+		})
+	}
+}
+
+func Test_checkLeader(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, checkLeader(&natsgo.StreamInfo{}).Ready)
+	assert.True(t, checkLeader(&natsgo.StreamInfo{Cluster: &natsgo.ClusterInfo{Leader: "nats-0"}}).Ready)
+	assert.False(t, checkLeader(&natsgo.StreamInfo{Cluster: &natsgo.ClusterInfo{Name: "nats"}}).Ready)
+}
+
+func readiness(t *testing.T, s *Sender) (int, ReadinessResponse) {
+	t.Helper()
+	writer := httptest.NewRecorder()
+	s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+	var resp ReadinessResponse
+	require.NoError(t, json.NewDecoder(writer.Body).Decode(&resp))
+	return writer.Code, resp
+}
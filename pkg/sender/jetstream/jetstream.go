@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"github.com/kyma-project/eventing-publisher-proxy/internal"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
@@ -23,11 +28,24 @@ import (
 
 const (
 	JSStoreFailedCode     = 10077
+	JSStreamNotMatchCode  = 10060
 	natsBackend           = "nats"
 	handlerName           = "jetstream-handler"
 	noSpaceLeftErrMessage = "no space left on device"
+
+	// binaryModeContentTypeHeader is the header of the datacontenttype in binary content mode.
+	binaryModeContentTypeHeader = "content-type"
 )
 
+// binaryModeVersions are the CloudEvents spec versions with the `ce-` prefix of the attribute headers
+// in binary content mode.
+//
+//nolint:gochecknoglobals // immutable spec versions.
+var binaryModeVersions = spec.WithPrefix("ce-")
+
+// ErrUnsupportedSpecVersion is returned if an event with an unknown spec version is converted to binary content mode.
+var ErrUnsupportedSpecVersion = errors.New("unsupported CloudEvents spec version")
+
 // compile time check.
 var (
 	_ sender.GenericSender = &Sender{}
@@ -47,8 +65,20 @@ var (
 		HTTPCode: http.StatusInsufficientStorage,
 		Info:     "insufficient resources on target stream",
 	}
+	ErrStreamMismatch = common.BackendPublishError{
+		HTTPCode: http.StatusInternalServerError,
+		Info:     "subject is not bound to the configured stream",
+	}
+	ErrTooManyPendingMessages = common.BackendPublishError{
+		HTTPCode:  http.StatusServiceUnavailable,
+		Info:      "too many messages pending an ack from NATS JetStream server",
+		RetryHint: flowControlRetryAfter,
+	}
 )
 
+// flowControlRetryAfter is the retry hint for events rejected because JetStream cannot keep up with the publishers.
+const flowControlRetryAfter = time.Second
+
 // Sender is responsible for sending messages over HTTP.
 type Sender struct {
 	ctx        context.Context
@@ -56,6 +86,17 @@ type Sender struct {
 	connection *nats.Conn
 	envCfg     *env.NATSConfig
 	opts       *options.Options
+	collector  metrics.PublishingMetricsCollector
+
+	// jsCtx is the JetStream context shared by all sends, it is created on first use.
+	jsMutex sync.Mutex
+	jsCtx   nats.JetStreamContext
+
+	// streamChecks are the cached results of the stream readiness checks, they expire at streamChecksExpiry.
+	streamChecksMutex  sync.Mutex
+	streamChecks       []CheckResult
+	streamChecksExpiry time.Time
+	now                func() time.Time
 }
 
 func (s *Sender) URL() string {
@@ -64,9 +105,17 @@ func (s *Sender) URL() string {
 
 // NewSender returns a new NewSender instance with the given NATS connection.
 func NewSender(ctx context.Context, connection *nats.Conn, envCfg *env.NATSConfig, opts *options.Options,
-	logger *logger.Logger,
+	collector metrics.PublishingMetricsCollector, logger *logger.Logger,
 ) *Sender {
-	return &Sender{ctx: ctx, connection: connection, envCfg: envCfg, opts: opts, logger: logger}
+	return &Sender{
+		ctx:        ctx,
+		connection: connection,
+		envCfg:     envCfg,
+		opts:       opts,
+		collector:  collector,
+		logger:     logger,
+		now:        time.Now,
+	}
 }
 
 // ConnectionStatus returns nats.code for the NATS connection used by the Sender.
@@ -76,12 +125,15 @@ func (s *Sender) ConnectionStatus() nats.Status {
 
 // Send dispatches the event to the NATS backend in JetStream mode.
 // If the NATS connection is not open, it returns an error.
-func (s *Sender) Send(_ context.Context, event *event.Event) sender.PublishError {
+// Events matching the core publish mode are published with core NATS without an ack, see publishCore.
+// In async mode, the event is published with PublishMsgAsync and Send waits for its ack or the given context.
+// The number of events waiting for an ack is bounded by JSPublishAsyncMaxPending.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
 	if s.ConnectionStatus() != nats.CONNECTED {
 		return ErrNotConnected
 	}
 
-	jsCtx, err := s.connection.JetStream()
+	jsCtx, err := s.jetStream()
 	if err != nil {
 		s.namedLogger().Error("error", err)
 		return common.ErrClientNoConnection
@@ -96,39 +148,117 @@ func (s *Sender) Send(_ context.Context, event *event.Event) sender.PublishError
 	}
 
 	// send the event
-	_, err = jsCtx.PublishMsg(msg)
+	if s.publishesWithCore(event.Type()) {
+		return s.publishCore(msg)
+	}
+	if !s.envCfg.JSPublishAsync {
+		_, err = jsCtx.PublishMsg(msg)
+		if err != nil {
+			s.namedLogger().Errorw("Cannot send event to backend", "error", err)
+			return natsErrorToPublishError(err)
+		}
+		return nil
+	}
+
+	future, err := jsCtx.PublishMsgAsync(msg)
 	if err != nil {
 		s.namedLogger().Errorw("Cannot send event to backend", "error", err)
 		return natsErrorToPublishError(err)
 	}
-	return nil
+	select {
+	case <-future.Ok():
+		return nil
+	case err = <-future.Err():
+		s.namedLogger().Errorw("Cannot send event to backend", "error", err)
+		return natsErrorToPublishError(err)
+	case <-ctx.Done():
+		s.namedLogger().Errorw("Cannot send event to backend", "error", ctx.Err())
+		return ErrCannotSendToStream
+	}
+}
+
+// jetStream returns the shared JetStream context and creates it if needed.
+func (s *Sender) jetStream() (nats.JetStreamContext, error) {
+	s.jsMutex.Lock()
+	defer s.jsMutex.Unlock()
+	if s.jsCtx != nil {
+		return s.jsCtx, nil
+	}
+	jsOpts := []nats.JSOpt{nats.PublishAsyncTimeout(s.envCfg.RequestTimeout)}
+	if s.envCfg.JSPublishAsyncMaxPending > 0 {
+		jsOpts = append(jsOpts, nats.PublishAsyncMaxPending(s.envCfg.JSPublishAsyncMaxPending))
+	}
+	jsCtx, err := s.connection.JetStream(jsOpts...)
+	if err != nil {
+		return nil, err
+	}
+	s.jsCtx = jsCtx
+	return jsCtx, nil
 }
 
 func natsErrorToPublishError(err error) sender.PublishError {
-	if errors.Is(err, nats.ErrNoStreamResponse) {
+	if errors.Is(err, nats.ErrNoStreamResponse) || errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrAsyncPublishTimeout) || errors.Is(err, nats.ErrTimeout) {
 		return ErrCannotSendToStream
 	}
 
+	if errors.Is(err, nats.ErrTooManyStalledMsgs) {
+		return ErrTooManyPendingMessages
+	}
+
 	if strings.Contains(err.Error(), noSpaceLeftErrMessage) {
 		return ErrNoSpaceLeftOnDevice
 	}
 
 	var apiErr nats.JetStreamError
 	e := common.BackendPublishError{HTTPCode: http.StatusInternalServerError}
-	if errors.As(err, &apiErr) {
+	if errors.As(err, &apiErr) && apiErr.APIError() != nil {
 		if apiErr.APIError().ErrorCode == JSStoreFailedCode {
 			return ErrNoSpaceLeftOnDevice
 		}
+		if apiErr.APIError().ErrorCode == JSStreamNotMatchCode {
+			e := ErrStreamMismatch
+			e.Wrap(err)
+			return e
+		}
 		e.HTTPCode = apiErr.APIError().Code
 		e.Info = apiErr.APIError().Description
+		if e.HTTPCode == http.StatusTooManyRequests || e.HTTPCode == http.StatusServiceUnavailable {
+			e.RetryHint = flowControlRetryAfter
+		}
 		e.Wrap(err)
 		return e
 	}
 	return common.ErrInternalBackendError
 }
 
-// eventToNATSMsg translates cloud event into the NATS Msg.
+// eventToNATSMsg translates cloud event into the NATS Msg using the configured content mode.
 func (s *Sender) eventToNATSMsg(event *event.Event) (*nats.Msg, error) {
+	var header nats.Header
+	var data []byte
+	var err error
+	if s.envCfg.JSContentMode == env.ContentModeBinary {
+		header, data, err = toBinaryMessage(event)
+	} else {
+		header, data, err = toStructuredMessage(event)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.envCfg.JSStreamName != "" {
+		// the server rejects the message if its subject is bound to another stream
+		header.Set(nats.ExpectedStreamHdr, s.envCfg.JSStreamName)
+	}
+
+	return &nats.Msg{
+		Subject: s.getJsSubjectToPublish(event.Type()),
+		Header:  header,
+		Data:    data,
+	}, nil
+}
+
+// toStructuredMessage returns the whole event as JSON and duplicates a few attributes in the headers.
+func toStructuredMessage(event *event.Event) (nats.Header, []byte, error) {
 	header := make(nats.Header)
 	header.Set(internal.HeaderContentType, event.DataContentType())
 	header.Set(internal.CeSpecVersionHeader, event.SpecVersion())
@@ -138,14 +268,43 @@ func (s *Sender) eventToNATSMsg(event *event.Event) (*nats.Msg, error) {
 
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return header, eventJSON, nil
+}
 
-	return &nats.Msg{
-		Subject: s.getJsSubjectToPublish(event.Type()),
-		Header:  header,
-		Data:    eventJSON,
-	}, err
+// toBinaryMessage returns the event data and puts all attributes and extensions prefixed with `ce-` in the headers,
+// except for the datacontenttype which is put in the `content-type` header.
+func toBinaryMessage(event *event.Event) (nats.Header, []byte, error) {
+	version := binaryModeVersions.Version(event.SpecVersion())
+	if version == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedSpecVersion, event.SpecVersion())
+	}
+
+	header := make(nats.Header)
+	for _, attribute := range version.Attributes() {
+		value := attribute.Get(event.Context)
+		if value == nil {
+			continue
+		}
+		formatted, err := types.Format(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if attribute.Kind() == spec.DataContentType {
+			header.Set(binaryModeContentTypeHeader, formatted)
+			continue
+		}
+		header.Set(attribute.PrefixedName(), formatted)
+	}
+	for name, value := range event.Extensions() {
+		formatted, err := types.Format(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		header.Set(binaryModeVersions.Prefix()+name, formatted)
+	}
+	return header, event.Data(), nil
 }
 
 // getJsSubjectToPublish appends stream name to subject if needed.
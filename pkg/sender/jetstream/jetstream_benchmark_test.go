@@ -0,0 +1,79 @@
+package jetstream
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkSender_Send compares the throughput and the p99 latency of synchronous and asynchronous publishing
+// against the embedded NATS server. Run it with: go test -run=^$ -bench=BenchmarkSender_Send ./pkg/sender/jetstream/
+func BenchmarkSender_Send(b *testing.B) {
+	for _, async := range []bool{false, true} {
+		name := "sync"
+		if async {
+			name = "async"
+		}
+		b.Run(name, func(b *testing.B) {
+			benchmarkSend(b, async)
+		})
+	}
+}
+
+func benchmarkSend(b *testing.B, async bool) {
+	b.Helper()
+
+	// given
+	testEnv := setupTestEnvironment(b)
+	b.Cleanup(func() {
+		testEnv.Connection.Close()
+		testEnv.Server.Shutdown()
+	})
+	sc := getStreamConfig(-1)
+	_, err := (*testEnv.JsContext).AddStream(sc)
+	require.NoError(b, err)
+
+	testEnv.Config.JSPublishAsync = async
+	testEnv.Config.JSPublishAsyncMaxPending = 4000
+	testEnv.Config.RequestTimeout = 5 * time.Second
+	s := NewSender(context.Background(), testEnv.Connection, testEnv.Config, &options.Options{}, nil,
+		testEnv.Logger)
+	event := createCloudEvent(b)
+
+	var mutex sync.Mutex
+	latencies := make([]time.Duration, 0, b.N)
+
+	// when
+	b.ReportAllocs()
+	b.SetParallelism(64)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		local := make([]time.Duration, 0)
+		for pb.Next() {
+			start := time.Now()
+			if err := s.Send(context.Background(), event); err != nil {
+				b.Error(err)
+				return
+			}
+			local = append(local, time.Since(start))
+		}
+		mutex.Lock()
+		latencies = append(latencies, local...)
+		mutex.Unlock()
+	})
+	b.StopTimer()
+
+	// then
+	if len(latencies) == 0 {
+		return
+	}
+	slices.Sort(latencies)
+	p99 := latencies[len(latencies)*99/100]
+	b.ReportMetric(float64(p99.Microseconds()), "p99-µs")
+	b.ReportMetric(float64(len(latencies))/b.Elapsed().Seconds(), "events/s")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
 	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
 	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
 	"github.com/nats-io/nats-server/v2/server"
 	natsgo "github.com/nats-io/nats.go"
@@ -57,41 +59,44 @@ func TestJetStreamMessageSender(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			// arrange
-			testEnv := setupTestEnvironment(t)
-			natsServer, connection, mockedLogger := testEnv.Server, testEnv.Connection, testEnv.Logger
-
-			defer func() {
-				natsServer.Shutdown()
-				connection.Close()
-			}()
-
-			if tc.givenStream {
-				sc := getStreamConfig(tc.givenStreamMaxBytes)
-				cc := getConsumerConfig()
-				addStream(t, connection, sc)
-				addConsumer(t, connection, sc, cc)
-			}
-
-			ce := createCloudEvent(t)
-
-			ctx := context.Background()
-			sender := NewSender(context.Background(), connection, testEnv.Config, &options.Options{}, mockedLogger)
-
-			if tc.givenNATSConnectionClosed {
-				connection.Close()
-			}
-
-			// act
-			err := sender.Send(ctx, ce)
-
-			testEnv.Logger.WithContext().Errorf("err: %v", err)
-
-			// assert
-			assert.ErrorIs(t, err, tc.wantErr)
-		})
+		for _, async := range []bool{false, true} {
+			tc, async := tc, async
+			t.Run(fmt.Sprintf("%s (async: %t)", tc.name, async), func(t *testing.T) {
+				// arrange
+				testEnv := setupTestEnvironment(t)
+				testEnv.Config.JSPublishAsync = async
+				natsServer, connection, mockedLogger := testEnv.Server, testEnv.Connection, testEnv.Logger
+
+				defer func() {
+					natsServer.Shutdown()
+					connection.Close()
+				}()
+
+				if tc.givenStream {
+					sc := getStreamConfig(tc.givenStreamMaxBytes)
+					cc := getConsumerConfig()
+					addStream(t, connection, sc)
+					addConsumer(t, connection, sc, cc)
+				}
+
+				ce := createCloudEvent(t)
+
+				ctx := context.Background()
+				sender := NewSender(context.Background(), connection, testEnv.Config, &options.Options{}, nil, mockedLogger)
+
+				if tc.givenNATSConnectionClosed {
+					connection.Close()
+				}
+
+				// act
+				err := sender.Send(ctx, ce)
+
+				testEnv.Logger.WithContext().Errorf("err: %v", err)
+
+				// assert
+				assert.ErrorIs(t, err, tc.wantErr)
+			})
+		}
 	}
 }
 
@@ -107,7 +112,7 @@ type TestEnvironment struct {
 }
 
 // setupTestEnvironment sets up the resources and mocks required for testing.
-func setupTestEnvironment(t *testing.T) *TestEnvironment {
+func setupTestEnvironment(t testing.TB) *TestEnvironment {
 	t.Helper()
 	natsServer := epptestingutils.StartNATSServer()
 	require.NotNil(t, natsServer)
@@ -141,7 +146,7 @@ func setupTestEnvironment(t *testing.T) *TestEnvironment {
 }
 
 // createCloudEvent build a cloud event.
-func createCloudEvent(t *testing.T) *event.Event {
+func createCloudEvent(t testing.TB) *event.Event {
 	t.Helper()
 	jsType := fmt.Sprintf("%s.%s", epptestingutils.StreamName, epptestingutils.CloudEventTypeWithPrefix)
 	builder := epptestingutils.NewCloudEventBuilder(
@@ -285,3 +290,118 @@ func TestSender_getJsSubjectToPublish(t *testing.T) {
 		})
 	}
 }
+
+func TestSender_eventToNATSMsg_ContentMode(t *testing.T) {
+	t.Parallel()
+
+	// given
+	givenEvent := ce.NewEvent()
+	givenEvent.SetID("id")
+	givenEvent.SetType("prefix.app.order.created.v1")
+	givenEvent.SetSource("source")
+	givenEvent.SetTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	givenEvent.SetExtension("originaltype", "order.created.v1")
+	givenEvent.SetExtension("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, givenEvent.SetData(ce.ApplicationJSON, map[string]string{"foo": "bar"}))
+	structured, err := json.Marshal(givenEvent)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		givenMode  string
+		wantHeader natsgo.Header
+		wantData   []byte
+	}{
+		{
+			name:      "should put the whole event into the body in structured mode",
+			givenMode: env.ContentModeStructured,
+			wantHeader: natsgo.Header{
+				"Content-Type":   []string{ce.ApplicationJSON},
+				"ce-specversion": []string{"1.0"},
+				"ce-type":        []string{"prefix.app.order.created.v1"},
+				"ce-source":      []string{"source"},
+				"ce-id":          []string{"id"},
+			},
+			wantData: structured,
+		},
+		{
+			name:      "should put the attributes into the headers and the data into the body in binary mode",
+			givenMode: env.ContentModeBinary,
+			wantHeader: natsgo.Header{
+				"content-type":    []string{ce.ApplicationJSON},
+				"ce-specversion":  []string{"1.0"},
+				"ce-type":         []string{"prefix.app.order.created.v1"},
+				"ce-source":       []string{"source"},
+				"ce-id":           []string{"id"},
+				"ce-time":         []string{"2024-01-02T03:04:05Z"},
+				"ce-originaltype": []string{"order.created.v1"},
+				"ce-traceparent":  []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+			wantData: []byte(`{"foo":"bar"}`),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Sender{envCfg: &env.NATSConfig{JSContentMode: tc.givenMode, EventTypePrefix: "prefix"}}
+
+			// when
+			msg, err := s.eventToNATSMsg(&givenEvent)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHeader, msg.Header)
+			assert.Equal(t, tc.wantData, msg.Data)
+			assert.Equal(t, "kyma.prefix.app.order.created.v1", msg.Subject)
+		})
+	}
+}
+
+func Test_natsErrorToPublishError_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenErr       error
+		wantCode       int
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "too many stalled messages",
+			givenErr:       natsgo.ErrTooManyStalledMsgs,
+			wantCode:       http.StatusServiceUnavailable,
+			wantRetryAfter: flowControlRetryAfter,
+		},
+		{
+			name:           "unavailable JetStream API",
+			givenErr:       &natsgo.APIError{Code: http.StatusServiceUnavailable, Description: "unavailable"},
+			wantCode:       http.StatusServiceUnavailable,
+			wantRetryAfter: flowControlRetryAfter,
+		},
+		{
+			name:           "no retry hint for other errors",
+			givenErr:       &natsgo.APIError{Code: http.StatusBadRequest, Description: "bad request"},
+			wantCode:       http.StatusBadRequest,
+			wantRetryAfter: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			err := natsErrorToPublishError(tc.givenErr)
+
+			// then
+			var retryAfterErr sender.RetryAfterError
+			require.ErrorAs(t, err, &retryAfterErr)
+			assert.Equal(t, tc.wantCode, retryAfterErr.Code())
+			assert.Equal(t, tc.wantRetryAfter, retryAfterErr.RetryAfter())
+		})
+	}
+}
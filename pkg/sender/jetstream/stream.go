@@ -0,0 +1,70 @@
+package jetstream
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/nats-io/nats.go"
+)
+
+var (
+	// ErrStreamNotFound is returned by VerifyStream if the configured stream does not exist.
+	ErrStreamNotFound = errors.New("stream not found")
+	// ErrStreamSubjectsMismatch is returned by VerifyStream if the stream does not cover the published subjects.
+	ErrStreamSubjectsMismatch = errors.New("stream subjects do not cover the published subjects")
+)
+
+// VerifyStream looks up the configured stream and verifies that its subjects cover the subjects
+// the Sender publishes to.
+func (s *Sender) VerifyStream() error {
+	_, err := s.verifyStream()
+	return err
+}
+
+// verifyStream verifies the configured stream like VerifyStream and returns its info.
+func (s *Sender) verifyStream() (*nats.StreamInfo, error) {
+	jsCtx, err := s.jetStream()
+	if err != nil {
+		return nil, err
+	}
+	info, err := jsCtx.StreamInfo(s.envCfg.JSStreamName)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrStreamNotFound, s.envCfg.JSStreamName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up stream %s: %w", s.envCfg.JSStreamName, err)
+	}
+
+	for _, subject := range requiredSubjects(s.envCfg) {
+		covered := slices.ContainsFunc(info.Config.Subjects, func(filter string) bool {
+			return eventtype.Matches(filter, subject)
+		})
+		if !covered {
+			return nil, fmt.Errorf("%w: stream %s with subjects %v does not cover %s", ErrStreamSubjectsMismatch,
+				s.envCfg.JSStreamName, info.Config.Subjects, subject)
+		}
+	}
+	return info, nil
+}
+
+// StorageUsage returns the highest ratio of the bytes and messages stored in the configured stream to its limits.
+func (s *Sender) StorageUsage() (float64, error) {
+	info, err := s.verifyStream()
+	if err != nil {
+		return 0, err
+	}
+	s.recordStreamState(info)
+	return storageUsage(info), nil
+}
+
+// requiredSubjects returns the subjects which the stream has to cover, see getJsSubjectToPublish.
+func requiredSubjects(cfg *env.NATSConfig) []string {
+	subjects := []string{env.JetStreamSubjectPrefix + ".>"}
+	if cfg.EventTypePrefix != "" {
+		subjects = append(subjects, fmt.Sprintf("%s.%s.>", env.JetStreamSubjectPrefix, cfg.EventTypePrefix))
+	}
+	return subjects
+}
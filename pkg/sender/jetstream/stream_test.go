@@ -0,0 +1,102 @@
+package jetstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/options"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	epptestingutils "github.com/kyma-project/eventing-publisher-proxy/testing"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_VerifyStream(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenStreamName string
+		givenSubjects   []string
+		wantErr         error
+		wantStatusCode  int
+		wantSendErr     sender.PublishError
+	}{
+		{
+			name:            "should succeed if the stream covers the published subjects",
+			givenStreamName: epptestingutils.StreamName,
+			givenSubjects:   []string{env.JetStreamSubjectPrefix + ".>"},
+			wantErr:         nil,
+			wantStatusCode:  health.StatusCodeHealthy,
+		},
+		{
+			name:            "should fail if the stream does not exist",
+			givenStreamName: "other",
+			givenSubjects:   []string{env.JetStreamSubjectPrefix + ".>"},
+			wantErr:         ErrStreamNotFound,
+			wantStatusCode:  health.StatusCodeNotHealthy,
+			wantSendErr:     ErrStreamMismatch,
+		},
+		{
+			name:            "should fail if the stream does not cover the published subjects",
+			givenStreamName: epptestingutils.StreamName,
+			givenSubjects:   []string{env.JetStreamSubjectPrefix + ".other.>"},
+			wantErr:         ErrStreamSubjectsMismatch,
+			wantStatusCode:  health.StatusCodeNotHealthy,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			testEnv := setupTestEnvironment(t)
+			defer func() {
+				testEnv.Connection.Close()
+				testEnv.Server.Shutdown()
+			}()
+			sc := getStreamConfig(5000)
+			sc.Name = tc.givenStreamName
+			sc.Subjects = tc.givenSubjects
+			addStream(t, testEnv.Connection, sc)
+			s := NewSender(context.Background(), testEnv.Connection, testEnv.Config, &options.Options{}, nil,
+				testEnv.Logger)
+
+			// when
+			err := s.VerifyStream()
+			writer := httptest.NewRecorder()
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+
+			// when
+			if tc.wantSendErr != nil {
+				sendErr := s.Send(context.Background(), createCloudEvent(t))
+
+				// then
+				require.NotNil(t, sendErr)
+				assert.Equal(t, tc.wantSendErr.Code(), sendErr.Code())
+				assert.Equal(t, tc.wantSendErr.Message(), sendErr.Message())
+			}
+		})
+	}
+}
+
+func TestSender_eventToNATSMsg_ExpectedStream(t *testing.T) {
+	t.Parallel()
+
+	// given
+	s := &Sender{envCfg: &env.NATSConfig{JSStreamName: "kyma", EventTypePrefix: "prefix"}}
+
+	// when
+	msg, err := s.eventToNATSMsg(createCloudEvent(t))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "kyma", msg.Header.Get(natsgo.ExpectedStreamHdr))
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/legacy"
+)
+
+const (
+	// EventsEndpoint lists (GET) or clears (DELETE) the captured events.
+	EventsEndpoint = "/memory/events"
+	// EventEndpointPattern fetches a captured event by its id.
+	EventEndpointPattern = "/memory/events/{id}"
+	// WaitEndpoint blocks until an event matching the query was captured.
+	WaitEndpoint = "/memory/wait"
+
+	typeQueryParam    = "type"
+	sourceQueryParam  = "source"
+	timeoutQueryParam = "timeout"
+
+	defaultWaitTimeout = 10 * time.Second
+)
+
+// API serves the HTTP endpoints to inspect the events captured by the in-memory Sender.
+type API struct {
+	sender         *Sender
+	maxWaitTimeout time.Duration
+}
+
+// NewAPI returns a new API instance for the given Sender.
+func NewAPI(sender *Sender, maxWaitTimeout time.Duration) *API {
+	return &API{sender: sender, maxWaitTimeout: maxWaitTimeout}
+}
+
+// RegisterRoutes registers the inspection endpoints on the given router.
+func (a *API) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc(EventsEndpoint, a.listEvents).Methods(http.MethodGet)
+	router.HandleFunc(EventsEndpoint, a.clearEvents).Methods(http.MethodDelete)
+	router.HandleFunc(EventEndpointPattern, a.getEvent).Methods(http.MethodGet)
+	router.HandleFunc(WaitEndpoint, a.waitForEvent).Methods(http.MethodGet)
+}
+
+func (a *API) listEvents(w http.ResponseWriter, r *http.Request) {
+	respondWithBody(w, http.StatusOK, a.sender.Events(filterFromRequest(r)))
+}
+
+func (a *API) clearEvents(w http.ResponseWriter, _ *http.Request) {
+	a.sender.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *API) getEvent(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	captured, ok := a.sender.Get(id)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, fmt.Sprintf("event with id %q not found", id))
+		return
+	}
+	respondWithBody(w, http.StatusOK, captured)
+}
+
+// waitForEvent returns the oldest captured event matching the query, or waits for it until the timeout is reached.
+func (a *API) waitForEvent(w http.ResponseWriter, r *http.Request) {
+	timeout := defaultWaitTimeout
+	if value := r.URL.Query().Get(timeoutQueryParam); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("invalid timeout: %v", err))
+			return
+		}
+	}
+	if a.maxWaitTimeout > 0 && timeout > a.maxWaitTimeout {
+		timeout = a.maxWaitTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	captured, ok := a.sender.Wait(ctx, filterFromRequest(r))
+	if !ok {
+		respondWithError(w, http.StatusRequestTimeout, "no matching event captured before timeout")
+		return
+	}
+	respondWithBody(w, http.StatusOK, captured)
+}
+
+func filterFromRequest(r *http.Request) Filter {
+	query := r.URL.Query()
+	return Filter{Type: query.Get(typeQueryParam), Source: query.Get(sourceQueryParam)}
+}
+
+func respondWithBody(w http.ResponseWriter, httpCode int, body any) {
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(httpCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func respondWithError(w http.ResponseWriter, httpCode int, message string) {
+	respondWithBody(w, httpCode, legacy.HTTPErrorResponse{Code: httpCode, Error: message})
+}
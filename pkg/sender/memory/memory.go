@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	backend     = "memory"
+	handlerName = "memory-handler"
+
+	// URL is the pseudo URL of the in-memory backend used as the destination service in metrics.
+	URL = "memory://"
+)
+
+// compile time check.
+var (
+	_ sender.GenericSender = &Sender{}
+	_ health.Checker       = &Sender{}
+)
+
+// CapturedEvent is an event stored by the in-memory Sender.
+type CapturedEvent struct {
+	ReceivedAt time.Time   `json:"receivedAt"`
+	Event      event.Event `json:"event"`
+}
+
+// Filter selects captured events by their type and source. Empty fields match any value.
+type Filter struct {
+	Type   string
+	Source string
+}
+
+// Matches returns true if the given event matches the Filter.
+func (f Filter) Matches(e *event.Event) bool {
+	if f.Type != "" && f.Type != e.Type() {
+		return false
+	}
+	if f.Source != "" && f.Source != e.Source() {
+		return false
+	}
+	return true
+}
+
+// Sender is responsible for capturing events in a bounded in-memory ring buffer.
+// It never fails to send, once the buffer is full the oldest events are dropped.
+type Sender struct {
+	logger *logger.Logger
+
+	mutex  sync.Mutex
+	events []CapturedEvent
+	start  int
+	size   int
+	// notify is closed and replaced every time a new event is captured to wake up waiting requests.
+	notify chan struct{}
+}
+
+// NewSender returns a new Sender instance which keeps at most bufferSize events.
+func NewSender(bufferSize int, logger *logger.Logger) *Sender {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Sender{
+		logger: logger,
+		events: make([]CapturedEvent, bufferSize),
+		notify: make(chan struct{}),
+	}
+}
+
+func (s *Sender) URL() string {
+	return URL
+}
+
+// Send captures a copy of the event in the ring buffer.
+func (s *Sender) Send(_ context.Context, event *event.Event) sender.PublishError {
+	captured := CapturedEvent{ReceivedAt: time.Now(), Event: event.Clone()}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.size < len(s.events) {
+		s.events[(s.start+s.size)%len(s.events)] = captured
+		s.size++
+	} else {
+		s.events[s.start] = captured
+		s.start = (s.start + 1) % len(s.events)
+	}
+	close(s.notify)
+	s.notify = make(chan struct{})
+
+	s.namedLogger().Debugw("Captured event", "id", event.ID(), "type", event.Type(), "source", event.Source())
+	return nil
+}
+
+// Events returns the captured events matching the given Filter, from the oldest to the newest.
+func (s *Sender) Events(filter Filter) []CapturedEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.eventsLocked(filter)
+}
+
+// Get returns the newest captured event with the given id.
+func (s *Sender) Get(id string) (CapturedEvent, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := s.size - 1; i >= 0; i-- {
+		captured := s.events[(s.start+i)%len(s.events)]
+		if captured.Event.ID() == id {
+			return captured, true
+		}
+	}
+	return CapturedEvent{}, false
+}
+
+// Clear removes all captured events.
+func (s *Sender) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	clear(s.events)
+	s.start, s.size = 0, 0
+}
+
+// Wait blocks until an event matching the given Filter was captured and returns the oldest one,
+// or returns false if the context is done before.
+func (s *Sender) Wait(ctx context.Context, filter Filter) (CapturedEvent, bool) {
+	for {
+		s.mutex.Lock()
+		events := s.eventsLocked(filter)
+		notify := s.notify
+		s.mutex.Unlock()
+
+		if len(events) > 0 {
+			return events[0], true
+		}
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return CapturedEvent{}, false
+		}
+	}
+}
+
+func (s *Sender) eventsLocked(filter Filter) []CapturedEvent {
+	events := make([]CapturedEvent, 0, s.size)
+	for i := range s.size {
+		captured := s.events[(s.start+i)%len(s.events)]
+		if filter.Matches(&captured.Event) {
+			events = append(events, captured)
+		}
+	}
+	return events
+}
+
+// ReadinessCheck always reports 2XX, because the in-memory backend is available as long as the process is running.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName).With("backend", backend)
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		givenBufferSize int
+		givenEvents     int
+		wantIDs         []string
+	}{
+		{
+			name:            "should keep all events if the buffer is not full",
+			givenBufferSize: 3,
+			givenEvents:     2,
+			wantIDs:         []string{"id-0", "id-1"},
+		},
+		{
+			name:            "should drop the oldest events if the buffer is full",
+			givenBufferSize: 3,
+			givenEvents:     5,
+			wantIDs:         []string{"id-2", "id-3", "id-4"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			sender := NewSender(tc.givenBufferSize, newLogger(t))
+
+			// when
+			for i := range tc.givenEvents {
+				require.Nil(t, sender.Send(context.Background(), newEvent(t, fmt.Sprintf("id-%d", i), "type", "source")))
+			}
+
+			// then
+			gotIDs := make([]string, 0)
+			for _, captured := range sender.Events(Filter{}) {
+				gotIDs = append(gotIDs, captured.Event.ID())
+			}
+			assert.Equal(t, tc.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestSender_Events(t *testing.T) {
+	t.Parallel()
+
+	sender := NewSender(10, newLogger(t))
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "1", "order.created.v1", "app1")))
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "2", "order.created.v1", "app2")))
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "3", "order.updated.v1", "app1")))
+
+	testCases := []struct {
+		name        string
+		givenFilter Filter
+		wantIDs     []string
+	}{
+		{
+			name:        "should return all events for an empty filter",
+			givenFilter: Filter{},
+			wantIDs:     []string{"1", "2", "3"},
+		},
+		{
+			name:        "should filter by type",
+			givenFilter: Filter{Type: "order.created.v1"},
+			wantIDs:     []string{"1", "2"},
+		},
+		{
+			name:        "should filter by source",
+			givenFilter: Filter{Source: "app1"},
+			wantIDs:     []string{"1", "3"},
+		},
+		{
+			name:        "should filter by type and source",
+			givenFilter: Filter{Type: "order.created.v1", Source: "app2"},
+			wantIDs:     []string{"2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotIDs := make([]string, 0)
+			for _, captured := range sender.Events(tc.givenFilter) {
+				gotIDs = append(gotIDs, captured.Event.ID())
+			}
+			assert.Equal(t, tc.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestSender_Wait(t *testing.T) {
+	t.Parallel()
+
+	// given
+	sender := NewSender(10, newLogger(t))
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "1", "order.created.v1", "app1")))
+
+	// when
+	result := make(chan CapturedEvent)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		captured, _ := sender.Wait(ctx, Filter{Type: "order.updated.v1"})
+		result <- captured
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "2", "order.updated.v1", "app1")))
+
+	// then
+	assert.Equal(t, "2", (<-result).Event.ID())
+
+	// a timed out wait should return false
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok := sender.Wait(ctx, Filter{Type: "order.deleted.v1"})
+	assert.False(t, ok)
+}
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+
+	// given
+	sender := NewSender(10, newLogger(t))
+	router := mux.NewRouter()
+	NewAPI(sender, time.Second).RegisterRoutes(router)
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "1", "order.created.v1", "app1")))
+	require.Nil(t, sender.Send(context.Background(), newEvent(t, "2", "order.updated.v1", "app1")))
+
+	// list events
+	writer := serve(router, http.MethodGet, EventsEndpoint+"?type=order.updated.v1")
+	require.Equal(t, http.StatusOK, writer.Code)
+	var events []CapturedEvent
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "2", events[0].Event.ID())
+
+	// get event by id
+	writer = serve(router, http.MethodGet, "/memory/events/1")
+	require.Equal(t, http.StatusOK, writer.Code)
+	var captured CapturedEvent
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &captured))
+	assert.Equal(t, "order.created.v1", captured.Event.Type())
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/memory/events/3").Code)
+
+	// wait for event
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, WaitEndpoint+"?source=app1").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(router, http.MethodGet, WaitEndpoint+"?timeout=invalid").Code)
+	assert.Equal(t, http.StatusRequestTimeout, serve(router, http.MethodGet, WaitEndpoint+"?source=app2&timeout=10ms").Code)
+
+	// clear events
+	assert.Equal(t, http.StatusNoContent, serve(router, http.MethodDelete, EventsEndpoint).Code)
+	assert.Empty(t, sender.Events(Filter{}))
+}
+
+func serve(router *mux.Router, method, target string) *httptest.ResponseRecorder {
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, httptest.NewRequest(method, target, nil))
+	return writer
+}
+
+func newEvent(t *testing.T, id, eventType, source string) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID(id)
+	event.SetType(eventType)
+	event.SetSource(source)
+	require.NoError(t, event.SetData(ce.ApplicationJSON, map[string]string{"foo": "bar"}))
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
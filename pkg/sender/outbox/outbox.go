@@ -0,0 +1,274 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	handlerName = "outbox-handler"
+)
+
+// compile time check.
+var (
+	_ sender.GenericSender = &Sender{}
+	_ sender.Wrapper       = &Sender{}
+	_ health.Checker       = &Sender{}
+)
+
+var ErrOutboxFull = common.BackendPublishError{
+	HTTPCode: http.StatusInsufficientStorage,
+	Info:     "outbox is full",
+}
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	BackendReady bool `json:"backendReady"`
+	OutboxDepth  int  `json:"outboxDepth"`
+	OutboxFull   bool `json:"outboxFull"`
+}
+
+// Sender decorates a sender.GenericSender with a local outbox. If a send fails with one of the configured codes,
+// the event is stored in a write-ahead log on the disk and common.ErrAccepted is returned.
+// Stored events are replayed in order once the decorated sender is ready. While events are stored,
+// new events are appended to the outbox as well to keep the order.
+type Sender struct {
+	sender    sender.GenericSender
+	cfg       env.OutboxConfig
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+
+	mutex sync.Mutex
+	wal   *wal
+	now   func() time.Time
+}
+
+// NewSender returns a new Sender instance and recovers the events stored in the configured directory.
+func NewSender(s sender.GenericSender, cfg env.OutboxConfig, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) (*Sender, error) {
+	w, err := openWAL(cfg.OutboxDir)
+	if err != nil {
+		return nil, err
+	}
+	o := &Sender{
+		sender:    s,
+		cfg:       cfg,
+		collector: collector,
+		logger:    logger,
+		wal:       w,
+		now:       time.Now,
+	}
+	o.recordMetricsLocked()
+	return o, nil
+}
+
+// Start replays the stored events periodically until the given context is done.
+func (s *Sender) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.OutboxReplayInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.replay(ctx)
+			}
+		}
+	}()
+}
+
+// Close closes the write-ahead log.
+func (s *Sender) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.wal.close()
+}
+
+func (s *Sender) URL() string {
+	return s.sender.URL()
+}
+
+// Unwrap returns the wrapped sender.
+func (s *Sender) Unwrap() sender.GenericSender {
+	return s.sender
+}
+
+// Send dispatches the event using the decorated sender, or stores it in the outbox if the send failed
+// with one of the configured codes or if there are stored events already.
+func (s *Sender) Send(ctx context.Context, event *ceevent.Event) sender.PublishError {
+	if s.depth() == 0 {
+		err := s.sender.Send(ctx, event)
+		if err == nil || !slices.Contains(s.cfg.OutboxCodes, err.Code()) {
+			return err
+		}
+		s.namedLogger().Warnw("Failed to send event, storing it in the outbox", "id", event.ID(), "error", err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		e := common.ErrClientConversionFailed
+		e.Wrap(err)
+		return e
+	}
+	return s.store(data)
+}
+
+func (s *Sender) store(data []byte) sender.PublishError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.wal.pending()+int64(len(data)) > s.cfg.OutboxMaxSize {
+		s.namedLogger().Errorw("Failed to store event, the outbox is full", "size", s.wal.pending())
+		return ErrOutboxFull
+	}
+	if err := s.wal.append(data, s.now()); err != nil {
+		s.namedLogger().Errorw("Failed to store event in the outbox", "error", err)
+		e := common.ErrInsufficientStorage
+		e.Wrap(err)
+		return e
+	}
+	s.recordMetricsLocked()
+	return common.ErrAccepted
+}
+
+// replay sends the stored events in order until the outbox is empty or the decorated sender fails.
+// Events which fail with a code which is not configured for the outbox are dropped.
+func (s *Sender) replay(ctx context.Context) {
+	defer s.recordMetrics()
+	if s.depth() == 0 || !isReady(ctx, s.sender) {
+		return
+	}
+
+	for ctx.Err() == nil {
+		s.mutex.Lock()
+		if s.wal.len() == 0 {
+			s.mutex.Unlock()
+			return
+		}
+		r, err := s.wal.head()
+		s.mutex.Unlock()
+
+		event := ceevent.New()
+		if err == nil {
+			err = json.Unmarshal(r.Event, &event)
+		}
+		if err != nil {
+			s.namedLogger().Errorw("Dropped unreadable event from the outbox", "error", err)
+			s.commit()
+			continue
+		}
+
+		code := http.StatusNoContent
+		sendErr := s.send(ctx, &event)
+		if sendErr != nil {
+			code = sendErr.Code()
+		}
+		s.collector.RecordOutboxReplay(code)
+		if sendErr != nil && slices.Contains(s.cfg.OutboxCodes, code) {
+			s.namedLogger().Debugw("Failed to replay event, retrying later", "id", event.ID(), "error", sendErr)
+			return
+		}
+		if sendErr != nil {
+			s.namedLogger().Errorw("Dropped event from the outbox", "id", event.ID(), "error", sendErr)
+		}
+		s.commit()
+	}
+}
+
+func (s *Sender) send(ctx context.Context, event *ceevent.Event) sender.PublishError {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.OutboxReplayTimeout)
+	defer cancel()
+	return s.sender.Send(ctx, event)
+}
+
+func (s *Sender) commit() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.wal.commit(); err != nil {
+		s.namedLogger().Errorw("Failed to commit the outbox offset", "error", err)
+	}
+	s.recordMetricsLocked()
+}
+
+func (s *Sender) depth() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.wal.len()
+}
+
+func (s *Sender) recordMetrics() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.recordMetricsLocked()
+}
+
+func (s *Sender) recordMetricsLocked() {
+	s.collector.SetOutboxDepth(s.wal.len())
+	var age time.Duration
+	if oldest := s.wal.oldest(); !oldest.IsZero() {
+		age = s.now().Sub(oldest)
+	}
+	s.collector.SetOutboxOldestEntryAge(age)
+}
+
+// ReadinessCheck reports 2XX if the decorated sender is ready or the outbox can still store events,
+// otherwise reports 5XX. The response body contains the readiness of the decorated sender and the outbox state.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	resp := ReadinessResponse{BackendReady: isReady(r.Context(), s.sender)}
+	s.mutex.Lock()
+	resp.OutboxDepth = s.wal.len()
+	resp.OutboxFull = s.wal.pending() >= s.cfg.OutboxMaxSize
+	s.mutex.Unlock()
+
+	statusCode := health.StatusCodeHealthy
+	if !resp.BackendReady && resp.OutboxFull {
+		s.namedLogger().Errorw("Readiness check failed: backend is not ready and the outbox is full")
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessCheck delegates to the decorated sender if it implements health.Checker, otherwise reports 2XX.
+func (s *Sender) LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	if checker, ok := s.sender.(health.Checker); ok {
+		checker.LivenessCheck(w, r)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
+
+// isReady returns true if the given sender is ready. Senders which do not implement health.Checker are always ready.
+func isReady(ctx context.Context, s sender.GenericSender) bool {
+	checker, ok := s.(health.Checker)
+	if !ok {
+		return true
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, health.ReadinessURI, nil)
+	if err != nil {
+		return false
+	}
+	return health.IsReady(checker, r)
+}
@@ -0,0 +1,379 @@
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/metricstest"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_SendAndReplay(t *testing.T) {
+	t.Parallel()
+
+	// given
+	now := time.Now()
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := newSender(t, stub, newConfig(t), collector)
+	s.now = func() time.Time { return now }
+
+	// when the backend fails
+	err := s.Send(context.Background(), newEvent(t, "1"))
+
+	// then the event is stored
+	assert.Equal(t, common.ErrAccepted, err)
+	assert.Equal(t, 1, s.depth())
+
+	// when events are stored
+	stub.err = nil
+	err = s.Send(context.Background(), newEvent(t, "2"))
+
+	// then new events are stored as well to keep the order
+	assert.Equal(t, common.ErrAccepted, err)
+	assert.Equal(t, []string{"1"}, stub.sent)
+	now = now.Add(time.Minute)
+	s.recordMetrics()
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_outbox_depth The number of events in the outbox waiting to be replayed
+		# TYPE eventing_epp_outbox_depth gauge
+		eventing_epp_outbox_depth 2
+	`, metrics.OutboxDepthKey)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_outbox_oldest_entry_age_seconds The age of the oldest event in the outbox in seconds
+		# TYPE eventing_epp_outbox_oldest_entry_age_seconds gauge
+		eventing_epp_outbox_oldest_entry_age_seconds 60
+	`, metrics.OutboxOldestEntryAgeKey)
+
+	// when the backend is not ready
+	s.replay(context.Background())
+
+	// then no events are replayed
+	assert.Equal(t, 2, s.depth())
+
+	// when the backend is ready
+	stub.ready = true
+	s.replay(context.Background())
+
+	// then the events are replayed in order
+	assert.Equal(t, []string{"1", "1", "2"}, stub.sent)
+	assert.Equal(t, 0, s.depth())
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_outbox_replayed_total The total number of events replayed from the outbox
+		# TYPE eventing_epp_outbox_replayed_total counter
+		eventing_epp_outbox_replayed_total{code="204"} 2
+	`, metrics.OutboxReplayedKey)
+
+	// when the outbox is empty
+	err = s.Send(context.Background(), newEvent(t, "3"))
+
+	// then events are sent directly
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "1", "2", "3"}, stub.sent)
+}
+
+func TestSender_Send_NotStored(t *testing.T) {
+	t.Parallel()
+
+	// given
+	stub := &senderStub{url: "stub", err: common.ErrClientConversionFailed}
+	s := newSender(t, stub, newConfig(t), metrics.NewCollector(latency.NewBucketsProvider()))
+
+	// when
+	err := s.Send(context.Background(), newEvent(t, "1"))
+
+	// then
+	assert.Equal(t, common.ErrClientConversionFailed, err)
+	assert.Equal(t, 0, s.depth())
+}
+
+func TestSender_Send_Full(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cfg := newConfig(t)
+	cfg.OutboxMaxSize = 300
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	s := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	require.Equal(t, common.ErrAccepted, s.Send(context.Background(), newEvent(t, "1")))
+
+	// when
+	err := s.Send(context.Background(), newEvent(t, "2"))
+
+	// then
+	assert.Equal(t, ErrOutboxFull, err)
+	assert.Equal(t, http.StatusInsufficientStorage, err.Code())
+	assert.Equal(t, 1, s.depth())
+}
+
+func TestSender_Send_WhileReplaying(t *testing.T) {
+	t.Parallel()
+
+	// given
+	const count = 1000
+	cfg := newConfig(t)
+	cfg.OutboxMaxSize = 1000
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	s := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	require.Equal(t, common.ErrAccepted, s.Send(context.Background(), newEvent(t, "0")))
+
+	// when events are published while the stored ones are replayed
+	var errs []sender.PublishError
+	stub.ready, stub.err, stub.sent = true, nil, nil
+	stub.onSend = func() {
+		if id := len(stub.sent); id < count {
+			errs = append(errs, s.Send(context.Background(), newEvent(t, strconv.Itoa(id))))
+		}
+	}
+	s.replay(context.Background())
+
+	// then the outbox is limited by the events which were not replayed yet only
+	require.Len(t, errs, count-1)
+	for _, err := range errs {
+		require.Equal(t, common.ErrAccepted, err)
+	}
+	require.Len(t, stub.sent, count)
+	assert.Equal(t, strconv.Itoa(count-1), stub.sent[count-1])
+	assert.Equal(t, 0, s.depth())
+
+	// and the replayed events are removed from the log
+	info, err := os.Stat(filepath.Join(cfg.OutboxDir, logFileName))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+}
+
+func TestSender_replay_Compacts(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cfg := newConfig(t)
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	s := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	var ids []string
+	for s.wal.size < 3*compactMinSize {
+		id := strconv.Itoa(len(ids))
+		require.Equal(t, common.ErrAccepted, s.Send(context.Background(), newEvent(t, id)))
+		ids = append(ids, id)
+	}
+	size := s.wal.size
+
+	// when more than half of the events are replayed
+	stub.ready, stub.err, stub.sent = true, nil, nil
+	stub.onSend = func() {
+		if len(stub.sent) == len(ids)*2/3 {
+			stub.err = common.ErrClientNoConnection
+		}
+	}
+	s.replay(context.Background())
+
+	// then the log is compacted
+	info, err := os.Stat(filepath.Join(cfg.OutboxDir, logFileName))
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), size/2)
+	require.NoError(t, s.Close())
+
+	// when the outbox is recovered
+	stub.err, stub.sent, stub.onSend = nil, nil, nil
+	recovered := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	recovered.replay(context.Background())
+
+	// then the events which were not replayed yet are replayed in order
+	assert.Equal(t, ids[len(ids)*2/3-1:], stub.sent)
+	assert.Equal(t, 0, recovered.depth())
+}
+
+func TestSender_replay_DropsRejectedEvents(t *testing.T) {
+	t.Parallel()
+
+	// given
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	collector := metrics.NewCollector(latency.NewBucketsProvider())
+	s := newSender(t, stub, newConfig(t), collector)
+	require.Equal(t, common.ErrAccepted, s.Send(context.Background(), newEvent(t, "1")))
+
+	// when
+	stub.ready = true
+	stub.err = common.ErrClientConversionFailed
+	s.replay(context.Background())
+
+	// then
+	assert.Equal(t, 0, s.depth())
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, collector, `
+		# HELP eventing_epp_outbox_replayed_total The total number of events replayed from the outbox
+		# TYPE eventing_epp_outbox_replayed_total counter
+		eventing_epp_outbox_replayed_total{code="400"} 1
+	`, metrics.OutboxReplayedKey)
+}
+
+func TestSender_Recover(t *testing.T) {
+	t.Parallel()
+
+	// given
+	cfg := newConfig(t)
+	stub := &senderStub{url: "stub", err: common.ErrClientNoConnection}
+	s := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	for _, id := range []string{"1", "2", "3"} {
+		require.Equal(t, common.ErrAccepted, s.Send(context.Background(), newEvent(t, id)))
+	}
+	stub.ready, stub.err, stub.sent = true, nil, nil
+	s.mutex.Lock()
+	r, err := s.wal.head()
+	require.NoError(t, err)
+	require.NoError(t, s.wal.commit())
+	s.mutex.Unlock()
+	require.Equal(t, "1", newEventFromRecord(t, r).ID())
+	require.NoError(t, s.Close())
+
+	// a partially written record at the end of the log
+	file, err := os.OpenFile(filepath.Join(cfg.OutboxDir, logFileName), os.O_APPEND|os.O_WRONLY, filePerm)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"storedAt":"2024-`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// when
+	recovered := newSender(t, stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+	recovered.replay(context.Background())
+
+	// then
+	assert.Equal(t, []string{"2", "3"}, stub.sent)
+	assert.Equal(t, 0, recovered.depth())
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenReady     bool
+		givenFull      bool
+		wantStatusCode int
+	}{
+		{
+			name:           "should be ready if the backend is ready",
+			givenReady:     true,
+			givenFull:      true,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "should be ready if the backend is not ready but the outbox is not full",
+			givenReady:     false,
+			givenFull:      false,
+			wantStatusCode: health.StatusCodeHealthy,
+		},
+		{
+			name:           "should not be ready if the backend is not ready and the outbox is full",
+			givenReady:     false,
+			givenFull:      true,
+			wantStatusCode: health.StatusCodeNotHealthy,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			cfg := newConfig(t)
+			if tc.givenFull {
+				cfg.OutboxMaxSize = 0
+			}
+			s := newSender(t, &senderStub{ready: tc.givenReady}, cfg, metrics.NewCollector(latency.NewBucketsProvider()))
+			writer := httptest.NewRecorder()
+
+			// when
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+		})
+	}
+}
+
+type senderStub struct {
+	err    sender.PublishError
+	url    string
+	ready  bool
+	sent   []string
+	onSend func()
+}
+
+func (s *senderStub) Send(_ context.Context, event *ceevent.Event) sender.PublishError {
+	s.sent = append(s.sent, event.ID())
+	if s.onSend != nil {
+		s.onSend()
+	}
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newSender(t *testing.T, s sender.GenericSender, cfg env.OutboxConfig, collector *metrics.Collector) *Sender {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	o, err := NewSender(s, cfg, collector, l)
+	require.NoError(t, err)
+	return o
+}
+
+func newConfig(t *testing.T) env.OutboxConfig {
+	t.Helper()
+	return env.OutboxConfig{
+		OutboxEnabled:        true,
+		OutboxDir:            t.TempDir(),
+		OutboxMaxSize:        1024 * 1024,
+		OutboxReplayInterval: time.Second,
+		OutboxReplayTimeout:  time.Second,
+		OutboxCodes:          []int{http.StatusBadGateway},
+	}
+}
+
+func newEvent(t *testing.T, id string) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID(id)
+	event.SetType("order.created.v1")
+	event.SetSource("source")
+	require.NoError(t, event.SetData(ce.ApplicationJSON, map[string]string{"foo": "bar"}))
+	return &event
+}
+
+func newEventFromRecord(t *testing.T, r *record) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	require.NoError(t, event.UnmarshalJSON(r.Event))
+	return &event
+}
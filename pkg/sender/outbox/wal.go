@@ -0,0 +1,258 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	logFileName    = "outbox.log"
+	offsetFileName = "outbox.offset"
+
+	filePerm = 0o600
+	dirPerm  = 0o700
+
+	// compactMinSize is the minimum size of the replayed records before the log is compacted.
+	compactMinSize = 64 * 1024
+)
+
+// record is a single line of the write-ahead log.
+type record struct {
+	StoredAt time.Time       `json:"storedAt"`
+	Event    json.RawMessage `json:"event"`
+}
+
+// entry locates a record in the write-ahead log.
+type entry struct {
+	offset   int64
+	length   int64
+	storedAt time.Time
+}
+
+// wal is an append-only log of records with a committed offset. Records before the offset were replayed.
+// It is not safe for concurrent use.
+type wal struct {
+	dir     string
+	file    *os.File
+	size    int64
+	offset  int64
+	entries []entry
+}
+
+// openWAL opens the write-ahead log in the given directory and recovers the records which were not replayed yet.
+// A partially written record at the end of the log is discarded.
+func openWAL(dir string) (*wal, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return nil, err
+	}
+	w := &wal{dir: dir, file: file}
+	if err := w.recover(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *wal) recover() error {
+	offset, err := w.readOffset()
+	if err != nil {
+		return err
+	}
+	info, err := w.file.Stat()
+	if err != nil {
+		return err
+	}
+	if offset > info.Size() {
+		// the offset does not belong to this log, replay it completely as events are delivered at least once
+		offset = 0
+	}
+	w.offset = offset
+
+	if _, err := w.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(w.file)
+	position := offset
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var r record
+		if err := json.Unmarshal(line, &r); err != nil {
+			break
+		}
+		w.entries = append(w.entries, entry{offset: position, length: int64(len(line)), storedAt: r.StoredAt})
+		position += int64(len(line))
+	}
+
+	// discard a partially written record
+	if err := w.file.Truncate(position); err != nil {
+		return err
+	}
+	w.size = position
+	_, err = w.file.Seek(position, io.SeekStart)
+	return err
+}
+
+// append writes the given event to the log and syncs it to the disk.
+func (w *wal) append(event []byte, storedAt time.Time) error {
+	line, err := json.Marshal(record{StoredAt: storedAt, Event: event})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := w.file.Write(line); err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	w.entries = append(w.entries, entry{offset: w.size, length: int64(len(line)), storedAt: storedAt})
+	w.size += int64(len(line))
+	return nil
+}
+
+// head returns the oldest record which was not replayed yet.
+func (w *wal) head() (*record, error) {
+	e := w.entries[0]
+	line := make([]byte, e.length)
+	if _, err := w.file.ReadAt(line, e.offset); err != nil {
+		return nil, err
+	}
+	var r record
+	if err := json.Unmarshal(line, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// commit marks the oldest record as replayed. The log is truncated once all records were replayed,
+// and compacted once the replayed records take up more space than the records which were not replayed yet.
+func (w *wal) commit() error {
+	e := w.entries[0]
+	w.entries = w.entries[1:]
+	if len(w.entries) > 0 {
+		w.offset = e.offset + e.length
+		if err := w.writeOffset(); err != nil {
+			return err
+		}
+		if w.offset >= compactMinSize && w.offset >= w.pending() {
+			return w.compact()
+		}
+		return nil
+	}
+
+	w.offset, w.size = 0, 0
+	if err := w.writeOffset(); err != nil {
+		return err
+	}
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := w.file.Seek(0, io.SeekStart)
+	return err
+}
+
+// compact replaces the log by a new one which contains only the records which were not replayed yet.
+// The offset is reset before the log is replaced, so a crash in between replays the old log completely,
+// as events are delivered at least once.
+func (w *wal) compact() error {
+	path := filepath.Join(w.dir, logFileName)
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, filePerm)
+	if err != nil {
+		return err
+	}
+	if err := w.copyPending(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	offset := w.offset
+	w.offset = 0
+	if err := w.writeOffset(); err != nil {
+		w.offset = offset
+		_ = file.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		w.offset = offset
+		_ = file.Close()
+		_ = os.Remove(tmp)
+		return errors.Join(err, w.writeOffset())
+	}
+
+	_ = w.file.Close()
+	w.file = file
+	w.size -= offset
+	for i := range w.entries {
+		w.entries[i].offset -= offset
+	}
+	return nil
+}
+
+// copyPending writes the records which were not replayed yet to the given file and syncs it to the disk.
+func (w *wal) copyPending(file *os.File) error {
+	if _, err := io.Copy(file, io.NewSectionReader(w.file, w.offset, w.pending())); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// pending returns the size of the records which were not replayed yet.
+func (w *wal) pending() int64 {
+	return w.size - w.offset
+}
+
+func (w *wal) len() int {
+	return len(w.entries)
+}
+
+func (w *wal) oldest() time.Time {
+	if len(w.entries) == 0 {
+		return time.Time{}
+	}
+	return w.entries[0].storedAt
+}
+
+func (w *wal) close() error {
+	return w.file.Close()
+}
+
+func (w *wal) readOffset() (int64, error) {
+	data, err := os.ReadFile(filepath.Join(w.dir, offsetFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// writeOffset replaces the offset file atomically.
+func (w *wal) writeOffset() error {
+	path := filepath.Join(w.dir, offsetFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(w.offset, 10)), filePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,156 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const (
+	handlerName = "retry-handler"
+)
+
+// compile time check.
+var (
+	_ sender.GenericSender = &Sender{}
+	_ sender.Wrapper       = &Sender{}
+	_ health.Checker       = &Sender{}
+)
+
+// Sender decorates a sender.GenericSender and retries failed sends with an exponential backoff and jitter.
+// If the error of a send is a sender.RetryAfterError, its hint is used as the minimum backoff.
+// A send is retried if the code of its sender.PublishError is one of the configured retryable codes,
+// as long as neither the maximum attempts nor the retry budget nor the deadline of the request are exceeded.
+type Sender struct {
+	sender    sender.GenericSender
+	cfg       env.RetryConfig
+	collector metrics.PublishingMetricsCollector
+	logger    *logger.Logger
+	sleep     func(ctx context.Context, d time.Duration) bool
+}
+
+// NewSender returns a new Sender instance which retries the sends of the given sender.
+func NewSender(s sender.GenericSender, cfg env.RetryConfig, collector metrics.PublishingMetricsCollector,
+	logger *logger.Logger,
+) *Sender {
+	return &Sender{sender: s, cfg: cfg, collector: collector, logger: logger, sleep: sleep}
+}
+
+func (s *Sender) URL() string {
+	return s.sender.URL()
+}
+
+// Unwrap returns the wrapped sender.
+func (s *Sender) Unwrap() sender.GenericSender {
+	return s.sender
+}
+
+// Send dispatches the event using the decorated sender and retries retryable errors.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	deadline, hasDeadline := ctx.Deadline()
+	if s.cfg.RetryBudget > 0 {
+		if budget := time.Now().Add(s.cfg.RetryBudget); !hasDeadline || budget.Before(deadline) {
+			deadline, hasDeadline = budget, true
+		}
+	}
+
+	backoff := s.cfg.RetryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := s.sender.Send(ctx, event)
+		duration := time.Since(start)
+
+		code := http.StatusNoContent
+		if err != nil {
+			code = err.Code()
+		}
+		s.collector.RecordBackendAttemptLatency(duration, code, s.sender.URL(), attempt)
+		s.namedLogger().Debugw("Sent event to backend", "id", event.ID(), "attempt", attempt, "code", code,
+			"duration", duration)
+
+		if err == nil || !s.isRetryable(err) || attempt >= s.cfg.RetryMaxAttempts {
+			return err
+		}
+
+		wait := jitter(backoff)
+		var retryAfterErr sender.RetryAfterError
+		if errors.As(err, &retryAfterErr) && retryAfterErr.RetryAfter() > wait {
+			wait = retryAfterErr.RetryAfter()
+		}
+		if hasDeadline && time.Now().Add(wait).After(deadline) {
+			s.namedLogger().Debugw("Retry budget exceeded", "id", event.ID(), "attempt", attempt)
+			return err
+		}
+		if !s.sleep(ctx, wait) {
+			return err
+		}
+		backoff = s.nextBackoff(backoff)
+	}
+}
+
+func (s *Sender) isRetryable(err sender.PublishError) bool {
+	return slices.Contains(s.cfg.RetryableCodes, err.Code())
+}
+
+func (s *Sender) nextBackoff(backoff time.Duration) time.Duration {
+	next := time.Duration(float64(backoff) * s.cfg.RetryBackoffMultiplier)
+	if s.cfg.RetryMaxBackoff > 0 && next > s.cfg.RetryMaxBackoff {
+		return s.cfg.RetryMaxBackoff
+	}
+	return next
+}
+
+// ReadinessCheck delegates to the decorated sender if it implements health.Checker, otherwise reports 2XX.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	if checker, ok := s.sender.(health.Checker); ok {
+		checker.ReadinessCheck(w, r)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+// LivenessCheck delegates to the decorated sender if it implements health.Checker, otherwise reports 2XX.
+func (s *Sender) LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	if checker, ok := s.sender.(health.Checker); ok {
+		checker.LivenessCheck(w, r)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
+
+// jitter returns a random duration in [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2                //nolint:mnd // half of the backoff is fixed, the other half is random
+	return half + rand.N(d-half) //nolint:gosec // no need for a cryptographically secure random number
+}
+
+// sleep waits for the given duration and returns false if the context is done before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,191 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/env"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		givenErrs    []sender.PublishError
+		givenBudget  time.Duration
+		wantErr      sender.PublishError
+		wantAttempts int
+		wantBackoffs []time.Duration
+	}{
+		{
+			name:         "should not retry if the first attempt succeeds",
+			givenErrs:    []sender.PublishError{nil},
+			wantErr:      nil,
+			wantAttempts: 1,
+		},
+		{
+			name:         "should retry retryable errors until the send succeeds",
+			givenErrs:    []sender.PublishError{common.ErrClientNoConnection, common.ErrClientNoConnection, nil},
+			wantErr:      nil,
+			wantAttempts: 3,
+			wantBackoffs: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "should not retry errors which are not retryable",
+			givenErrs:    []sender.PublishError{common.ErrClientConversionFailed},
+			wantErr:      common.ErrClientConversionFailed,
+			wantAttempts: 1,
+		},
+		{
+			name: "should stop after the maximum attempts",
+			givenErrs: []sender.PublishError{
+				common.ErrClientNoConnection, common.ErrClientNoConnection, common.ErrClientNoConnection,
+				common.ErrClientNoConnection, nil,
+			},
+			wantErr:      common.ErrClientNoConnection,
+			wantAttempts: 4,
+			wantBackoffs: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond},
+		},
+		{
+			name:         "should stop if the retry budget is exceeded",
+			givenErrs:    []sender.PublishError{common.ErrClientNoConnection, nil},
+			givenBudget:  time.Millisecond,
+			wantErr:      common.ErrClientNoConnection,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			stub := &senderStub{url: "stub", errs: tc.givenErrs}
+			cfg := env.RetryConfig{
+				RetryMaxAttempts:       4,
+				RetryInitialBackoff:    100 * time.Millisecond,
+				RetryMaxBackoff:        250 * time.Millisecond,
+				RetryBackoffMultiplier: 2,
+				RetryBudget:            tc.givenBudget,
+				RetryableCodes:         []int{http.StatusBadGateway},
+			}
+			s := NewSender(stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+			var gotBackoffs []time.Duration
+			s.sleep = func(_ context.Context, d time.Duration) bool {
+				gotBackoffs = append(gotBackoffs, d)
+				return true
+			}
+
+			// when
+			err := s.Send(context.Background(), newEvent(t))
+
+			// then
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantAttempts, stub.sent)
+			require.Len(t, gotBackoffs, len(tc.wantBackoffs))
+			for i, want := range tc.wantBackoffs {
+				assert.GreaterOrEqual(t, gotBackoffs[i], want/2)
+				assert.Less(t, gotBackoffs[i], want)
+			}
+		})
+	}
+}
+
+func TestSender_Send_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	// given
+	stub := &senderStub{errs: []sender.PublishError{common.ErrClientNoConnection, nil}}
+	cfg := env.RetryConfig{
+		RetryMaxAttempts:       3,
+		RetryInitialBackoff:    time.Minute,
+		RetryBackoffMultiplier: 2,
+		RetryableCodes:         []int{http.StatusBadGateway},
+	}
+	s := NewSender(stub, cfg, metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	err := s.Send(ctx, newEvent(t))
+
+	// then
+	assert.Equal(t, common.ErrClientNoConnection, err)
+	assert.Equal(t, 1, stub.sent)
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	// given
+	s := NewSender(&senderStub{ready: false}, env.RetryConfig{}, metrics.NewCollector(latency.NewBucketsProvider()),
+		newLogger(t))
+	writer := httptest.NewRecorder()
+
+	// when
+	s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+	// then
+	assert.Equal(t, health.StatusCodeNotHealthy, writer.Code)
+}
+
+type senderStub struct {
+	errs  []sender.PublishError
+	url   string
+	ready bool
+	sent  int
+}
+
+func (s *senderStub) Send(_ context.Context, _ *ceevent.Event) sender.PublishError {
+	err := s.errs[s.sent]
+	s.sent++
+	return err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newEvent(t *testing.T) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType("order.created.v1")
+	event.SetSource("source")
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
@@ -0,0 +1,163 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/internal"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/cloudevents/eventtype"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+const handlerName = "routing-handler"
+
+// compile time check.
+var (
+	_ sender.GenericSender   = &Sender{}
+	_ sender.LatencyRecorder = &Sender{}
+	_ health.Checker         = &Sender{}
+)
+
+// Target is a named backend the Sender routes events to.
+type Target struct {
+	Name   string
+	Sender sender.GenericSender
+}
+
+// ReadinessResponse is the body written by the readiness check of the Sender.
+type ReadinessResponse struct {
+	Ready   bool           `json:"ready"`
+	Targets []TargetStatus `json:"targets"`
+}
+
+// TargetStatus is the readiness of a single target.
+type TargetStatus struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+// Sender publishes every event to exactly one target picked by the first matching route.
+// The first target is the default one, it receives all events without a matching route.
+type Sender struct {
+	targets    []Target
+	routes     []eventmesh.Route
+	typePrefix string
+	collector  metrics.PublishingMetricsCollector
+	logger     *logger.Logger
+}
+
+// NewSender returns a new Sender instance for the given targets and routes. The type prefix is used to
+// determine the application of an event from its type, because its source is the EventMesh namespace.
+// It panics if no target is given.
+func NewSender(targets []Target, routes []eventmesh.Route, typePrefix string,
+	collector metrics.PublishingMetricsCollector, logger *logger.Logger,
+) *Sender {
+	if len(targets) == 0 {
+		panic("routing sender requires at least one target")
+	}
+	return &Sender{targets: targets, routes: routes, typePrefix: typePrefix, collector: collector, logger: logger}
+}
+
+// RecordsLatency returns true, since the backend latency is recorded per target.
+func (s *Sender) RecordsLatency() bool {
+	return true
+}
+
+// URL returns the comma separated URLs of all targets.
+func (s *Sender) URL() string {
+	urls := make([]string, 0, len(s.targets))
+	for _, t := range s.targets {
+		urls = append(urls, t.Sender.URL())
+	}
+	return strings.Join(urls, ",")
+}
+
+// Send dispatches the event to the target of the first matching route and records the latency labeled
+// with the name of the target.
+func (s *Sender) Send(ctx context.Context, event *event.Event) sender.PublishError {
+	target := s.route(event)
+	start := time.Now()
+	err := target.Sender.Send(ctx, event)
+	code := http.StatusNoContent
+	if err != nil {
+		code = err.Code()
+		s.namedLogger().Errorw("Failed to send event to target", "target", target.Name, "id", event.ID(),
+			"error", err)
+	}
+	s.collector.RecordBackendLatency(time.Since(start), code, target.Sender.URL())
+	return err
+}
+
+// route returns the target of the first route matching the application or the type of the event,
+// or the default target if none matches.
+func (s *Sender) route(event *event.Event) Target {
+	application := s.application(event.Type())
+	for _, r := range s.routes {
+		matches := slices.Contains(r.Sources, application) || slices.Contains(r.Sources, event.Source()) ||
+			slices.ContainsFunc(r.Types, func(pattern string) bool {
+				return eventtype.Matches(pattern, event.Type())
+			})
+		if !matches {
+			continue
+		}
+		for _, t := range s.targets {
+			if t.Name == r.Target {
+				return t
+			}
+		}
+	}
+	return s.targets[0]
+}
+
+// application returns the segment of the event type which follows the type prefix.
+func (s *Sender) application(eventType string) string {
+	if s.typePrefix != "" {
+		var ok bool
+		if eventType, ok = strings.CutPrefix(eventType, s.typePrefix+"."); !ok {
+			return ""
+		}
+	}
+	application, _, _ := strings.Cut(eventType, ".")
+	return application
+}
+
+// ReadinessCheck reports 2XX if all targets are ready, otherwise reports 5XX, and lists the readiness
+// of the targets in a JSON body. Targets which do not implement health.Checker are considered ready.
+func (s *Sender) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	resp := ReadinessResponse{Ready: true, Targets: make([]TargetStatus, 0, len(s.targets))}
+	for _, t := range s.targets {
+		status := TargetStatus{Name: t.Name, Ready: true}
+		if checker, ok := t.Sender.(health.Checker); ok && !health.IsReady(checker, r) {
+			s.namedLogger().Errorw("Readiness check failed for target", "target", t.Name)
+			status.Ready, resp.Ready = false, false
+		}
+		resp.Targets = append(resp.Targets, status)
+	}
+
+	statusCode := health.StatusCodeHealthy
+	if !resp.Ready {
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set(internal.HeaderContentType, internal.ContentTypeApplicationJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *Sender) namedLogger() *zap.SugaredLogger {
+	return s.logger.WithContext().Named(handlerName)
+}
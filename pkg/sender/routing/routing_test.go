@@ -0,0 +1,252 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	ceevent "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/eventmesh"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/metrics/latency"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/eventing-publisher-proxy/pkg/sender/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/eventing-manager/pkg/logger"
+)
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	routes := []eventmesh.Route{
+		{Target: "eu", Sources: []string{"commerce"}},
+		{Target: "us", Types: []string{"prefix.*.order.>"}},
+		{Target: "eu", Types: []string{"prefix.marketing.>"}},
+	}
+
+	testCases := []struct {
+		name       string
+		givenType  string
+		givenErr   sender.PublishError
+		wantTarget string
+		wantCode   int
+	}{
+		{
+			name:       "route by application",
+			givenType:  "prefix.commerce.order.created.v1",
+			wantTarget: "eu",
+			wantCode:   http.StatusNoContent,
+		},
+		{
+			name:       "route by type pattern",
+			givenType:  "prefix.erp.order.created.v1",
+			wantTarget: "us",
+			wantCode:   http.StatusNoContent,
+		},
+		{
+			name:       "default target without matching route",
+			givenType:  "prefix.erp.invoice.created.v1",
+			wantTarget: eventmesh.DefaultTarget,
+			wantCode:   http.StatusNoContent,
+		},
+		{
+			name:       "error of the target",
+			givenType:  "prefix.marketing.campaign.started.v1",
+			givenErr:   common.ErrInsufficientStorage,
+			wantTarget: "eu",
+			wantCode:   http.StatusInsufficientStorage,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			stubs := map[string]*senderStub{
+				eventmesh.DefaultTarget: {ready: true, url: "https://default.example.com"},
+				"eu":                    {ready: true, url: "https://eu.example.com"},
+				"us":                    {ready: true, url: "https://us.example.com"},
+			}
+			stubs[tc.wantTarget].err = tc.givenErr
+			collector := &collectorStub{PublishingMetricsCollector: metrics.NewCollector(latency.NewBucketsProvider())}
+			s := NewSender([]Target{
+				{Name: eventmesh.DefaultTarget, Sender: stubs[eventmesh.DefaultTarget]},
+				{Name: "eu", Sender: stubs["eu"]},
+				{Name: "us", Sender: stubs["us"]},
+			}, routes, "prefix", collector, newLogger(t))
+
+			// when
+			err := s.Send(context.Background(), newEvent(t, tc.givenType))
+
+			// then
+			for name, stub := range stubs {
+				assert.Equal(t, name == tc.wantTarget, stub.received != nil, name)
+			}
+			assert.Equal(t, tc.givenErr, err)
+			assert.Equal(t, []string{fmt.Sprintf("%d %s", tc.wantCode, stubs[tc.wantTarget].url)}, collector.latencies)
+			assert.True(t, sender.RecordsLatency(s), "the latency must not be recorded again by the handler")
+		})
+	}
+}
+
+func TestSender_application(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		givenPrefix     string
+		givenType       string
+		wantApplication string
+	}{
+		{
+			name:            "with prefix",
+			givenPrefix:     "sap.kyma.custom",
+			givenType:       "sap.kyma.custom.commerce.order.created.v1",
+			wantApplication: "commerce",
+		},
+		{
+			name:            "without prefix",
+			givenType:       "commerce.order.created.v1",
+			wantApplication: "commerce",
+		},
+		{
+			name:            "type without prefix",
+			givenPrefix:     "sap.kyma.custom",
+			givenType:       "commerce.order.created.v1",
+			wantApplication: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := &Sender{typePrefix: tc.givenPrefix}
+
+			// when
+			application := s.application(tc.givenType)
+
+			// then
+			assert.Equal(t, tc.wantApplication, application)
+		})
+	}
+}
+
+func TestSender_ReadinessCheck(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		givenDefault   bool
+		givenOther     bool
+		wantStatusCode int
+		wantResponse   ReadinessResponse
+	}{
+		{
+			name:           "all targets are ready",
+			givenDefault:   true,
+			givenOther:     true,
+			wantStatusCode: health.StatusCodeHealthy,
+			wantResponse: ReadinessResponse{Ready: true, Targets: []TargetStatus{
+				{Name: eventmesh.DefaultTarget, Ready: true}, {Name: "eu", Ready: true},
+			}},
+		},
+		{
+			name:           "one target is not ready",
+			givenDefault:   true,
+			givenOther:     false,
+			wantStatusCode: health.StatusCodeNotHealthy,
+			wantResponse: ReadinessResponse{Ready: false, Targets: []TargetStatus{
+				{Name: eventmesh.DefaultTarget, Ready: true}, {Name: "eu", Ready: false},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			s := NewSender([]Target{
+				{Name: eventmesh.DefaultTarget, Sender: &senderStub{ready: tc.givenDefault}},
+				{Name: "eu", Sender: &senderStub{ready: tc.givenOther}},
+			}, nil, "", metrics.NewCollector(latency.NewBucketsProvider()), newLogger(t))
+			writer := httptest.NewRecorder()
+
+			// when
+			s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, writer.Code)
+			resp := ReadinessResponse{}
+			require.NoError(t, json.NewDecoder(writer.Body).Decode(&resp))
+			assert.Equal(t, tc.wantResponse, resp)
+		})
+	}
+}
+
+type collectorStub struct {
+	metrics.PublishingMetricsCollector
+	latencies []string
+}
+
+func (c *collectorStub) RecordBackendLatency(_ time.Duration, statusCode int, destSvc string) {
+	c.latencies = append(c.latencies, fmt.Sprintf("%d %s", statusCode, destSvc))
+}
+
+type senderStub struct {
+	err      sender.PublishError
+	ready    bool
+	url      string
+	received *ceevent.Event
+}
+
+func (s *senderStub) Send(_ context.Context, event *ceevent.Event) sender.PublishError {
+	s.received = event
+	return s.err
+}
+
+func (s *senderStub) URL() string {
+	return s.url
+}
+
+func (s *senderStub) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func (s *senderStub) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(health.StatusCodeHealthy)
+}
+
+func newEvent(t *testing.T, eventType string) *ceevent.Event {
+	t.Helper()
+	event := ce.NewEvent()
+	event.SetID("id")
+	event.SetType(eventType)
+	event.SetSource("namespace")
+	return &event
+}
+
+func newLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	l, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return l
+}
@@ -2,6 +2,7 @@ package sender
 
 import (
 	"context"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 )
@@ -16,3 +17,44 @@ type PublishError interface {
 	Code() int
 	Message() string
 }
+
+// RetryAfterError is a PublishError which knows how long the client should wait before retrying.
+type RetryAfterError interface {
+	PublishError
+	RetryAfter() time.Duration
+}
+
+// TypedError is a PublishError which classifies the error, e.g. to answer legacy requests with an error type.
+type TypedError interface {
+	PublishError
+	Type() string
+}
+
+// LatencyRecorder is a GenericSender which records the backend latency of its sends itself, e.g. per destination.
+// The latency of its sends must not be recorded again.
+type LatencyRecorder interface {
+	GenericSender
+	RecordsLatency() bool
+}
+
+// Wrapper is a GenericSender which sends the events with the GenericSender it wraps.
+type Wrapper interface {
+	GenericSender
+	Unwrap() GenericSender
+}
+
+// RecordsLatency returns true if the given sender, or one of the senders it wraps, records the backend latency of its
+// sends itself.
+func RecordsLatency(s GenericSender) bool {
+	for s != nil {
+		if recorder, ok := s.(LatencyRecorder); ok && recorder.RecordsLatency() {
+			return true
+		}
+		wrapper, ok := s.(Wrapper)
+		if !ok {
+			return false
+		}
+		s = wrapper.Unwrap()
+	}
+	return false
+}
@@ -38,15 +38,18 @@ func ConvertRuntimeObjToSubscription(sObj runtime.Object) (*emeventingv2alpha1.S
 }
 
 // GenerateSubscriptionInfFactory generates DynamicSharedInformerFactory for Subscription.
-func GenerateSubscriptionInfFactory(k8sConfig *rest.Config) dynamicinformer.DynamicSharedInformerFactory {
-	subDynamicClient := dynamic.NewForConfigOrDie(k8sConfig)
+func GenerateSubscriptionInfFactory(k8sConfig *rest.Config) (dynamicinformer.DynamicSharedInformerFactory, error) {
+	subDynamicClient, err := dynamic.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
 	dFilteredSharedInfFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(subDynamicClient,
 		informers.DefaultResyncPeriod,
 		kcorev1.NamespaceAll,
 		nil,
 	)
 	dFilteredSharedInfFactory.ForResource(SubscriptionGVR())
-	return dFilteredSharedInfFactory
+	return dFilteredSharedInfFactory, nil
 }
 
 // ConvertEventsMapToSlice converts a map of Events to a slice of Events.
@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +19,8 @@ type MockServer struct {
 	expiresInSec         int           // token expiry in seconds
 	generatedTokensCount int           // generated tokens count
 	validator            Validator     // validate the received requests form publishers
+	tokenValidator       Validator     // validate the received token requests, invalid ones are answered with 401
+	tls                  *tls.Config   // serve TLS with the given config if set
 }
 
 func NewMockServer(opts ...MockServerOption) *MockServer {
@@ -47,14 +51,33 @@ func WithValidator(validator Validator) MockServerOption {
 	}
 }
 
+func WithTokenValidator(validator Validator) MockServerOption {
+	return func(m *MockServer) {
+		m.tokenValidator = validator
+	}
+}
+
+// WithTLS serves TLS with the given config, the server certificate is generated if the config has none.
+func WithTLS(config *tls.Config) MockServerOption {
+	return func(m *MockServer) {
+		m.tls = config
+	}
+}
+
 func (m *MockServer) Start(t *testing.T, tokenEndpoint, eventsEndpoint, eventsWithHTTP400 string) {
 	t.Helper()
-	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	m.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(m.responseTime)
 
 		switch r.URL.String() {
 		case tokenEndpoint:
 			{
+				if m.tokenValidator != nil {
+					if err := m.tokenValidator(r); err != nil {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+				}
 				m.generatedTokensCount++
 				token := fmt.Sprintf("access_token=token-%d&token_type=bearer&expires_in=%d", time.Now().UnixNano(), m.expiresInSec)
 				if _, err := w.Write([]byte(token)); err != nil {
@@ -90,6 +113,12 @@ func (m *MockServer) Start(t *testing.T, tokenEndpoint, eventsEndpoint, eventsWi
 			}
 		}
 	}))
+	if m.tls == nil {
+		m.server.Start()
+		return
+	}
+	m.server.TLS = m.tls
+	m.server.StartTLS()
 }
 
 func (m *MockServer) validateRequest(r *http.Request) error {
@@ -104,6 +133,11 @@ func (m *MockServer) URL() string {
 	return m.server.URL
 }
 
+// Certificate returns the certificate of the server if it serves TLS.
+func (m *MockServer) Certificate() *x509.Certificate {
+	return m.server.Certificate()
+}
+
 func (m *MockServer) GeneratedTokensCount() int {
 	return m.generatedTokensCount
 }